@@ -12,14 +12,17 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/user/jsbug/internal/batch"
+	"github.com/user/jsbug/internal/blobstore"
 	"github.com/user/jsbug/internal/captcha"
 	"github.com/user/jsbug/internal/chrome"
 	"github.com/user/jsbug/internal/config"
+	"github.com/user/jsbug/internal/crawl"
 	"github.com/user/jsbug/internal/fetcher"
 	"github.com/user/jsbug/internal/logger"
+	"github.com/user/jsbug/internal/metrics"
 	"github.com/user/jsbug/internal/parser"
 	"github.com/user/jsbug/internal/robots"
-	"github.com/user/jsbug/internal/screenshot"
 	"github.com/user/jsbug/internal/server"
 	"github.com/user/jsbug/internal/session"
 )
@@ -58,6 +61,11 @@ func main() {
 		Timeout:           time.Duration(cfg.ChromeTimeout()) * time.Second,
 		RestartAfterCount: cfg.Chrome.RestartAfterCount,
 		RestartAfterTime:  cfg.Chrome.RestartAfterTime,
+		MinInstances:      cfg.Chrome.MinInstances,
+		MaxInstances:      cfg.Chrome.MaxInstances,
+		IdleTimeout:       cfg.Chrome.IdleTimeout,
+		QueueSize:         *cfg.Chrome.QueueSize,
+		QueueTimeout:      *cfg.Chrome.QueueTimeout,
 	}, log)
 
 	if err != nil {
@@ -90,7 +98,7 @@ func main() {
 	}
 
 	// Create screenshot store with 5-minute TTL
-	screenshotStore := screenshot.NewStore(5 * time.Minute)
+	screenshotStore := blobstore.NewStore(5 * time.Minute)
 
 	// Create a context for background cleanup that will be cancelled on shutdown
 	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
@@ -99,6 +107,14 @@ func main() {
 	// Start screenshot cleanup goroutine (runs every minute)
 	screenshotStore.StartCleanup(cleanupCtx, 1*time.Minute)
 
+	// PDFs use the same TTL store, served by /api/pdf/{id}
+	pdfStore := blobstore.NewStore(5 * time.Minute)
+	pdfStore.StartCleanup(cleanupCtx, 1*time.Minute)
+
+	// HAR documents too, served by /api/har/{id}
+	harStore := blobstore.NewStore(5 * time.Minute)
+	harStore.StartCleanup(cleanupCtx, 1*time.Minute)
+
 	// Create server (SSE manager is created internally)
 	srv := server.New(cfg, log)
 
@@ -108,26 +124,76 @@ func main() {
 		srv.SetAuthHandler(authHandler)
 	}
 
+	// Metrics collector shared by the render handlers and /metrics
+	serviceMetrics := metrics.New()
+
 	// Create and configure render handler with pool and screenshot store
 	renderHandler := server.NewRenderHandler(pool, httpFetcher, htmlParser, cfg, log, tokenManager, screenshotStore)
 	renderHandler.SetSSEManager(srv.SSEManager())
+	renderHandler.SetMetrics(serviceMetrics)
+	renderHandler.SetPDFStore(pdfStore)
+	renderHandler.SetHARStore(harStore)
 	srv.SetRenderHandler(renderHandler)
 
+	// Create and configure metrics handler
+	metricsHandler := server.NewMetricsHandler(serviceMetrics, pool, srv.SSEManager(), screenshotStore, log)
+	srv.SetMetricsHandler(metricsHandler)
+
+	// Robots checker is shared by the robots API and crawls
+	robotsChecker := robots.NewChecker(log)
+
 	// Set up external API handler (if API is enabled)
+	var batchManager *batch.Manager
+	var crawlManager *crawl.Manager
 	if cfg.API.Enabled {
 		extHandler := server.NewExtRenderHandler(renderHandler, cfg, log)
 		srv.SetExtRenderHandler(extHandler)
 		compareHandler := server.NewExtCompareHandler(renderHandler, cfg, log)
 		srv.SetExtCompareHandler(compareHandler)
+
+		// Batch jobs share the Chrome pool: render slots match the max instance count
+		batchManager = batch.NewManager(batch.Config{
+			MaxQueuedJobs:  cfg.API.Batch.MaxQueuedJobs,
+			MaxRunningJobs: cfg.API.Batch.MaxRunningJobs,
+			RenderSlots:    cfg.Chrome.MaxInstances,
+			ResultTTL:      cfg.API.Batch.ResultTTL,
+		}, log)
+		batchManager.Start(1 * time.Minute)
+		batchHandler := server.NewExtBatchHandler(renderHandler, batchManager, cfg, log)
+		srv.SetExtBatchHandler(batchHandler)
+
+		crawlManager = crawl.NewManager(crawl.ManagerConfig{
+			MaxQueuedJobs:  cfg.API.Crawl.MaxQueuedJobs,
+			MaxRunningJobs: cfg.API.Crawl.MaxRunningJobs,
+			ResultTTL:      cfg.API.Crawl.ResultTTL,
+		}, log)
+		crawlManager.Start(1 * time.Minute)
+		crawlHandler := server.NewExtCrawlHandler(renderHandler, crawlManager, robotsChecker, cfg, log)
+		srv.SetExtCrawlHandler(crawlHandler)
 		log.Info("External API enabled", zap.Int("api_keys", len(cfg.API.Keys)))
 	}
 
-	// Create and configure screenshot handler
-	screenshotHandler := server.NewScreenshotHandler(screenshotStore)
-	srv.SetScreenshotHandler(screenshotHandler)
+	// Serve stored screenshots, PDFs and HARs by ID
+	srv.SetBlobHandler(server.NewBlobHandler(screenshotStore, server.BlobOptions{
+		Prefix:      "/api/screenshot/",
+		Name:        "screenshot",
+		Disposition: "inline", // PNG, JPEG or WebP depending on the request options
+	}))
+	srv.SetBlobHandler(server.NewBlobHandler(pdfStore, server.BlobOptions{
+		Prefix:      "/api/pdf/",
+		Name:        "PDF",
+		ContentType: "application/pdf",
+		Disposition: "inline",
+	}))
+	srv.SetBlobHandler(server.NewBlobHandler(harStore, server.BlobOptions{
+		Prefix:      "/api/har/",
+		Name:        "HAR",
+		ContentType: "application/json",
+		Disposition: "attachment",
+		Extension:   ".har",
+	}))
 
 	// Create and configure robots handler
-	robotsChecker := robots.NewChecker(log)
 	robotsHandler := server.NewRobotsHandler(robotsChecker, log)
 	srv.SetRobotsHandler(robotsHandler)
 
@@ -141,7 +207,8 @@ func main() {
 	log.Info("jsbug started",
 		zap.String("host", cfg.Server.Host),
 		zap.Int("port", cfg.Server.Port),
-		zap.Int("pool_size", cfg.Chrome.PoolSize),
+		zap.Int("min_instances", cfg.Chrome.MinInstances),
+		zap.Int("max_instances", cfg.Chrome.MaxInstances),
 	)
 
 	// Wait for shutdown signal
@@ -161,7 +228,17 @@ func main() {
 		log.Error("Server shutdown error", zap.Error(err))
 	}
 
-	// 2. Then shutdown pool (in-flight renders should already be done)
+	// 2. Cancel batch jobs and crawls so their renders release Chrome instances
+	if batchManager != nil {
+		log.Info("Canceling batch jobs...")
+		batchManager.Shutdown()
+	}
+	if crawlManager != nil {
+		log.Info("Canceling crawl jobs...")
+		crawlManager.Shutdown()
+	}
+
+	// 3. Then shutdown pool (in-flight renders should already be done)
 	log.Info("Shutting down Chrome pool...")
 	if err := pool.Shutdown(); err != nil {
 		log.Error("Pool shutdown error", zap.Error(err))
@@ -0,0 +1,314 @@
+// Package batch runs asynchronous batch render jobs on a bounded worker queue.
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/jobqueue"
+	"github.com/user/jsbug/internal/types"
+)
+
+// Default manager settings
+const (
+	DefaultMaxQueuedJobs  = 16
+	DefaultMaxRunningJobs = 2
+	DefaultRenderSlots    = 4
+	DefaultMaxAttempts    = 5
+	DefaultRetryDelay     = 2 * time.Second
+	DefaultResultTTL      = 1 * time.Hour
+	DefaultMaxResultBytes = 64 << 20 // 64 MB of rendered data per job
+)
+
+// RenderFunc renders a single URL with the job's shared options
+type RenderFunc func(ctx context.Context, url string) (*types.ExtRenderData, *types.RenderError)
+
+// Config contains batch manager settings
+type Config struct {
+	MaxQueuedJobs  int           // Jobs waiting for a runner
+	MaxRunningJobs int           // Jobs processed at the same time
+	RenderSlots    int           // URLs rendered at the same time across all jobs
+	MaxAttempts    int           // Attempts per URL while the Chrome pool is exhausted
+	RetryDelay     time.Duration // Delay before retrying after POOL_EXHAUSTED
+	ResultTTL      time.Duration // How long finished jobs are kept
+	MaxResultBytes int           // JSON size of all item results kept per job
+}
+
+// job renders the URLs of a batch. items and resultBytes are guarded by mu.
+type job struct {
+	manager     *Manager
+	concurrency int
+	render      RenderFunc
+
+	mu          sync.Mutex
+	items       []types.BatchItem
+	resultBytes int
+}
+
+// Manager queues batch jobs and renders their URLs within the configured limits
+type Manager struct {
+	config Config
+	logger *zap.Logger
+	jobs   *jobqueue.Queue[*job]
+	slots  chan struct{} // Global render slots shared by all jobs
+}
+
+// NewManager creates a new Manager. Zero config values are replaced by defaults.
+// Call Start to begin processing jobs.
+func NewManager(config Config, logger *zap.Logger) *Manager {
+	if config.MaxQueuedJobs <= 0 {
+		config.MaxQueuedJobs = DefaultMaxQueuedJobs
+	}
+	if config.MaxRunningJobs <= 0 {
+		config.MaxRunningJobs = DefaultMaxRunningJobs
+	}
+	if config.RenderSlots <= 0 {
+		config.RenderSlots = DefaultRenderSlots
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = DefaultRetryDelay
+	}
+	if config.ResultTTL <= 0 {
+		config.ResultTTL = DefaultResultTTL
+	}
+	if config.MaxResultBytes <= 0 {
+		config.MaxResultBytes = DefaultMaxResultBytes
+	}
+
+	return &Manager{
+		config: config,
+		logger: logger,
+		jobs: jobqueue.New[*job](jobqueue.Config{
+			Name:           "Batch",
+			MaxQueuedJobs:  config.MaxQueuedJobs,
+			MaxRunningJobs: config.MaxRunningJobs,
+			ResultTTL:      config.ResultTTL,
+		}, logger),
+		slots: make(chan struct{}, config.RenderSlots),
+	}
+}
+
+// Start launches the job runners and the cleanup of expired jobs.
+// They stop when Shutdown is called.
+func (m *Manager) Start(cleanupInterval time.Duration) {
+	m.jobs.Start(cleanupInterval)
+
+	m.logger.Info("Batch manager started",
+		zap.Int("max_queued_jobs", m.config.MaxQueuedJobs),
+		zap.Int("max_running_jobs", m.config.MaxRunningJobs),
+		zap.Int("render_slots", m.config.RenderSlots),
+	)
+}
+
+// Shutdown cancels all queued and running jobs
+func (m *Manager) Shutdown() {
+	m.jobs.Shutdown()
+}
+
+// Submit queues a job rendering urls with render, at most concurrency at a time.
+// owner is the API key that may read and cancel the job. It returns
+// jobqueue.ErrQueueFull or jobqueue.ErrShuttingDown when the job is not queued.
+func (m *Manager) Submit(owner string, urls []string, concurrency int, render RenderFunc) (*types.ExtBatchJob, error) {
+	j := &job{
+		manager:     m,
+		concurrency: concurrency,
+		render:      render,
+		items:       make([]types.BatchItem, len(urls)),
+	}
+	for i, url := range urls {
+		j.items[i] = types.BatchItem{URL: url, Status: types.BatchItemPending}
+	}
+
+	queued, err := m.jobs.Submit(owner, j)
+	if err != nil {
+		return nil, err
+	}
+
+	m.logger.Info("Batch job queued",
+		zap.String("job_id", queued.ID),
+		zap.Int("urls", len(urls)),
+		zap.Int("concurrency", concurrency),
+	)
+
+	return snapshot(queued, false), nil
+}
+
+// Get returns a snapshot of the job, including per-URL items.
+// Returns false if the job does not exist or belongs to another owner.
+func (m *Manager) Get(id, owner string) (*types.ExtBatchJob, bool) {
+	queued, ok := m.jobs.Get(id, owner)
+	if !ok {
+		return nil, false
+	}
+	return snapshot(queued, true), true
+}
+
+// Cancel stops a queued or running job. Items not yet finished are reported
+// canceled; in-flight renders are aborted through their context.
+// Returns false if the job does not exist or belongs to another owner.
+func (m *Manager) Cancel(id, owner string) (*types.ExtBatchJob, bool) {
+	queued, ok := m.jobs.Cancel(id, owner)
+	if !ok {
+		return nil, false
+	}
+	return snapshot(queued, false), true
+}
+
+// Run renders the job's URLs, at most j.concurrency at a time and only while
+// a global render slot is free
+func (j *job) Run(ctx context.Context) {
+	m := j.manager
+	jobSlots := make(chan struct{}, j.concurrency)
+	var wg sync.WaitGroup
+
+dispatch:
+	for i := range j.items {
+		select {
+		case jobSlots <- struct{}{}:
+		case <-ctx.Done():
+			break dispatch
+		}
+		select {
+		case m.slots <- struct{}{}:
+		case <-ctx.Done():
+			<-jobSlots
+			break dispatch
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-jobSlots }()
+			defer func() { <-m.slots }()
+			j.renderItem(ctx, i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// renderItem renders item i, retrying while the Chrome pool is exhausted
+func (j *job) renderItem(ctx context.Context, i int) {
+	config := j.manager.config
+
+	j.mu.Lock()
+	j.items[i].Status = types.BatchItemRunning
+	url := j.items[i].URL
+	j.mu.Unlock()
+
+	var data *types.ExtRenderData
+	var renderErr *types.RenderError
+	attempts := 0
+	for attempts < config.MaxAttempts {
+		attempts++
+		data, renderErr = j.render(ctx, url)
+		if renderErr == nil || renderErr.Code != types.ErrPoolExhausted || attempts == config.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-time.After(config.RetryDelay):
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	// Results are kept for the TTL, so their total size per job is capped
+	size := 0
+	if renderErr == nil {
+		size = resultSize(data)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	item := &j.items[i]
+	item.Attempts = attempts
+	switch {
+	case renderErr == nil && j.resultBytes+size > config.MaxResultBytes:
+		item.Status = types.BatchItemError
+		item.Error = &types.RenderError{
+			Code:    types.ErrBatchResultTooLarge,
+			Message: fmt.Sprintf("result of %d bytes exceeds the job's result limit of %d bytes", size, config.MaxResultBytes),
+		}
+	case renderErr == nil:
+		item.Status = types.BatchItemSuccess
+		item.Data = data
+		j.resultBytes += size
+	case ctx.Err() != nil:
+		item.Status = types.BatchItemCanceled
+	default:
+		item.Status = types.BatchItemError
+		item.Error = renderErr
+	}
+}
+
+// resultSize returns the JSON size of a render result
+func resultSize(data *types.ExtRenderData) int {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return 0
+	}
+	return len(encoded)
+}
+
+// snapshot converts a queued job. Items of a finished job that never
+// completed are reported canceled.
+func snapshot(queued *jobqueue.Job[*job], includeItems bool) *types.ExtBatchJob {
+	j := queued.Work
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	items := make([]types.BatchItem, len(j.items))
+	copy(items, j.items)
+	if queued.FinishedAt != nil {
+		for i := range items {
+			if items[i].Status == types.BatchItemPending || items[i].Status == types.BatchItemRunning {
+				items[i].Status = types.BatchItemCanceled
+			}
+		}
+	}
+
+	result := &types.ExtBatchJob{
+		ID:          queued.ID,
+		Status:      queued.Status,
+		Concurrency: j.concurrency,
+		CreatedAt:   queued.CreatedAt,
+		StartedAt:   queued.StartedAt,
+		FinishedAt:  queued.FinishedAt,
+		Progress:    progress(items),
+	}
+	if includeItems {
+		result.Items = items
+	}
+	return result
+}
+
+// progress counts items by status
+func progress(items []types.BatchItem) types.BatchProgress {
+	progress := types.BatchProgress{Total: len(items)}
+	for _, item := range items {
+		switch item.Status {
+		case types.BatchItemPending:
+			progress.Pending++
+		case types.BatchItemRunning:
+			progress.Running++
+		case types.BatchItemSuccess:
+			progress.Succeeded++
+		case types.BatchItemError:
+			progress.Failed++
+		case types.BatchItemCanceled:
+			progress.Canceled++
+		}
+	}
+	return progress
+}
@@ -0,0 +1,256 @@
+package batch
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/jobqueue"
+	"github.com/user/jsbug/internal/types"
+)
+
+func newTestManager(config Config) *Manager {
+	m := NewManager(config, zap.NewNop())
+	m.Start(time.Hour)
+	return m
+}
+
+// waitForStatus polls until the job reaches status
+func waitForStatus(t *testing.T, m *Manager, id, owner, status string) *types.ExtBatchJob {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		job, ok := m.Get(id, owner)
+		if !ok {
+			t.Fatalf("Get(%q) not found", id)
+		}
+		if job.Status == status {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job status = %q, want %q", job.Status, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func successRender(ctx context.Context, url string) (*types.ExtRenderData, *types.RenderError) {
+	return &types.ExtRenderData{StatusCode: 200, FinalURL: url}, nil
+}
+
+func TestManager_CompletesJob(t *testing.T) {
+	m := newTestManager(Config{})
+	defer m.Shutdown()
+
+	urls := []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"}
+	render := func(ctx context.Context, url string) (*types.ExtRenderData, *types.RenderError) {
+		if url == "https://example.com/b" {
+			return nil, &types.RenderError{Code: types.ErrRenderFailed, Message: "boom"}
+		}
+		return successRender(ctx, url)
+	}
+
+	submitted, err := m.Submit("key", urls, 2, render)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if submitted.Items != nil {
+		t.Error("Submit() snapshot should not include items")
+	}
+
+	job := waitForStatus(t, m, submitted.ID, "key", types.JobStatusCompleted)
+
+	want := types.BatchProgress{Total: 3, Succeeded: 2, Failed: 1}
+	if job.Progress != want {
+		t.Errorf("Progress = %+v, want %+v", job.Progress, want)
+	}
+	if job.StartedAt == nil || job.FinishedAt == nil {
+		t.Error("StartedAt and FinishedAt should be set")
+	}
+	if len(job.Items) != 3 {
+		t.Fatalf("len(Items) = %d, want 3", len(job.Items))
+	}
+	if job.Items[0].Status != types.BatchItemSuccess || job.Items[0].Data.FinalURL != urls[0] {
+		t.Errorf("Items[0] = %+v, want success for %s", job.Items[0], urls[0])
+	}
+	if job.Items[1].Status != types.BatchItemError || job.Items[1].Error.Code != types.ErrRenderFailed {
+		t.Errorf("Items[1] = %+v, want RENDER_FAILED error", job.Items[1])
+	}
+}
+
+func TestManager_OwnerIsolation(t *testing.T) {
+	m := newTestManager(Config{})
+	defer m.Shutdown()
+
+	job, err := m.Submit("owner-key", []string{"https://example.com"}, 1, successRender)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	if _, ok := m.Get(job.ID, "other-key"); ok {
+		t.Error("Get() with another key should not find the job")
+	}
+	if _, ok := m.Cancel(job.ID, "other-key"); ok {
+		t.Error("Cancel() with another key should not find the job")
+	}
+	if _, ok := m.Get("missing", "owner-key"); ok {
+		t.Error("Get() for unknown ID should return false")
+	}
+}
+
+func TestManager_Concurrency(t *testing.T) {
+	m := newTestManager(Config{RenderSlots: 8})
+	defer m.Shutdown()
+
+	var inFlight, maxInFlight atomic.Int32
+	render := func(ctx context.Context, url string) (*types.ExtRenderData, *types.RenderError) {
+		n := inFlight.Add(1)
+		for {
+			peak := maxInFlight.Load()
+			if n <= peak || maxInFlight.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		inFlight.Add(-1)
+		return successRender(ctx, url)
+	}
+
+	urls := make([]string, 8)
+	for i := range urls {
+		urls[i] = "https://example.com/"
+	}
+	job, err := m.Submit("key", urls, 2, render)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	waitForStatus(t, m, job.ID, "key", types.JobStatusCompleted)
+
+	if got := maxInFlight.Load(); got != 2 {
+		t.Errorf("max in-flight renders = %d, want job concurrency 2", got)
+	}
+}
+
+func TestManager_RetriesPoolExhausted(t *testing.T) {
+	m := newTestManager(Config{MaxAttempts: 3, RetryDelay: time.Millisecond})
+	defer m.Shutdown()
+
+	var calls atomic.Int32
+	render := func(ctx context.Context, url string) (*types.ExtRenderData, *types.RenderError) {
+		if calls.Add(1) < 3 {
+			return nil, &types.RenderError{Code: types.ErrPoolExhausted, Message: "busy"}
+		}
+		return successRender(ctx, url)
+	}
+
+	submitted, err := m.Submit("key", []string{"https://example.com"}, 1, render)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	job := waitForStatus(t, m, submitted.ID, "key", types.JobStatusCompleted)
+
+	if job.Items[0].Status != types.BatchItemSuccess || job.Items[0].Attempts != 3 {
+		t.Errorf("Items[0] = %+v, want success after 3 attempts", job.Items[0])
+	}
+}
+
+func TestManager_MaxResultBytes(t *testing.T) {
+	one := resultSize(&types.ExtRenderData{StatusCode: 200, FinalURL: "https://example.com/a"})
+	m := newTestManager(Config{MaxResultBytes: one + one/2})
+	defer m.Shutdown()
+
+	urls := []string{"https://example.com/a", "https://example.com/b"}
+	submitted, err := m.Submit("key", urls, 1, successRender)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	job := waitForStatus(t, m, submitted.ID, "key", types.JobStatusCompleted)
+
+	if job.Items[0].Status != types.BatchItemSuccess {
+		t.Errorf("Items[0] = %+v, want success within the limit", job.Items[0])
+	}
+	item := job.Items[1]
+	if item.Status != types.BatchItemError || item.Data != nil || item.Error == nil || item.Error.Code != types.ErrBatchResultTooLarge {
+		t.Errorf("Items[1] = %+v, want BATCH_RESULT_TOO_LARGE without data", item)
+	}
+}
+
+func TestManager_Cancel(t *testing.T) {
+	m := newTestManager(Config{})
+	defer m.Shutdown()
+
+	started := make(chan struct{})
+	var once sync.Once
+	render := func(ctx context.Context, url string) (*types.ExtRenderData, *types.RenderError) {
+		once.Do(func() { close(started) })
+		<-ctx.Done()
+		return nil, &types.RenderError{Code: types.ErrRenderFailed, Message: "canceled"}
+	}
+
+	submitted, err := m.Submit("key", []string{"https://example.com/a", "https://example.com/b"}, 1, render)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-started
+
+	if _, ok := m.Cancel(submitted.ID, "key"); !ok {
+		t.Fatal("Cancel() returned false")
+	}
+	job := waitForStatus(t, m, submitted.ID, "key", types.JobStatusCanceled)
+
+	want := types.BatchProgress{Total: 2, Canceled: 2}
+	if job.Progress != want {
+		t.Errorf("Progress = %+v, want %+v", job.Progress, want)
+	}
+}
+
+func TestManager_QueueFull(t *testing.T) {
+	m := NewManager(Config{MaxQueuedJobs: 1}, zap.NewNop())
+	defer m.Shutdown()
+	// Not started: jobs stay queued
+
+	first, err := m.Submit("key", []string{"https://example.com"}, 1, successRender)
+	if err != nil {
+		t.Fatalf("first Submit() error = %v", err)
+	}
+	if _, err := m.Submit("key", []string{"https://example.com"}, 1, successRender); err != jobqueue.ErrQueueFull {
+		t.Errorf("second Submit() error = %v, want ErrQueueFull", err)
+	}
+
+	// Canceling a queued job finishes it immediately
+	job, ok := m.Cancel(first.ID, "key")
+	if !ok || job.Status != types.JobStatusCanceled || job.Progress.Canceled != 1 {
+		t.Errorf("Cancel() = %+v, %v, want canceled job", job, ok)
+	}
+}
+
+func TestManager_Cleanup(t *testing.T) {
+	m := newTestManager(Config{ResultTTL: time.Millisecond})
+	defer m.Shutdown()
+
+	job, err := m.Submit("key", []string{"https://example.com"}, 1, successRender)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	waitForStatus(t, m, job.ID, "key", types.JobStatusCompleted)
+
+	time.Sleep(5 * time.Millisecond)
+	m.jobs.Cleanup()
+
+	if _, ok := m.Get(job.ID, "key"); ok {
+		t.Error("expired job should be removed by cleanup")
+	}
+}
+
+func TestManager_SubmitAfterShutdown(t *testing.T) {
+	m := newTestManager(Config{})
+	m.Shutdown()
+
+	if _, err := m.Submit("key", []string{"https://example.com"}, 1, successRender); err != jobqueue.ErrShuttingDown {
+		t.Errorf("Submit() error = %v, want ErrShuttingDown", err)
+	}
+}
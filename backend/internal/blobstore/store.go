@@ -1,4 +1,6 @@
-package screenshot
+// Package blobstore keeps rendered artifacts (screenshots, PDFs, HARs) in
+// memory for a limited time so they can be served by ID.
+package blobstore
 
 import (
 	"context"
@@ -8,36 +10,36 @@ import (
 	"github.com/google/uuid"
 )
 
-// DefaultTTL is the default time-to-live for screenshots
+// DefaultTTL is the default time-to-live for blobs
 const DefaultTTL = 5 * time.Minute
 
-// entry holds screenshot data with creation timestamp
+// entry holds blob data with creation timestamp
 type entry struct {
 	data      []byte
 	createdAt time.Time
 }
 
-// ScreenshotStore provides thread-safe in-memory storage for screenshots with TTL
-type ScreenshotStore struct {
+// Store provides thread-safe in-memory storage for blobs with TTL
+type Store struct {
 	mu      sync.RWMutex
 	entries map[string]*entry
 	ttl     time.Duration
 }
 
-// NewStore creates a new ScreenshotStore with the specified TTL
+// NewStore creates a new Store with the specified TTL
 // If ttl is 0, DefaultTTL is used
-func NewStore(ttl time.Duration) *ScreenshotStore {
+func NewStore(ttl time.Duration) *Store {
 	if ttl == 0 {
 		ttl = DefaultTTL
 	}
-	return &ScreenshotStore{
+	return &Store{
 		entries: make(map[string]*entry),
 		ttl:     ttl,
 	}
 }
 
-// Store saves screenshot data and returns a unique ID
-func (s *ScreenshotStore) Store(data []byte) string {
+// Store saves blob data and returns a unique ID
+func (s *Store) Store(data []byte) string {
 	id := uuid.New().String()
 	e := &entry{
 		data:      data,
@@ -51,9 +53,9 @@ func (s *ScreenshotStore) Store(data []byte) string {
 	return id
 }
 
-// Get retrieves screenshot data by ID
+// Get retrieves blob data by ID
 // Returns nil, false if not found or expired
-func (s *ScreenshotStore) Get(id string) ([]byte, bool) {
+func (s *Store) Get(id string) ([]byte, bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -71,8 +73,20 @@ func (s *ScreenshotStore) Get(id string) ([]byte, bool) {
 	return e.data, true
 }
 
+// Stats returns the number of stored blobs and their total size in bytes.
+// Expired entries not yet removed by cleanup are included.
+func (s *Store) Stats() (count int, bytes int64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, e := range s.entries {
+		bytes += int64(len(e.data))
+	}
+	return len(s.entries), bytes
+}
+
 // cleanup removes all expired entries
-func (s *ScreenshotStore) cleanup() {
+func (s *Store) cleanup() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -85,7 +99,7 @@ func (s *ScreenshotStore) cleanup() {
 
 // StartCleanup starts a background goroutine that periodically removes expired entries
 // The cleanup stops when ctx is cancelled
-func (s *ScreenshotStore) StartCleanup(ctx context.Context, interval time.Duration) {
+func (s *Store) StartCleanup(ctx context.Context, interval time.Duration) {
 	go func() {
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
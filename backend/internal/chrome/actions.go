@@ -0,0 +1,196 @@
+package chrome
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	cdpruntime "github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"github.com/chromedp/chromedp/kb"
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+// actionKeys maps press_key names to chromedp key runes
+var actionKeys = map[string]string{
+	"Enter":      kb.Enter,
+	"Escape":     kb.Escape,
+	"Tab":        kb.Tab,
+	"Backspace":  kb.Backspace,
+	"Delete":     kb.Delete,
+	"Space":      " ",
+	"ArrowUp":    kb.ArrowUp,
+	"ArrowDown":  kb.ArrowDown,
+	"ArrowLeft":  kb.ArrowLeft,
+	"ArrowRight": kb.ArrowRight,
+	"Home":       kb.Home,
+	"End":        kb.End,
+	"PageUp":     kb.PageUp,
+	"PageDown":   kb.PageDown,
+}
+
+// runActions runs the scripted page actions after the wait event. Actions get
+// whatever is left of the render timeout. A failed action skips the rest
+// unless it is optional; the render continues with HTML extraction either way.
+func (r *RendererV2) runActions(opts RenderOptions, state *renderState) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if len(opts.Actions) == 0 {
+			return nil
+		}
+
+		budgetCtx, cancel := state.budget(ctx)
+		defer cancel()
+
+		results := make([]types.ActionResult, len(opts.Actions))
+		stopped := false
+		for i, action := range opts.Actions {
+			results[i] = types.ActionResult{Index: i, Type: action.Type, Status: types.ActionStatusSkipped}
+			if stopped {
+				continue
+			}
+
+			start := time.Now()
+			value, err := r.runAction(budgetCtx, action)
+			results[i].Duration = time.Since(start).Seconds()
+
+			// Hard timeout or cancellation: stop the render
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			if err != nil {
+				results[i].Status = types.ActionStatusError
+				results[i].Error = err.Error()
+				if budgetCtx.Err() != nil {
+					results[i].Error = "actions timeout exceeded"
+				}
+				r.logger.Debug("Page action failed",
+					zap.String("url", opts.URL),
+					zap.Int("index", i),
+					zap.String("type", action.Type),
+					zap.Error(err))
+				stopped = !action.Optional || budgetCtx.Err() != nil
+				continue
+			}
+
+			results[i].Status = types.ActionStatusSuccess
+			results[i].Result = value
+		}
+
+		state.mu.Lock()
+		state.actions = results
+		state.mu.Unlock()
+
+		return nil
+	}
+}
+
+// runAction runs a single action. Only evaluate returns a value.
+func (r *RendererV2) runAction(ctx context.Context, action types.Action) (json.RawMessage, error) {
+	if action.Type == types.ActionWaitMs {
+		select {
+		case <-time.After(time.Duration(action.Ms) * time.Millisecond):
+			return nil, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	timeout := time.Duration(action.Timeout()) * time.Millisecond
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var err error
+	switch action.Type {
+	case types.ActionEvaluate:
+		value, evalErr := evaluateAction(stepCtx, action.Script)
+		if errors.Is(evalErr, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("evaluate timed out after %dms", action.Timeout())
+		}
+		return value, evalErr
+	case types.ActionClick:
+		err = chromedp.Click(action.Selector, chromedp.ByQuery, chromedp.NodeVisible).Do(stepCtx)
+	case types.ActionType:
+		err = chromedp.SendKeys(action.Selector, action.Text, chromedp.ByQuery, chromedp.NodeVisible).Do(stepCtx)
+	case types.ActionWaitForSelector:
+		err = r.waitForCondition(stepCtx, types.WaitCondition{Kind: types.WaitKindSelector, Value: action.Selector}, timeout)
+	case types.ActionScroll:
+		err = scroll(stepCtx, action)
+	case types.ActionPressKey:
+		err = pressKey(stepCtx, action)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, errWaitTimeout) {
+		return nil, fmt.Errorf("%s timed out after %dms", action.Type, action.Timeout())
+	}
+	return nil, err
+}
+
+// scroll scrolls an element into view, or the window by x/y pixels.
+// Without a selector or offset it scrolls one viewport down.
+func scroll(ctx context.Context, action types.Action) error {
+	if action.Selector != "" {
+		return chromedp.ScrollIntoView(action.Selector, chromedp.ByQuery).Do(ctx)
+	}
+
+	expression := fmt.Sprintf("window.scrollBy(%d, %d)", action.X, action.Y)
+	if action.X == 0 && action.Y == 0 {
+		expression = "window.scrollBy(0, window.innerHeight)"
+	}
+	_, exception, err := cdpruntime.Evaluate(expression).Do(ctx)
+	if err != nil {
+		return err
+	}
+	if exception != nil {
+		return fmt.Errorf("scroll failed: %s", exceptionMessage(exception))
+	}
+	return nil
+}
+
+// pressKey dispatches a key press, focusing the selector first if set
+func pressKey(ctx context.Context, action types.Action) error {
+	if action.Selector != "" {
+		if err := chromedp.Focus(action.Selector, chromedp.ByQuery).Do(ctx); err != nil {
+			return err
+		}
+	}
+	key, ok := actionKeys[action.Key]
+	if !ok {
+		key = action.Key
+	}
+	return chromedp.KeyEvent(key).Do(ctx)
+}
+
+// evaluateAction runs a script and returns its JSON-encoded result.
+// Promises are awaited.
+func evaluateAction(ctx context.Context, script string) (json.RawMessage, error) {
+	result, exception, err := cdpruntime.Evaluate(script).
+		WithReturnByValue(true).
+		WithAwaitPromise(true).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if exception != nil {
+		return nil, fmt.Errorf("script error: %s", exceptionMessage(exception))
+	}
+	if result == nil || len(result.Value) == 0 {
+		return nil, nil
+	}
+	if len(result.Value) > types.MaxActionResultBytes {
+		return nil, fmt.Errorf("result exceeds %d bytes", types.MaxActionResultBytes)
+	}
+	return json.RawMessage(result.Value), nil
+}
+
+// exceptionMessage returns the most descriptive message of a JS exception
+func exceptionMessage(exception *cdpruntime.ExceptionDetails) string {
+	if exception.Exception != nil && exception.Exception.Description != "" {
+		return exception.Exception.Description
+	}
+	return exception.Text
+}
@@ -0,0 +1,201 @@
+package chrome
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Reaper tick bounds
+const (
+	minReapInterval = 1 * time.Second
+	maxReapInterval = 30 * time.Second
+)
+
+// On-demand spawn retries
+const (
+	maxSpawnAttempts = 2
+	spawnRetryDelay  = 250 * time.Millisecond
+)
+
+// liveCountLocked returns the number of started instances.
+// Must be called with waitMu held.
+func (p *ChromePool) liveCountLocked() int {
+	count := 0
+	for _, instance := range p.instances {
+		if instance != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// canSpawnLocked reports whether the pool is below MaxInstances with a free
+// slot. Must be called with waitMu held.
+func (p *ChromePool) canSpawnLocked() bool {
+	return p.freeSlotLocked() >= 0
+}
+
+// freeSlotLocked returns a slot for a new instance, or -1 when the pool is at
+// MaxInstances. Must be called with waitMu held.
+func (p *ChromePool) freeSlotLocked() int {
+	if p.liveCountLocked()+len(p.spawning) >= p.config.MaxInstances {
+		return -1
+	}
+	for i, instance := range p.instances {
+		if instance == nil && !p.spawning[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+// maybeSpawnLocked starts a new instance in the background when requests are
+// queued and the pool is below MaxInstances. At most one instance is spawned
+// per queued request. Must be called with waitMu held.
+func (p *ChromePool) maybeSpawnLocked() {
+	if len(p.spawning) >= len(p.waiters) {
+		return
+	}
+	id := p.freeSlotLocked()
+	if id < 0 {
+		return
+	}
+
+	if p.spawning == nil {
+		p.spawning = make(map[int]bool)
+	}
+	p.spawning[id] = true
+
+	go p.spawnInstance(id)
+}
+
+// spawnInstance starts an instance in slot id and hands it to the queue
+func (p *ChromePool) spawnInstance(id int) {
+	p.logger.Info("Spawning Chrome instance on demand",
+		zap.Int("instance_id", id),
+	)
+
+	newInstance := p.newInstance
+	if newInstance == nil {
+		newInstance = func(id int) (*Instance, error) {
+			return New(id, p.config, p.logger)
+		}
+	}
+	instance, err := newInstance(id)
+	// Retry while requests still wait for it
+	for attempt := 1; err != nil && attempt < maxSpawnAttempts && p.queueLength() > 0; attempt++ {
+		p.logger.Warn("Failed to spawn Chrome instance, retrying",
+			zap.Int("instance_id", id),
+			zap.Error(err),
+		)
+		select {
+		case <-p.ctx.Done():
+			attempt = maxSpawnAttempts
+		case <-time.After(spawnRetryDelay):
+			instance, err = newInstance(id)
+		}
+	}
+
+	p.waitMu.Lock()
+	delete(p.spawning, id)
+
+	if err != nil {
+		failed := p.failOverflowLocked()
+		p.waitMu.Unlock()
+		for _, w := range failed {
+			w.ch <- -1
+		}
+		p.logger.Error("Failed to spawn Chrome instance",
+			zap.Int("instance_id", id),
+			zap.Int("failed_requests", len(failed)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	// Pool shut down while the instance was starting
+	if p.ctx.Err() != nil {
+		p.waitMu.Unlock()
+		instance.Terminate()
+		return
+	}
+
+	p.instances[id] = instance
+	p.spawnedTotal++
+	p.waitMu.Unlock()
+
+	p.returnToPool(id)
+}
+
+// failOverflowLocked dequeues the newest waiters beyond QueueSize that no
+// running spawn will serve. They were queued only for an instance that
+// failed to start. Must be called with waitMu held.
+func (p *ChromePool) failOverflowLocked() []*waiter {
+	overflow := len(p.waiters) - p.config.QueueSize - len(p.spawning)
+	if overflow <= 0 {
+		return nil
+	}
+	cut := len(p.waiters) - overflow
+	failed := append([]*waiter(nil), p.waiters[cut:]...)
+	p.waiters = p.waiters[:cut]
+	return failed
+}
+
+// reapLoop periodically terminates instances idle longer than IdleTimeout
+func (p *ChromePool) reapLoop() {
+	interval := p.config.IdleTimeout / 2
+	if interval < minReapInterval {
+		interval = minReapInterval
+	}
+	if interval > maxReapInterval {
+		interval = maxReapInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.reapIdle()
+		}
+	}
+}
+
+// reapIdle terminates available instances idle longer than IdleTimeout while
+// keeping at least MinInstances
+func (p *ChromePool) reapIdle() {
+	p.waitMu.Lock()
+
+	var reaped []*Instance
+	live := p.liveCountLocked()
+	for n := len(p.available); n > 0; n-- {
+		id := <-p.available
+		if live > p.config.MinInstances && time.Since(p.idleSince[id]) >= p.config.IdleTimeout {
+			reaped = append(reaped, p.instances[id])
+			p.instances[id] = nil
+			delete(p.idleSince, id)
+			p.reapedTotal++
+			live--
+			continue
+		}
+		p.available <- id
+	}
+
+	p.waitMu.Unlock()
+
+	for _, instance := range reaped {
+		p.logger.Info("Reaping idle Chrome instance",
+			zap.Int("instance_id", instance.ID()),
+		)
+		if err := instance.Terminate(); err != nil {
+			p.logger.Error("Failed to terminate idle instance",
+				zap.Int("instance_id", instance.ID()),
+				zap.Error(err),
+			)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package chrome
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+// maxTouchPoints reported by navigator.maxTouchPoints on touch devices
+const maxTouchPoints = 5
+
+var chromeVersionPattern = regexp.MustCompile(`Chrome/(\d+)\.`)
+
+// emulateDevice sets the user agent and Accept-Language, viewport, touch
+// support and platform hints. Without opts.Device the viewport is a desktop or
+// phone by IsMobile.
+func (r *RendererV2) emulateDevice(opts RenderOptions) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		device := opts.Device
+		if device == nil {
+			device = &types.Device{Width: DesktopWidth, Height: DesktopHeight, DeviceScaleFactor: 1, Mobile: opts.IsMobile}
+			if opts.IsMobile {
+				device.Width, device.Height = MobileWidth, MobileHeight
+			}
+		}
+
+		userAgent := opts.UserAgent
+		acceptLanguage := types.AcceptLanguage(opts.Locale)
+		if userAgent == "" && acceptLanguage != "" {
+			// Accept-Language can only be overridden together with the user agent
+			_, _, _, ua, _, err := browser.GetVersion().Do(ctx)
+			if err != nil {
+				return fmt.Errorf("get browser user agent failed: %w", err)
+			}
+			userAgent = ua
+		}
+
+		if userAgent != "" {
+			override := emulation.SetUserAgentOverride(userAgent)
+			if acceptLanguage != "" {
+				override = override.WithAcceptLanguage(acceptLanguage)
+			}
+			if device.Platform != "" {
+				override = override.WithPlatform(device.Platform)
+			}
+			if metadata := userAgentMetadata(userAgent, device); metadata != nil {
+				override = override.WithUserAgentMetadata(metadata)
+			}
+			if err := override.Do(ctx); err != nil {
+				return err
+			}
+		}
+
+		if err := emulation.SetDeviceMetricsOverride(
+			int64(device.Width),
+			int64(device.Height),
+			device.DeviceScaleFactor,
+			device.Mobile,
+		).Do(ctx); err != nil {
+			return err
+		}
+
+		if device.Touch {
+			if err := emulation.SetTouchEmulationEnabled(true).WithMaxTouchPoints(maxTouchPoints).Do(ctx); err != nil {
+				return fmt.Errorf("enable touch emulation failed: %w", err)
+			}
+		}
+		return nil
+	}
+}
+
+// userAgentMetadata builds the client hints for a Chromium user agent, nil
+// when the device has none or the user agent is not Chrome
+func userAgentMetadata(userAgent string, device *types.Device) *emulation.UserAgentMetadata {
+	hints := device.ClientHints
+	if hints == nil {
+		return nil
+	}
+	match := chromeVersionPattern.FindStringSubmatch(userAgent)
+	if match == nil {
+		return nil
+	}
+	major := match[1]
+	return &emulation.UserAgentMetadata{
+		Brands: []*emulation.UserAgentBrandVersion{
+			{Brand: "Not_A Brand", Version: "8"},
+			{Brand: "Chromium", Version: major},
+			{Brand: "Google Chrome", Version: major},
+		},
+		Platform:        hints.Platform,
+		PlatformVersion: hints.PlatformVersion,
+		Architecture:    hints.Architecture,
+		Model:           hints.Model,
+		Mobile:          device.Mobile,
+	}
+}
@@ -0,0 +1,36 @@
+package chrome
+
+import (
+	"testing"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+func TestUserAgentMetadata(t *testing.T) {
+	pixel := types.DeviceProfiles[types.DevicePixel8]
+
+	metadata := userAgentMetadata(pixel.UserAgent, &pixel)
+	if metadata == nil {
+		t.Fatal("userAgentMetadata() = nil, want client hints")
+	}
+	if metadata.Platform != "Android" || metadata.Model != "Pixel 8" || !metadata.Mobile {
+		t.Errorf("userAgentMetadata() = %+v", metadata)
+	}
+	found := false
+	for _, b := range metadata.Brands {
+		if b.Brand == "Google Chrome" && b.Version == "120" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Brands = %v, want Google Chrome 120", metadata.Brands)
+	}
+
+	iphone := types.DeviceProfiles[types.DeviceIPhone15]
+	if got := userAgentMetadata(iphone.UserAgent, &iphone); got != nil {
+		t.Errorf("userAgentMetadata() for Safari = %+v, want nil", got)
+	}
+	if got := userAgentMetadata(types.UserAgentPresets[types.UserAgentFirefox], &pixel); got != nil {
+		t.Errorf("userAgentMetadata() for a non-Chrome user agent = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,68 @@
+package chrome
+
+import (
+	"context"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+// dialogTimeout bounds answering a single dialog
+const dialogTimeout = 2 * time.Second
+
+// handleDialog answers a JavaScript dialog per opts.DialogAction and records
+// it. An unanswered alert() or confirm() would stall the page until the hard
+// timeout.
+func (r *RendererV2) handleDialog(ctx context.Context, opts RenderOptions, state *renderState, ev *page.EventJavascriptDialogOpening, timeOrigin int64) {
+	action := opts.DialogAction
+	if action == "" {
+		action = types.DefaultDialogAction
+	}
+	accept := action == types.DialogActionAccept
+
+	state.mu.Lock()
+	if len(state.dialogs) < types.MaxDialogs {
+		state.dialogs = append(state.dialogs, types.Dialog{
+			Type:    string(ev.Type),
+			Message: truncateRunes(ev.Message, types.MaxDialogMessageLength),
+			URL:     ev.URL,
+			Action:  action,
+			Time:    float64(time.Now().UnixMilli()-timeOrigin) / 1000.0,
+		})
+	}
+	state.mu.Unlock()
+
+	cmdCtx, cancel := context.WithTimeout(ctx, dialogTimeout)
+	defer cancel()
+
+	params := page.HandleJavaScriptDialog(accept)
+	if accept && ev.Type == page.DialogTypePrompt {
+		promptText := opts.DialogPromptText
+		if promptText == "" {
+			promptText = ev.DefaultPrompt
+		}
+		params = params.WithPromptText(promptText)
+	}
+
+	executor := cdp.WithExecutor(cmdCtx, chromedp.FromContext(cmdCtx).Target)
+	if err := params.Do(executor); err != nil {
+		r.logger.Warn("Failed to handle JavaScript dialog",
+			zap.String("url", opts.URL),
+			zap.String("type", string(ev.Type)),
+			zap.Error(err))
+	}
+}
+
+// truncateRunes cuts s to at most n characters
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
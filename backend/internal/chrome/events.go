@@ -2,6 +2,7 @@ package chrome
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"net/url"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/fetch"
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/cdproto/page"
@@ -32,8 +34,30 @@ type NetworkRequestData struct {
 	StartTime     time.Time
 	EndTime       time.Time
 	Blocked       bool
+	BlockedReason string
 	Failed        bool
 	FailureReason string
+
+	// Details kept for the HAR export
+	RequestHeaders  network.Headers
+	PostData        string
+	WallTime        time.Time // Wall clock time the request was sent
+	SentAt          time.Time // CDP monotonic timestamps, comparable with Timing.RequestTime
+	FinishedAt      time.Time
+	StatusText      string
+	Protocol        string
+	MimeType        string
+	ResponseHeaders network.Headers
+	RemoteIP        string
+	RemotePort      int
+	FromCache       bool
+	Timing          *network.ResourceTiming
+	RedirectURL     string // Location of a redirect hop
+	Body            string
+	BodyBase64      bool
+	BodyComment     string // Why the body was left out
+
+	ResponseBody *types.ResponseBody // Captured XHR/fetch body, see RenderOptions.ResponseBodies
 }
 
 // ConsoleMessageData holds data for a console message
@@ -71,6 +95,13 @@ type EventCollector struct {
 	// Redirect tracking
 	redirectURL    string
 	redirectStatus int
+	mainRequestID  string              // Request ID of the main document (kept across redirect hops)
+	redirectChain  []types.RedirectHop // Redirect hops of the main document
+	hopStart       time.Time           // Time the current main document hop was requested
+
+	// Redirect hops of every request, in order. Chrome reuses the request ID
+	// for each hop, so the hops are moved out of networkRequests.
+	redirectedRequests []*NetworkRequestData
 
 	// Fetch handler tracking
 	fetchHandlerCount int64
@@ -167,12 +198,54 @@ func (ec *EventCollector) handleRequestWillBeSent(e *network.EventRequestWillBeS
 	defer ec.mu.Unlock()
 
 	reqID := string(e.RequestID)
-	ec.networkRequests[reqID] = &NetworkRequestData{
-		RequestID:    reqID,
-		URL:          e.Request.URL,
-		Method:       e.Request.Method,
-		ResourceType: e.Type.String(),
-		StartTime:    time.Now(),
+	if prev, ok := ec.networkRequests[reqID]; ok && e.RedirectResponse != nil {
+		prev.applyResponse(e.RedirectResponse)
+		prev.RedirectURL = e.Request.URL
+		prev.EndTime = time.Now()
+		if e.Timestamp != nil {
+			prev.FinishedAt = e.Timestamp.Time()
+		}
+		ec.redirectedRequests = append(ec.redirectedRequests, prev)
+	}
+
+	req := &NetworkRequestData{
+		RequestID:      reqID,
+		URL:            e.Request.URL,
+		Method:         e.Request.Method,
+		ResourceType:   e.Type.String(),
+		StartTime:      time.Now(),
+		RequestHeaders: e.Request.Headers,
+		PostData:       postDataText(e.Request.PostDataEntries),
+		WallTime:       time.Now(),
+	}
+	if e.WallTime != nil {
+		req.WallTime = e.WallTime.Time()
+	}
+	if e.Timestamp != nil {
+		req.SentAt = e.Timestamp.Time()
+	}
+	ec.networkRequests[reqID] = req
+
+	// Track the main document request and its redirect hops. Chrome reuses
+	// the request ID for every hop of a redirected navigation.
+	if e.Type == network.ResourceTypeDocument {
+		sentAt := time.Now()
+		if e.Timestamp != nil {
+			sentAt = e.Timestamp.Time()
+		}
+		switch {
+		case ec.mainRequestID == "" && e.RedirectResponse == nil && urlsMatchIgnoringFragment(e.Request.URL, ec.pageURL):
+			ec.mainRequestID = reqID
+			ec.hopStart = sentAt
+		case reqID == ec.mainRequestID && e.RedirectResponse != nil:
+			ec.redirectChain = append(ec.redirectChain, types.RedirectHop{
+				URL:        e.RedirectResponse.URL,
+				StatusCode: int(e.RedirectResponse.Status),
+				Location:   e.Request.URL,
+				Time:       sentAt.Sub(ec.hopStart).Seconds(),
+			})
+			ec.hopStart = sentAt
+		}
 	}
 
 	// Capture redirect information
@@ -191,7 +264,7 @@ func (ec *EventCollector) handleResponseReceived(e *network.EventResponseReceive
 
 	reqID := string(e.RequestID)
 	if req, ok := ec.networkRequests[reqID]; ok {
-		req.Status = int(e.Response.Status)
+		req.applyResponse(e.Response)
 		// Capture size from response (may be partial, but good fallback for cached)
 		if e.Response.EncodedDataLength > 0 {
 			req.SizeBytes = int64(e.Response.EncodedDataLength)
@@ -210,6 +283,9 @@ func (ec *EventCollector) handleLoadingFinished(e *network.EventLoadingFinished)
 	reqID := string(e.RequestID)
 	if req, ok := ec.networkRequests[reqID]; ok {
 		req.EndTime = time.Now()
+		if e.Timestamp != nil {
+			req.FinishedAt = e.Timestamp.Time()
+		}
 		// LoadingFinished has the authoritative final size
 		if e.EncodedDataLength > 0 {
 			req.SizeBytes = int64(e.EncodedDataLength)
@@ -226,7 +302,38 @@ func (ec *EventCollector) handleLoadingFailed(e *network.EventLoadingFailed) {
 		req.Failed = true
 		req.FailureReason = e.ErrorText
 		req.EndTime = time.Now()
+		if e.Timestamp != nil {
+			req.FinishedAt = e.Timestamp.Time()
+		}
+	}
+}
+
+// applyResponse records the response details of a request or redirect hop
+func (req *NetworkRequestData) applyResponse(resp *network.Response) {
+	req.Status = int(resp.Status)
+	req.StatusText = resp.StatusText
+	req.Protocol = resp.Protocol
+	req.MimeType = resp.MimeType
+	req.ResponseHeaders = resp.Headers
+	req.RemoteIP = resp.RemoteIPAddress
+	req.RemotePort = int(resp.RemotePort)
+	req.FromCache = resp.FromDiskCache || resp.FromPrefetchCache || resp.FromServiceWorker
+	req.Timing = resp.Timing
+	if len(resp.RequestHeaders) > 0 {
+		// Headers actually sent on the wire, including cookies
+		req.RequestHeaders = resp.RequestHeaders
+	}
+}
+
+// postDataText joins the decoded request body entries
+func postDataText(entries []*network.PostDataEntry) string {
+	var b strings.Builder
+	for _, entry := range entries {
+		if data, err := base64.StdEncoding.DecodeString(entry.Bytes); err == nil {
+			b.Write(data)
+		}
 	}
+	return b.String()
 }
 
 func (ec *EventCollector) handleDataReceived(e *network.EventDataReceived) {
@@ -245,21 +352,7 @@ func (ec *EventCollector) handleRequestPaused(ctx context.Context, e *fetch.Even
 	resourceType := e.ResourceType.String()
 
 	if blocklist != nil && blocklist.ShouldBlock(reqURL, resourceType) {
-		ec.mu.Lock()
-		// Mark as blocked in network requests
-		if req, ok := ec.networkRequests[string(e.NetworkID)]; ok {
-			req.Blocked = true
-		} else {
-			ec.networkRequests[string(e.NetworkID)] = &NetworkRequestData{
-				RequestID:    string(e.NetworkID),
-				URL:          reqURL,
-				ResourceType: resourceType,
-				Blocked:      true,
-				StartTime:    time.Now(),
-				EndTime:      time.Now(),
-			}
-		}
-		ec.mu.Unlock()
+		ec.markBlocked(string(e.NetworkID), reqURL, resourceType, types.BlockedReasonBlocklist)
 
 		// Fail the request - track goroutine for completion
 		atomic.AddInt64(&ec.fetchHandlerCount, 1)
@@ -277,6 +370,30 @@ func (ec *EventCollector) handleRequestPaused(ctx context.Context, e *fetch.Even
 	}
 }
 
+// markBlocked records a request as blocked, creating an entry if the request
+// was intercepted before Network.requestWillBeSent was seen
+func (ec *EventCollector) markBlocked(networkID, reqURL, resourceType, reason string) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
+	if req, ok := ec.networkRequests[networkID]; ok {
+		req.Blocked = true
+		req.BlockedReason = reason
+		return
+	}
+
+	now := time.Now()
+	ec.networkRequests[networkID] = &NetworkRequestData{
+		RequestID:     networkID,
+		URL:           reqURL,
+		ResourceType:  resourceType,
+		Blocked:       true,
+		BlockedReason: reason,
+		StartTime:     now,
+		EndTime:       now,
+	}
+}
+
 func (ec *EventCollector) handleConsoleAPICalled(e *runtime.EventConsoleAPICalled) {
 	ec.mu.Lock()
 	defer ec.mu.Unlock()
@@ -361,35 +478,95 @@ func (ec *EventCollector) GetNetworkResults() []types.NetworkRequest {
 			continue
 		}
 
-		timeMS := int64(0)
-		if !req.EndTime.IsZero() {
-			timeMS = req.EndTime.Sub(req.StartTime).Milliseconds()
-		}
+		requests = append(requests, req.toNetworkRequest(ec.isInternalRequest(req.URL)))
+	}
+
+	return requests
+}
 
-		// Determine if request is internal (same domain as page)
-		isInternal := ec.isInternalRequest(req.URL)
+// GetDocumentRequest returns the final hop of the main document request, nil
+// if the navigation never produced one
+func (ec *EventCollector) GetDocumentRequest() *types.NetworkRequest {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
 
-		// Use ReceivedBytes as fallback when SizeBytes is 0
-		size := req.SizeBytes
-		if size == 0 {
-			size = req.ReceivedBytes
-		}
+	req, ok := ec.networkRequests[ec.mainRequestID]
+	if !ok {
+		return nil
+	}
+	doc := req.toNetworkRequest(true)
+	return &doc
+}
 
-		requests = append(requests, types.NetworkRequest{
-			ID:         req.RequestID,
-			URL:        req.URL,
-			Method:     req.Method,
-			Status:     req.Status,
-			Type:       req.ResourceType,
-			Size:       int(size),
-			Time:       float64(timeMS) / 1000.0,
-			IsInternal: isInternal,
-			Blocked:    req.Blocked,
-			Failed:     req.Failed,
-		})
+// toNetworkRequest converts the collected data to the API representation
+func (req *NetworkRequestData) toNetworkRequest(isInternal bool) types.NetworkRequest {
+	timeMS := int64(0)
+	if !req.EndTime.IsZero() {
+		timeMS = req.EndTime.Sub(req.StartTime).Milliseconds()
+	}
+
+	// Use ReceivedBytes as fallback when SizeBytes is 0
+	size := req.SizeBytes
+	if size == 0 {
+		size = req.ReceivedBytes
+	}
+
+	return types.NetworkRequest{
+		ID:            req.RequestID,
+		URL:           req.URL,
+		Method:        req.Method,
+		Status:        req.Status,
+		Type:          req.ResourceType,
+		Size:          int(size),
+		Time:          float64(timeMS) / 1000.0,
+		IsInternal:    isInternal,
+		Blocked:       req.Blocked,
+		BlockedReason: req.BlockedReason,
+		Failed:        req.Failed,
+		Timing:        requestTiming(req),
+		Protocol:      types.NormalizeProtocol(req.Protocol),
+		RemoteIP:      strings.Trim(req.RemoteIP, "[]"),
+		FromCache:     req.FromCache,
+		ResponseBody:  req.ResponseBody,
 	}
+}
 
-	return requests
+// requestTiming splits a request into phases using Chrome's ResourceTiming.
+// Returns nil when Chrome reported no timing (blocked, failed, memory cache).
+func requestTiming(req *NetworkRequestData) *types.RequestTiming {
+	t := req.Timing
+	if t == nil {
+		return nil
+	}
+
+	timing := &types.RequestTiming{
+		DNS:  msToSeconds(phase(t.DNSStart, t.DNSEnd)),
+		TLS:  msToSeconds(phase(t.SslStart, t.SslEnd)),
+		TTFB: msToSeconds(nonNegative(t.ReceiveHeadersEnd - t.SendEnd)),
+	}
+
+	// Chrome's connect phase includes the TLS handshake
+	connectEnd := t.ConnectEnd
+	if t.SslStart >= 0 {
+		connectEnd = t.SslStart
+	}
+	timing.Connect = msToSeconds(phase(t.ConnectStart, connectEnd))
+
+	// Download runs from the end of the headers to loadingFinished
+	if !req.FinishedAt.IsZero() {
+		requestStart := cdp.MonotonicTimeEpoch.Add(time.Duration(t.RequestTime * float64(time.Second)))
+		headersEnd := requestStart.Add(time.Duration(t.ReceiveHeadersEnd * float64(time.Millisecond)))
+		timing.Download = nonNegative(req.FinishedAt.Sub(headersEnd).Seconds())
+	}
+	return timing
+}
+
+// msToSeconds converts a ResourceTiming phase to seconds, 0 when it did not happen
+func msToSeconds(ms float64) float64 {
+	if ms <= 0 {
+		return 0
+	}
+	return ms / 1000
 }
 
 // isInternalRequest checks if a request URL is internal to the page domain
@@ -495,6 +672,37 @@ func (ec *EventCollector) GetRedirectInfo() (string, int) {
 	return ec.redirectURL, ec.redirectStatus
 }
 
+// GetRedirectChain returns a copy of the main document's redirect hops
+func (ec *EventCollector) GetRedirectChain() []types.RedirectHop {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+	if len(ec.redirectChain) == 0 {
+		return nil
+	}
+	chain := make([]types.RedirectHop, len(ec.redirectChain))
+	copy(chain, ec.redirectChain)
+	return chain
+}
+
+// isMainDocumentRequest reports whether requestID belongs to the main document navigation
+func (ec *EventCollector) isMainDocumentRequest(requestID string) bool {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+	return ec.mainRequestID != "" && ec.mainRequestID == requestID
+}
+
+// redirectLoopDetected reports whether the last redirect hop points back to
+// a URL the main document already visited more than MaxRedirectRevisits times
+func (ec *EventCollector) redirectLoopDetected() bool {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+	n := len(ec.redirectChain)
+	if n == 0 {
+		return false
+	}
+	return types.RedirectVisits(ec.redirectChain, ec.redirectChain[n-1].Location) > types.MaxRedirectRevisits
+}
+
 // WaitForFetchHandlers waits for all fetch handler goroutines to complete
 func (ec *EventCollector) WaitForFetchHandlers(timeout time.Duration) {
 	deadline := time.After(timeout)
@@ -1,11 +1,16 @@
 package chrome
 
 import (
+	"math"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
 	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/types"
 )
 
 func TestNewEventCollector(t *testing.T) {
@@ -603,3 +608,194 @@ func TestUrlsMatchIgnoringFragment(t *testing.T) {
 		})
 	}
 }
+
+func TestEventCollector_MarkBlocked(t *testing.T) {
+	logger := zap.NewNop()
+	ec := NewEventCollector(logger)
+
+	// Existing request gets flagged in place
+	ec.mu.Lock()
+	ec.networkRequests["req-1"] = &NetworkRequestData{
+		RequestID:    "req-1",
+		URL:          "https://example.com/app.js",
+		ResourceType: "Script",
+		StartTime:    time.Now(),
+	}
+	ec.mu.Unlock()
+	ec.markBlocked("req-1", "https://example.com/app.js", "Script", types.BlockedReasonBlocklist)
+
+	// Unknown request gets a new entry
+	ec.markBlocked("req-2", "http://10.0.0.1/internal", "XHR", types.BlockedReasonSSRF)
+
+	requests := ec.GetNetworkResults()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+
+	reasons := make(map[string]string)
+	for _, req := range requests {
+		if !req.Blocked {
+			t.Errorf("request %s: expected Blocked = true", req.ID)
+		}
+		reasons[req.ID] = req.BlockedReason
+	}
+
+	if reasons["req-1"] != types.BlockedReasonBlocklist {
+		t.Errorf("req-1 BlockedReason = %q, want %q", reasons["req-1"], types.BlockedReasonBlocklist)
+	}
+	if reasons["req-2"] != types.BlockedReasonSSRF {
+		t.Errorf("req-2 BlockedReason = %q, want %q", reasons["req-2"], types.BlockedReasonSSRF)
+	}
+}
+
+func TestEventCollector_RedirectChain(t *testing.T) {
+	logger := zap.NewNop()
+	ec := NewEventCollector(logger)
+	ec.SetPageURL("http://example.com/old")
+
+	sent := func(url string, redirect *network.Response) *network.EventRequestWillBeSent {
+		return &network.EventRequestWillBeSent{
+			RequestID:        "main",
+			Request:          &network.Request{URL: url, Method: "GET"},
+			Type:             network.ResourceTypeDocument,
+			DocumentURL:      url,
+			RedirectResponse: redirect,
+		}
+	}
+
+	ec.handleRequestWillBeSent(sent("http://example.com/old", nil))
+	ec.handleRequestWillBeSent(sent("https://example.com/old", &network.Response{URL: "http://example.com/old", Status: 301}))
+	ec.handleRequestWillBeSent(sent("https://www.example.com/new", &network.Response{URL: "https://example.com/old", Status: 302}))
+
+	// Unrelated document request (e.g. an iframe) must not be recorded
+	ec.handleRequestWillBeSent(&network.EventRequestWillBeSent{
+		RequestID:        "frame",
+		Request:          &network.Request{URL: "https://ads.example.net/b", Method: "GET"},
+		Type:             network.ResourceTypeDocument,
+		RedirectResponse: &network.Response{URL: "https://ads.example.net/a", Status: 302},
+	})
+
+	if !ec.isMainDocumentRequest("main") {
+		t.Error("isMainDocumentRequest(main) = false, want true")
+	}
+	if ec.isMainDocumentRequest("frame") {
+		t.Error("isMainDocumentRequest(frame) = true, want false")
+	}
+
+	chain := ec.GetRedirectChain()
+	if len(chain) != 2 {
+		t.Fatalf("len(chain) = %d, want 2", len(chain))
+	}
+	if chain[0].URL != "http://example.com/old" || chain[0].StatusCode != 301 || chain[0].Location != "https://example.com/old" {
+		t.Errorf("hop 0 = %+v", chain[0])
+	}
+	if chain[1].URL != "https://example.com/old" || chain[1].StatusCode != 302 || chain[1].Location != "https://www.example.com/new" {
+		t.Errorf("hop 1 = %+v", chain[1])
+	}
+	if ec.redirectLoopDetected() {
+		t.Error("redirectLoopDetected() = true, want false")
+	}
+
+	// One redirect back to the start URL is allowed, e.g. after a cookie is set
+	ec.handleRequestWillBeSent(sent("http://example.com/old", &network.Response{URL: "https://www.example.com/new", Status: 302}))
+	if ec.redirectLoopDetected() {
+		t.Error("redirectLoopDetected() = true after one revisit, want false")
+	}
+
+	// Coming back a second time is a loop
+	ec.handleRequestWillBeSent(sent("https://example.com/old", &network.Response{URL: "http://example.com/old", Status: 301}))
+	ec.handleRequestWillBeSent(sent("https://www.example.com/new", &network.Response{URL: "https://example.com/old", Status: 302}))
+	ec.handleRequestWillBeSent(sent("http://example.com/old", &network.Response{URL: "https://www.example.com/new", Status: 302}))
+	if !ec.redirectLoopDetected() {
+		t.Error("redirectLoopDetected() = false, want true")
+	}
+}
+
+func TestEventCollector_DocumentRequestTiming(t *testing.T) {
+	ec := NewEventCollector(zap.NewNop())
+	ec.SetPageURL("https://example.com/")
+
+	requestTime := cdp.MonotonicTime(cdp.MonotonicTimeEpoch.Add(100 * time.Second))
+	finished := cdp.MonotonicTime(cdp.MonotonicTimeEpoch.Add(100*time.Second + 172*time.Millisecond))
+
+	ec.handleRequestWillBeSent(&network.EventRequestWillBeSent{
+		RequestID: "main",
+		Request:   &network.Request{URL: "https://example.com/", Method: "GET"},
+		Type:      network.ResourceTypeDocument,
+		Timestamp: &requestTime,
+	})
+	ec.handleResponseReceived(&network.EventResponseReceived{
+		RequestID: "main",
+		Response: &network.Response{
+			URL: "https://example.com/", Status: 200, Protocol: "h2", RemoteIPAddress: "[2001:db8::1]",
+			Timing: &network.ResourceTiming{
+				RequestTime: 100,
+				DNSStart:    1, DNSEnd: 5,
+				ConnectStart: 5, ConnectEnd: 20,
+				SslStart: 10, SslEnd: 20,
+				SendStart: 21, SendEnd: 22,
+				ReceiveHeadersEnd: 72,
+			},
+		},
+	})
+	ec.handleLoadingFinished(&network.EventLoadingFinished{RequestID: "main", Timestamp: &finished})
+
+	// Reused connection: no DNS/connect/TLS phases
+	ec.handleRequestWillBeSent(&network.EventRequestWillBeSent{
+		RequestID: "css",
+		Request:   &network.Request{URL: "https://example.com/app.css", Method: "GET"},
+		Type:      network.ResourceTypeStylesheet,
+	})
+	ec.handleResponseReceived(&network.EventResponseReceived{
+		RequestID: "css",
+		Response: &network.Response{
+			URL: "https://example.com/app.css", Status: 200, Protocol: "http/1.1", FromDiskCache: true,
+			Timing: &network.ResourceTiming{
+				DNSStart: -1, DNSEnd: -1, ConnectStart: -1, ConnectEnd: -1, SslStart: -1, SslEnd: -1,
+				SendStart: 1, SendEnd: 2, ReceiveHeadersEnd: 12,
+			},
+		},
+	})
+
+	doc := ec.GetDocumentRequest()
+	if doc == nil {
+		t.Fatal("GetDocumentRequest() = nil")
+	}
+	if doc.Protocol != types.ProtocolHTTP2 || doc.RemoteIP != "2001:db8::1" || doc.FromCache {
+		t.Errorf("document = %+v", doc)
+	}
+
+	approx := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-6 {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+	approx("DNS", doc.Timing.DNS, 0.004)
+	approx("Connect", doc.Timing.Connect, 0.005)
+	approx("TLS", doc.Timing.TLS, 0.010)
+	approx("TTFB", doc.Timing.TTFB, 0.050)
+	approx("Download", doc.Timing.Download, 0.100)
+
+	requests := ec.GetNetworkResults()
+	if len(requests) != 1 {
+		t.Fatalf("len(requests) = %d, want 1 (main document excluded)", len(requests))
+	}
+	css := requests[0]
+	if css.Protocol != types.ProtocolHTTP1 || !css.FromCache {
+		t.Errorf("css = %+v", css)
+	}
+	if css.Timing.DNS != 0 || css.Timing.Connect != 0 || css.Timing.TLS != 0 {
+		t.Errorf("css timing = %+v, want no connection phases", css.Timing)
+	}
+	approx("css TTFB", css.Timing.TTFB, 0.010)
+}
+
+func TestEventCollector_DocumentRequestMissing(t *testing.T) {
+	ec := NewEventCollector(zap.NewNop())
+	ec.SetPageURL("https://example.com/")
+
+	if doc := ec.GetDocumentRequest(); doc != nil {
+		t.Errorf("GetDocumentRequest() = %+v, want nil", doc)
+	}
+}
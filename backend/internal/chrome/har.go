@@ -0,0 +1,399 @@
+package chrome
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+const harPageID = "page_1"
+
+// harTimeFormat is ISO 8601 with milliseconds, as HAR viewers expect
+const harTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// BuildHAR returns the captured network activity as a HAR 1.2 document.
+// Redirect hops are separate entries; entries are ordered by start time.
+func (ec *EventCollector) BuildHAR(pageTitle string) *types.HAR {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+
+	requests := make([]*NetworkRequestData, 0, len(ec.networkRequests)+len(ec.redirectedRequests))
+	requests = append(requests, ec.redirectedRequests...)
+	for _, req := range ec.networkRequests {
+		requests = append(requests, req)
+	}
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].StartTime.Before(requests[j].StartTime)
+	})
+
+	entries := make([]types.HAREntry, 0, len(requests))
+	for _, req := range requests {
+		entries = append(entries, harEntry(req))
+	}
+
+	return &types.HAR{
+		Log: types.HARLog{
+			Version: "1.2",
+			Creator: types.HARCreator{Name: "jsbug", Version: "1.0"},
+			Pages: []types.HARPage{{
+				StartedDateTime: ec.startTime.Format(harTimeFormat),
+				ID:              harPageID,
+				Title:           pageTitle,
+				PageTimings: types.HARPageTimings{
+					OnContentLoad: ec.lifecycleOffsetMs("DOMContentLoaded"),
+					OnLoad:        ec.lifecycleOffsetMs("load"),
+				},
+			}},
+			Entries: entries,
+		},
+	}
+}
+
+// lifecycleOffsetMs returns milliseconds from render start to a lifecycle
+// event, or -1 if it did not fire. Caller holds ec.mu.
+func (ec *EventCollector) lifecycleOffsetMs(name string) float64 {
+	t, ok := ec.lifecycleEvents[name]
+	if !ok {
+		return -1
+	}
+	return float64(t.Sub(ec.startTime).Microseconds()) / 1000
+}
+
+// harEntry converts a captured request to a HAR entry
+func harEntry(req *NetworkRequestData) types.HAREntry {
+	timings := harTimings(req)
+	entry := types.HAREntry{
+		PageRef:         harPageID,
+		StartedDateTime: req.WallTime.Format(harTimeFormat),
+		Time:            harTotal(timings),
+		Request: types.HARRequest{
+			Method:      req.Method,
+			URL:         req.URL,
+			HTTPVersion: harHTTPVersion(req.Protocol),
+			Cookies:     []types.HARCookie{},
+			Headers:     harHeaders(req.RequestHeaders),
+			QueryString: harQueryString(req.URL),
+			HeadersSize: -1,
+			BodySize:    len(req.PostData),
+		},
+		Response: types.HARResponse{
+			Status:      req.Status,
+			StatusText:  req.StatusText,
+			HTTPVersion: harHTTPVersion(req.Protocol),
+			Cookies:     []types.HARCookie{},
+			Headers:     harHeaders(req.ResponseHeaders),
+			Content: types.HARContent{
+				Size:     int(req.ReceivedBytes),
+				MimeType: req.MimeType,
+				Text:     req.Body,
+				Comment:  req.BodyComment,
+			},
+			RedirectURL: req.RedirectURL,
+			HeadersSize: -1,
+			BodySize:    int(req.SizeBytes),
+		},
+		Timings:       timings,
+		ResourceType:  req.ResourceType,
+		BlockedReason: req.BlockedReason,
+		Error:         req.FailureReason,
+		FromCache:     req.FromCache,
+	}
+
+	if req.Method == "" {
+		// Blocked before Network.requestWillBeSent was seen
+		entry.Request.Method = "GET"
+	}
+	if req.WallTime.IsZero() {
+		entry.StartedDateTime = req.StartTime.Format(harTimeFormat)
+	}
+	if req.PostData != "" {
+		entry.Request.PostData = &types.HARPostData{
+			MimeType: headerValue(req.RequestHeaders, "Content-Type"),
+			Text:     req.PostData,
+		}
+	}
+	if req.BodyBase64 {
+		entry.Response.Content.Encoding = "base64"
+	}
+	if entry.Response.Content.Size == 0 && req.Body != "" && !req.BodyBase64 {
+		entry.Response.Content.Size = len(req.Body)
+	}
+	if req.SizeBytes == 0 && req.Status == 0 {
+		entry.Response.BodySize = -1
+	}
+	if req.RemoteIP != "" {
+		entry.ServerIPAddress = strings.Trim(req.RemoteIP, "[]")
+	}
+	if req.Blocked {
+		entry.Comment = "blocked: " + req.BlockedReason
+	}
+	return entry
+}
+
+// harTimings splits a request into HAR phases using Chrome's ResourceTiming.
+// Without timing data (blocked, failed, cached) the whole duration is wait.
+func harTimings(req *NetworkRequestData) types.HARTimings {
+	t := req.Timing
+	if t == nil {
+		total := 0.0
+		if !req.EndTime.IsZero() {
+			total = float64(req.EndTime.Sub(req.StartTime).Microseconds()) / 1000
+		}
+		return types.HARTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Wait: total}
+	}
+
+	timings := types.HARTimings{
+		Blocked: firstNonNegative(t.DNSStart, t.ConnectStart, t.SendStart),
+		DNS:     phase(t.DNSStart, t.DNSEnd),
+		Connect: phase(t.ConnectStart, t.ConnectEnd),
+		SSL:     phase(t.SslStart, t.SslEnd),
+		Send:    nonNegative(t.SendEnd - t.SendStart),
+		Wait:    nonNegative(t.ReceiveHeadersEnd - t.SendEnd),
+	}
+
+	// Receive runs from the end of the headers to loadingFinished
+	if !req.FinishedAt.IsZero() {
+		requestStart := cdp.MonotonicTimeEpoch.Add(time.Duration(t.RequestTime * float64(time.Second)))
+		headersEnd := requestStart.Add(time.Duration(t.ReceiveHeadersEnd * float64(time.Millisecond)))
+		timings.Receive = nonNegative(float64(req.FinishedAt.Sub(headersEnd).Microseconds()) / 1000)
+	}
+	return timings
+}
+
+// harTotal sums the non-negative phases. SSL is already part of connect.
+func harTotal(t types.HARTimings) float64 {
+	total := 0.0
+	for _, v := range []float64{t.Blocked, t.DNS, t.Connect, t.Send, t.Wait, t.Receive} {
+		if v > 0 {
+			total += v
+		}
+	}
+	return total
+}
+
+// phase returns end-start, or -1 when the phase did not happen
+func phase(start, end float64) float64 {
+	if start < 0 || end < 0 {
+		return -1
+	}
+	return nonNegative(end - start)
+}
+
+func firstNonNegative(values ...float64) float64 {
+	for _, v := range values {
+		if v >= 0 {
+			return v
+		}
+	}
+	return -1
+}
+
+func nonNegative(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	return v
+}
+
+// harHTTPVersion maps Chrome's protocol names to HTTP version strings
+func harHTTPVersion(protocol string) string {
+	switch strings.ToLower(protocol) {
+	case "":
+		return ""
+	case "h2":
+		return "HTTP/2.0"
+	case "h3", "h3-29", "quic":
+		return "HTTP/3.0"
+	default:
+		return strings.ToUpper(protocol)
+	}
+}
+
+// harHeaders converts CDP headers to sorted HAR headers. Chrome joins
+// repeated headers with newlines; each value becomes its own entry.
+func harHeaders(headers network.Headers) []types.HARHeader {
+	result := make([]types.HARHeader, 0, len(headers))
+	for name, value := range headers {
+		str, ok := value.(string)
+		if !ok {
+			str = fmt.Sprint(value)
+		}
+		for _, v := range strings.Split(str, "\n") {
+			result = append(result, types.HARHeader{Name: name, Value: v})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+	return result
+}
+
+// headerValue returns a header value, matching the name case-insensitively
+func headerValue(headers network.Headers, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			if str, ok := value.(string); ok {
+				return str
+			}
+		}
+	}
+	return ""
+}
+
+// harQueryString lists the query parameters of a URL in order
+func harQueryString(rawURL string) []types.HARQueryParam {
+	params := []types.HARQueryParam{}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return params
+	}
+	for _, pair := range strings.Split(u.RawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(pair, "=")
+		if n, err := url.QueryUnescape(name); err == nil {
+			name = n
+		}
+		if v, err := url.QueryUnescape(value); err == nil {
+			value = v
+		}
+		params = append(params, types.HARQueryParam{Name: name, Value: value})
+	}
+	return params
+}
+
+// captureHARBodies fetches response bodies for the HAR export while the tab
+// is still open. Bodies over MaxHARBodyBytes, and any past the total budget,
+// are left out with a comment. Failures never fail the render.
+func (r *RendererV2) captureHARBodies(opts RenderOptions, state *renderState, collector *EventCollector) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if opts.HAR == nil || !opts.HAR.IncludeBodies {
+			return nil
+		}
+
+		budgetCtx, cancel := state.budget(ctx)
+		defer cancel()
+
+		total := 0
+		for _, id := range collector.bodyCandidates() {
+			if budgetCtx.Err() != nil {
+				break
+			}
+			if total >= types.MaxHARTotalBodyBytes {
+				collector.setResponseBody(id, "", false, "total body budget exceeded")
+				continue
+			}
+
+			// Reuse a body already read for response_bodies instead of fetching it twice
+			if captured := collector.capturedBody(id); captured != nil && captured.Skipped == "" {
+				if captured.Size > types.MaxHARBodyBytes {
+					collector.setResponseBody(id, "", false, fmt.Sprintf("body exceeds %d bytes", types.MaxHARBodyBytes))
+					continue
+				}
+				if !captured.Truncated {
+					total += captured.Size
+					collector.setResponseBody(id, captured.Body, captured.Base64, "")
+					continue
+				}
+			}
+
+			body, err := network.GetResponseBody(network.RequestID(id)).Do(budgetCtx)
+			if err != nil {
+				r.logger.Debug("Failed to get response body",
+					zap.String("url", opts.URL),
+					zap.String("request_id", id),
+					zap.Error(err))
+				continue
+			}
+			if len(body) > types.MaxHARBodyBytes {
+				collector.setResponseBody(id, "", false, fmt.Sprintf("body exceeds %d bytes", types.MaxHARBodyBytes))
+				continue
+			}
+
+			total += len(body)
+			if isTextMimeType(collector.mimeType(id)) && utf8.Valid(body) {
+				collector.setResponseBody(id, string(body), false, "")
+			} else {
+				collector.setResponseBody(id, base64.StdEncoding.EncodeToString(body), true, "")
+			}
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return nil
+	}
+}
+
+// bodyCandidates returns the IDs of finished requests that may have a body
+func (ec *EventCollector) bodyCandidates() []string {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+
+	ids := make([]string, 0, len(ec.networkRequests))
+	for id, req := range ec.networkRequests {
+		if req.Blocked || req.Failed || req.FinishedAt.IsZero() || req.Status == 204 ||
+			(req.Status >= 300 && req.Status < 400) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (ec *EventCollector) mimeType(requestID string) string {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+	if req, ok := ec.networkRequests[requestID]; ok {
+		return req.MimeType
+	}
+	return ""
+}
+
+// capturedBody returns the response_bodies capture of a request, if any
+func (ec *EventCollector) capturedBody(requestID string) *types.ResponseBody {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+	if req, ok := ec.networkRequests[requestID]; ok {
+		return req.ResponseBody
+	}
+	return nil
+}
+
+func (ec *EventCollector) setResponseBody(requestID, body string, base64Encoded bool, comment string) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if req, ok := ec.networkRequests[requestID]; ok {
+		req.Body = body
+		req.BodyBase64 = base64Encoded
+		req.BodyComment = comment
+	}
+}
+
+// isTextMimeType reports whether a body can be stored as plain text
+func isTextMimeType(mimeType string) bool {
+	mimeType = strings.ToLower(mimeType)
+	if strings.HasPrefix(mimeType, "text/") {
+		return true
+	}
+	for _, part := range []string{"json", "javascript", "xml", "x-www-form-urlencoded"} {
+		if strings.Contains(mimeType, part) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package chrome
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+func TestEventCollector_BuildHAR(t *testing.T) {
+	ec := NewEventCollector(zap.NewNop())
+	ec.SetPageURL("http://example.com/")
+
+	monotonic := func(seconds float64) *cdp.MonotonicTime {
+		ts := cdp.MonotonicTime(cdp.MonotonicTimeEpoch.Add(time.Duration(seconds * float64(time.Second))))
+		return &ts
+	}
+
+	// Main document with one redirect hop
+	ec.handleRequestWillBeSent(&network.EventRequestWillBeSent{
+		RequestID: "main",
+		Request:   &network.Request{URL: "http://example.com/", Method: "GET", Headers: network.Headers{"User-Agent": "test"}},
+		Type:      network.ResourceTypeDocument,
+		Timestamp: monotonic(99.9),
+	})
+	ec.handleRequestWillBeSent(&network.EventRequestWillBeSent{
+		RequestID: "main",
+		Request:   &network.Request{URL: "https://example.com/?q=a%20b&x", Method: "GET"},
+		Type:      network.ResourceTypeDocument,
+		Timestamp: monotonic(100),
+		RedirectResponse: &network.Response{
+			URL: "http://example.com/", Status: 301, StatusText: "Moved Permanently", Protocol: "http/1.1",
+			Headers: network.Headers{"Location": "https://example.com/?q=a%20b&x"},
+		},
+	})
+	ec.handleResponseReceived(&network.EventResponseReceived{
+		RequestID: "main",
+		Response: &network.Response{
+			URL: "https://example.com/?q=a%20b&x", Status: 200, StatusText: "OK", Protocol: "h2",
+			MimeType: "text/html", RemoteIPAddress: "[2001:db8::1]",
+			Headers: network.Headers{"Set-Cookie": "a=1\nb=2", "Content-Type": "text/html"},
+			Timing: &network.ResourceTiming{
+				RequestTime: 100,
+				DNSStart:    1, DNSEnd: 5,
+				ConnectStart: 5, ConnectEnd: 20,
+				SslStart: 10, SslEnd: 20,
+				SendStart: 21, SendEnd: 22,
+				ReceiveHeadersEnd: 72,
+			},
+		},
+	})
+	ec.handleLoadingFinished(&network.EventLoadingFinished{RequestID: "main", Timestamp: monotonic(100.1), EncodedDataLength: 512})
+
+	// Blocked subresource
+	ec.markBlocked("ads", "https://ads.example.net/tag.js", "Script", types.BlockedReasonBlocklist)
+
+	har := ec.BuildHAR("Example")
+
+	if har.Log.Version != "1.2" {
+		t.Errorf("Version = %q, want 1.2", har.Log.Version)
+	}
+	if len(har.Log.Pages) != 1 || har.Log.Pages[0].Title != "Example" {
+		t.Errorf("Pages = %+v", har.Log.Pages)
+	}
+	if len(har.Log.Entries) != 3 {
+		t.Fatalf("len(Entries) = %d, want 3", len(har.Log.Entries))
+	}
+
+	hop := har.Log.Entries[0]
+	if hop.Response.Status != 301 || hop.Response.RedirectURL != "https://example.com/?q=a%20b&x" || hop.Response.HTTPVersion != "HTTP/1.1" {
+		t.Errorf("redirect hop = %+v", hop.Response)
+	}
+
+	main := har.Log.Entries[1]
+	if main.Response.Status != 200 || main.Response.HTTPVersion != "HTTP/2.0" {
+		t.Errorf("main response = %+v", main.Response)
+	}
+	if main.ServerIPAddress != "2001:db8::1" {
+		t.Errorf("ServerIPAddress = %q, want 2001:db8::1", main.ServerIPAddress)
+	}
+	wantQuery := []types.HARQueryParam{{Name: "q", Value: "a b"}, {Name: "x", Value: ""}}
+	if len(main.Request.QueryString) != 2 || main.Request.QueryString[0] != wantQuery[0] || main.Request.QueryString[1] != wantQuery[1] {
+		t.Errorf("QueryString = %+v, want %+v", main.Request.QueryString, wantQuery)
+	}
+	cookies := 0
+	for _, h := range main.Response.Headers {
+		if h.Name == "Set-Cookie" {
+			cookies++
+		}
+	}
+	if cookies != 2 {
+		t.Errorf("Set-Cookie headers = %d, want 2 (split on newline)", cookies)
+	}
+
+	want := types.HARTimings{Blocked: 1, DNS: 4, Connect: 15, SSL: 10, Send: 1, Wait: 50, Receive: 28}
+	got := main.Timings
+	for name, pair := range map[string][2]float64{
+		"blocked": {got.Blocked, want.Blocked}, "dns": {got.DNS, want.DNS}, "connect": {got.Connect, want.Connect},
+		"ssl": {got.SSL, want.SSL}, "send": {got.Send, want.Send}, "wait": {got.Wait, want.Wait}, "receive": {got.Receive, want.Receive},
+	} {
+		if math.Abs(pair[0]-pair[1]) > 0.01 {
+			t.Errorf("timings.%s = %v, want %v", name, pair[0], pair[1])
+		}
+	}
+	if math.Abs(main.Time-99) > 0.01 {
+		t.Errorf("Time = %v, want 99", main.Time)
+	}
+
+	blocked := har.Log.Entries[2]
+	if blocked.BlockedReason != types.BlockedReasonBlocklist || blocked.Response.Status != 0 || blocked.Request.Method != "GET" {
+		t.Errorf("blocked entry = %+v", blocked)
+	}
+}
+
+func TestCaptureHARBodies_ReusesResponseBodies(t *testing.T) {
+	ec := NewEventCollector(zap.NewNop())
+	ec.networkRequests["xhr"] = &NetworkRequestData{
+		URL: "https://a.test/api", Status: 200, MimeType: "application/json", FinishedAt: time.Now(),
+		ResponseBody: &types.ResponseBody{Body: `{"a":1}`, Size: 7},
+	}
+	ec.networkRequests["big"] = &NetworkRequestData{
+		URL: "https://a.test/big", Status: 200, MimeType: "application/json", FinishedAt: time.Now(),
+		ResponseBody: &types.ResponseBody{Body: "x", Size: types.MaxHARBodyBytes + 1, Truncated: true},
+	}
+
+	// No CDP target: any GetResponseBody call would fail and leave Body empty
+	r := &RendererV2{logger: zap.NewNop()}
+	opts := RenderOptions{Timeout: time.Second, HAR: &types.HAROptions{IncludeBodies: true}}
+	state := &renderState{deadline: time.Now().Add(opts.Timeout)}
+	if err := r.captureHARBodies(opts, state, ec)(context.Background()); err != nil {
+		t.Fatalf("captureHARBodies() error = %v", err)
+	}
+
+	if req := ec.networkRequests["xhr"]; req.Body != `{"a":1}` || req.BodyBase64 || req.BodyComment != "" {
+		t.Errorf("xhr body = %q (base64 %v, comment %q), want the captured body", req.Body, req.BodyBase64, req.BodyComment)
+	}
+	if req := ec.networkRequests["big"]; req.Body != "" || req.BodyComment == "" {
+		t.Errorf("big body = %q (comment %q), want left out with a comment", req.Body, req.BodyComment)
+	}
+}
+
+func TestHARHTTPVersion(t *testing.T) {
+	tests := map[string]string{
+		"":         "",
+		"http/1.1": "HTTP/1.1",
+		"h2":       "HTTP/2.0",
+		"h3":       "HTTP/3.0",
+	}
+	for protocol, want := range tests {
+		if got := harHTTPVersion(protocol); got != want {
+			t.Errorf("harHTTPVersion(%q) = %q, want %q", protocol, got, want)
+		}
+	}
+}
+
+func TestIsTextMimeType(t *testing.T) {
+	tests := map[string]bool{
+		"text/html":              true,
+		"application/json":       true,
+		"application/javascript": true,
+		"image/svg+xml":          true,
+		"image/png":              false,
+		"font/woff2":             false,
+	}
+	for mimeType, want := range tests {
+		if got := isTextMimeType(mimeType); got != want {
+			t.Errorf("isTextMimeType(%q) = %v, want %v", mimeType, got, want)
+		}
+	}
+}
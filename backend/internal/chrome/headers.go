@@ -0,0 +1,39 @@
+package chrome
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+)
+
+// mergeHeaders returns the request headers with custom headers added,
+// replacing same-named headers case-insensitively
+func mergeHeaders(request network.Headers, custom map[string]string) []*fetch.HeaderEntry {
+	entries := make([]*fetch.HeaderEntry, 0, len(request)+len(custom))
+	for name, value := range request {
+		if hasHeader(custom, name) {
+			continue
+		}
+		str, ok := value.(string)
+		if !ok {
+			str = fmt.Sprint(value)
+		}
+		entries = append(entries, &fetch.HeaderEntry{Name: name, Value: str})
+	}
+	for name, value := range custom {
+		entries = append(entries, &fetch.HeaderEntry{Name: name, Value: value})
+	}
+	return entries
+}
+
+// hasHeader reports whether headers contains name, case-insensitively
+func hasHeader(headers map[string]string, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
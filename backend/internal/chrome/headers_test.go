@@ -0,0 +1,30 @@
+package chrome
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+func TestMergeHeaders(t *testing.T) {
+	request := network.Headers{"User-Agent": "test", "authorization": "Basic old"}
+	custom := map[string]string{"Authorization": "Bearer token", "X-Custom": "1"}
+
+	got := make(map[string]string)
+	for _, entry := range mergeHeaders(request, custom) {
+		if _, dup := got[entry.Name]; dup {
+			t.Errorf("header %s repeated", entry.Name)
+		}
+		got[entry.Name] = entry.Value
+	}
+
+	want := map[string]string{"User-Agent": "test", "Authorization": "Bearer token", "X-Custom": "1"}
+	if len(got) != len(want) {
+		t.Errorf("mergeHeaders() = %v, want %v", got, want)
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %q, want %q", name, got[name], value)
+		}
+	}
+}
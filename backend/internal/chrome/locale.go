@@ -0,0 +1,38 @@
+package chrome
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+)
+
+// emulateLocale overrides the Intl locale, the time zone and the position
+// reported by navigator.geolocation. Accept-Language is set with the user
+// agent in emulateDevice.
+func (r *RendererV2) emulateLocale(opts RenderOptions) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if opts.Locale != "" {
+			if err := emulation.SetLocaleOverride().WithLocale(opts.Locale).Do(ctx); err != nil {
+				return fmt.Errorf("set locale override failed: %w", err)
+			}
+		}
+		if opts.Timezone != "" {
+			if err := emulation.SetTimezoneOverride(opts.Timezone).Do(ctx); err != nil {
+				return fmt.Errorf("set timezone override failed: %w", err)
+			}
+		}
+		if g := opts.Geolocation; g != nil {
+			err := emulation.SetGeolocationOverride().
+				WithLatitude(g.Latitude).
+				WithLongitude(g.Longitude).
+				WithAccuracy(g.Accuracy).
+				Do(ctx)
+			if err != nil {
+				return fmt.Errorf("set geolocation override failed: %w", err)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,46 @@
+package chrome
+
+import (
+	"context"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"go.uber.org/zap"
+)
+
+// printPDF prints the rendered page to PDF when opts.PDF is set.
+// Failures are logged and never fail the render.
+func (r *RendererV2) printPDF(opts RenderOptions, state *renderState) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if opts.PDF == nil {
+			return nil
+		}
+		pdfOpts := *opts.PDF
+		pdfOpts.ApplyDefaults()
+
+		width, height := pdfOpts.PaperSize()
+		data, _, err := page.PrintToPDF().
+			WithLandscape(pdfOpts.Landscape).
+			WithPrintBackground(pdfOpts.PrintBackground).
+			WithPaperWidth(width).
+			WithPaperHeight(height).
+			WithMarginTop(pdfOpts.Margins.Top).
+			WithMarginRight(pdfOpts.Margins.Right).
+			WithMarginBottom(pdfOpts.Margins.Bottom).
+			WithMarginLeft(pdfOpts.Margins.Left).
+			Do(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			r.logger.Warn("Failed to print PDF", zap.String("url", opts.URL), zap.Error(err))
+			return nil
+		}
+
+		state.mu.Lock()
+		state.pdf = data
+		state.mu.Unlock()
+
+		return nil
+	}
+}
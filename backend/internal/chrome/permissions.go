@@ -0,0 +1,55 @@
+package chrome
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+// grantPermissions resets permissions left over from earlier renders in the
+// instance, then answers every permission prompt up front: permissions in
+// opts.Permissions are granted, the others denied. Geolocation is granted
+// when a position is emulated.
+func (r *RendererV2) grantPermissions(opts RenderOptions) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		// Permissions belong to the browser context shared by all tabs
+		browserCtx := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Browser)
+
+		if err := browser.ResetPermissions().Do(browserCtx); err != nil {
+			return fmt.Errorf("reset permissions failed: %w", err)
+		}
+
+		granted := make(map[string]bool, len(opts.Permissions)+1)
+		for _, p := range opts.Permissions {
+			granted[p] = true
+		}
+		if opts.Geolocation != nil {
+			granted["geolocation"] = true
+		}
+
+		for _, name := range types.PromptPermissions {
+			setting := browser.PermissionSettingDenied
+			if granted[name] {
+				setting = browser.PermissionSettingGranted
+			}
+			err := browser.SetPermission(&browser.PermissionDescriptor{Name: name}, setting).Do(browserCtx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				// Permissions unknown to this Chrome version are skipped
+				r.logger.Debug("Failed to set permission",
+					zap.String("permission", name),
+					zap.String("setting", string(setting)),
+					zap.Error(err))
+			}
+		}
+		return nil
+	}
+}
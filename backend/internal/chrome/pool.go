@@ -3,6 +3,8 @@ package chrome
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -13,6 +15,11 @@ import (
 var (
 	ErrNoInstanceAvailable = errors.New("no chrome instance available")
 	ErrPoolShuttingDown    = errors.New("pool is shutting down")
+
+	// Queue errors wrap ErrNoInstanceAvailable so callers can treat them as pool exhaustion
+	ErrQueueFull    = fmt.Errorf("%w: render queue is full", ErrNoInstanceAvailable)
+	ErrQueueTimeout = fmt.Errorf("%w: queue wait timeout exceeded", ErrNoInstanceAvailable)
+	ErrSpawnFailed  = fmt.Errorf("%w: failed to start chrome instance", ErrNoInstanceAvailable)
 )
 
 // PoolStats contains pool statistics
@@ -20,6 +27,31 @@ type PoolStats struct {
 	TotalInstances     int
 	AvailableInstances int
 	ActiveInstances    int32
+	QueuedRequests     int
+
+	// Autoscaling
+	MinInstances      int
+	MaxInstances      int
+	SpawningInstances int   // Instances currently starting
+	SpawnedTotal      int64 // Instances spawned on demand since start
+	ReapedTotal       int64 // Idle instances terminated since start
+
+	// Restarts since start, by reason
+	DeadRestarts   int64 // Instance found dead on acquire
+	PolicyRestarts int64 // RestartAfterCount / RestartAfterTime reached
+}
+
+// waiter is a request queued for an instance
+type waiter struct {
+	ch         chan int // receives the handed-over instance ID, or -1 when its spawn failed (buffered, size 1)
+	onPosition func(position int)
+}
+
+// positionUpdate is a queue position callback collected under waitMu
+// and invoked after it is released
+type positionUpdate struct {
+	fn       func(position int)
+	position int
 }
 
 // ChromePool manages a pool of Chrome browser instances
@@ -31,28 +63,53 @@ type ChromePool struct {
 	activeCount atomic.Int32
 	ctx         context.Context
 	cancel      context.CancelFunc
+
+	// FIFO queue of requests waiting for an instance, guarded by waitMu.
+	// Released instances are handed to the head of the queue before
+	// being returned to available.
+	waitMu  sync.Mutex
+	waiters []*waiter
+
+	// Autoscaling state, guarded by waitMu. instances has MaxInstances slots;
+	// a nil slot is free for spawning.
+	spawning     map[int]bool      // Slots with an instance starting
+	idleSince    map[int]time.Time // When each available instance was returned
+	spawnedTotal int64
+	reapedTotal  int64
+	newInstance  func(id int) (*Instance, error) // Instance factory, defaults to New
+
+	deadRestarts   atomic.Int64
+	policyRestarts atomic.Int64
 }
 
 // NewChromePool creates a new pool of Chrome instances.
-// It initializes all instances sequentially and fails fast if any instance fails.
+// It starts MinInstances instances sequentially and fails fast if any instance fails.
+// When MaxInstances > MinInstances, more instances are spawned on demand while
+// requests are queued, and instances idle for IdleTimeout are reaped.
 func NewChromePool(config InstanceConfig, logger *zap.Logger) (*ChromePool, error) {
 	if config.PoolSize <= 0 {
 		config.PoolSize = 1
 	}
+	if config.MinInstances <= 0 {
+		config.MinInstances = config.PoolSize
+	}
+	if config.MaxInstances < config.MinInstances {
+		config.MaxInstances = config.MinInstances
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	pool := &ChromePool{
 		config:    config,
 		logger:    logger,
-		instances: make([]*Instance, config.PoolSize),
-		available: make(chan int, config.PoolSize),
+		instances: make([]*Instance, config.MaxInstances),
+		available: make(chan int, config.MaxInstances),
 		ctx:       ctx,
 		cancel:    cancel,
 	}
 
-	// Initialize all instances sequentially
-	for i := 0; i < config.PoolSize; i++ {
+	// Initialize the minimum number of instances sequentially
+	for i := 0; i < config.MinInstances; i++ {
 		instance, err := New(i, config, logger)
 		if err != nil {
 			// Fail-fast: terminate all already-created instances
@@ -70,15 +127,23 @@ func NewChromePool(config InstanceConfig, logger *zap.Logger) (*ChromePool, erro
 		}
 
 		pool.instances[i] = instance
-		pool.available <- i
+		pool.returnToPool(i)
 
 		logger.Debug("Chrome instance created",
 			zap.Int("instance_id", i),
 		)
 	}
 
+	if config.MaxInstances > config.MinInstances && config.IdleTimeout > 0 {
+		go pool.reapLoop()
+	}
+
 	logger.Info("Chrome pool initialized",
-		zap.Int("pool_size", config.PoolSize),
+		zap.Int("min_instances", config.MinInstances),
+		zap.Int("max_instances", config.MaxInstances),
+		zap.Duration("idle_timeout", config.IdleTimeout),
+		zap.Int("queue_size", config.QueueSize),
+		zap.Duration("queue_timeout", config.QueueTimeout),
 	)
 
 	return pool, nil
@@ -86,17 +151,42 @@ func NewChromePool(config InstanceConfig, logger *zap.Logger) (*ChromePool, erro
 
 // Stats returns current pool statistics
 func (p *ChromePool) Stats() PoolStats {
+	p.waitMu.Lock()
+	defer p.waitMu.Unlock()
+
 	return PoolStats{
-		TotalInstances:     len(p.instances),
+		TotalInstances:     p.liveCountLocked(),
 		AvailableInstances: len(p.available),
 		ActiveInstances:    p.activeCount.Load(),
+		QueuedRequests:     len(p.waiters),
+		MinInstances:       p.config.MinInstances,
+		MaxInstances:       p.config.MaxInstances,
+		SpawningInstances:  len(p.spawning),
+		SpawnedTotal:       p.spawnedTotal,
+		ReapedTotal:        p.reapedTotal,
+		DeadRestarts:       p.deadRestarts.Load(),
+		PolicyRestarts:     p.policyRestarts.Load(),
 	}
 }
 
-// Acquire gets an available Chrome instance from the pool.
-// It returns ErrNoInstanceAvailable if all instances are busy,
-// or ErrPoolShuttingDown if the pool is shutting down.
-func (p *ChromePool) Acquire() (*Instance, error) {
+// queueLength returns the number of requests waiting for an instance
+func (p *ChromePool) queueLength() int {
+	p.waitMu.Lock()
+	defer p.waitMu.Unlock()
+	return len(p.waiters)
+}
+
+// Acquire gets an available Chrome instance from the pool, waiting in the
+// FIFO queue when all instances are busy. It returns ErrQueueFull if the queue
+// is full, ErrQueueTimeout if the wait exceeds the configured QueueTimeout,
+// ctx.Err() if ctx ends first, or ErrPoolShuttingDown if the pool is shutting down.
+func (p *ChromePool) Acquire(ctx context.Context) (*Instance, error) {
+	return p.AcquireWithPosition(ctx, nil)
+}
+
+// AcquireWithPosition is like Acquire and calls onPosition with the 1-based
+// queue position when the request is queued and every time it moves up.
+func (p *ChromePool) AcquireWithPosition(ctx context.Context, onPosition func(position int)) (*Instance, error) {
 	// Fast-path: check if pool is shutting down
 	select {
 	case <-p.ctx.Done():
@@ -104,71 +194,197 @@ func (p *ChromePool) Acquire() (*Instance, error) {
 	default:
 	}
 
-	// Non-blocking receive from available channel
+	id, err := p.waitForInstance(ctx, onPosition)
+	if err != nil {
+		return nil, err
+	}
+
+	// Double-check shutdown to prevent race condition
 	select {
-	case id := <-p.available:
-		// Double-check shutdown to prevent race condition
-		select {
-		case <-p.ctx.Done():
-			// Return instance to queue and report shutdown
-			select {
-			case p.available <- id:
-			default:
-			}
-			return nil, ErrPoolShuttingDown
-		default:
-		}
+	case <-p.ctx.Done():
+		// Return instance to queue and report shutdown
+		p.returnToPool(id)
+		return nil, ErrPoolShuttingDown
+	default:
+	}
 
-		instance := p.instances[id]
+	p.waitMu.Lock()
+	instance := p.instances[id]
+	p.waitMu.Unlock()
 
-		// Check if instance is alive
-		if !instance.IsAlive() {
-			// Attempt restart
-			if err := instance.Restart(); err != nil {
-				// Restart failed, return instance to queue
-				p.logger.Error("Failed to restart dead instance",
-					zap.Int("instance_id", id),
-					zap.Error(err),
-				)
-				select {
-				case p.available <- id:
-				default:
-				}
-				return nil, err
-			}
-			p.logger.Info("Restarted dead instance",
+	// Check if instance is alive
+	if !instance.IsAlive() {
+		// Attempt restart
+		if err := instance.Restart(); err != nil {
+			// Restart failed, return instance to queue
+			p.logger.Error("Failed to restart dead instance",
 				zap.Int("instance_id", id),
+				zap.Error(err),
 			)
+			p.returnToPool(id)
+			return nil, err
 		}
+		p.deadRestarts.Add(1)
+		p.logger.Info("Restarted dead instance",
+			zap.Int("instance_id", id),
+		)
+	}
 
-		// Check if policy-based restart is needed
-		if instance.ShouldRestart() {
-			if err := instance.Restart(); err != nil {
-				p.logger.Warn("Policy restart failed, continuing with existing instance",
-					zap.Int("instance_id", id),
-					zap.Error(err),
-				)
-			} else {
-				p.logger.Debug("Policy restart completed",
-					zap.Int("instance_id", id),
-				)
-			}
+	// Check if policy-based restart is needed
+	if instance.ShouldRestart() {
+		if err := instance.Restart(); err != nil {
+			p.logger.Warn("Policy restart failed, continuing with existing instance",
+				zap.Int("instance_id", id),
+				zap.Error(err),
+			)
+		} else {
+			p.policyRestarts.Add(1)
+			p.logger.Debug("Policy restart completed",
+				zap.Int("instance_id", id),
+			)
 		}
+	}
 
-		// Increment active count and set status
-		p.activeCount.Add(1)
-		instance.SetStatus(StatusRendering)
+	// Increment active count and set status
+	p.activeCount.Add(1)
+	instance.SetStatus(StatusRendering)
 
-		p.logger.Debug("Instance acquired",
-			zap.Int("instance_id", id),
-			zap.Int32("active_count", p.activeCount.Load()),
-		)
+	p.logger.Debug("Instance acquired",
+		zap.Int("instance_id", id),
+		zap.Int32("active_count", p.activeCount.Load()),
+	)
+
+	return instance, nil
+}
 
-		return instance, nil
+// waitForInstance takes a free instance ID, or queues the caller until one is
+// handed over by returnToPool
+func (p *ChromePool) waitForInstance(ctx context.Context, onPosition func(position int)) (int, error) {
+	p.waitMu.Lock()
 
+	// Take a free instance only when nobody is queued ahead (FIFO)
+	if len(p.waiters) == 0 {
+		select {
+		case id := <-p.available:
+			p.waitMu.Unlock()
+			return id, nil
+		default:
+		}
+	}
+
+	// Requests beyond the queue are only taken when an instance can be spawned for them
+	if len(p.waiters) >= p.config.QueueSize && !p.canSpawnLocked() {
+		p.waitMu.Unlock()
+		if p.config.QueueSize == 0 {
+			return -1, ErrNoInstanceAvailable
+		}
+		return -1, ErrQueueFull
+	}
+
+	w := &waiter{ch: make(chan int, 1), onPosition: onPosition}
+	p.waiters = append(p.waiters, w)
+	position := len(p.waiters)
+	p.maybeSpawnLocked()
+	p.waitMu.Unlock()
+
+	p.logger.Debug("Request queued for instance",
+		zap.Int("position", position),
+	)
+	if onPosition != nil {
+		onPosition(position)
+	}
+
+	var timeout <-chan time.Time
+	if p.config.QueueTimeout > 0 {
+		timer := time.NewTimer(p.config.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var err error
+	select {
+	case id := <-w.ch:
+		if id < 0 {
+			return -1, ErrSpawnFailed
+		}
+		return id, nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timeout:
+		err = ErrQueueTimeout
+	case <-p.ctx.Done():
+		err = ErrPoolShuttingDown
+	}
+
+	// Leave the queue. If an instance was handed over in the meantime, pass it on.
+	if !p.removeWaiter(w) {
+		if id := <-w.ch; id >= 0 {
+			p.returnToPool(id)
+		}
+	}
+	return -1, err
+}
+
+// removeWaiter removes w from the queue. It returns false if w was already
+// dequeued by returnToPool (an instance ID is then pending on w.ch).
+func (p *ChromePool) removeWaiter(w *waiter) bool {
+	p.waitMu.Lock()
+	for i, queued := range p.waiters {
+		if queued == w {
+			p.waiters = append(p.waiters[:i], p.waiters[i+1:]...)
+			updates := p.positionUpdatesLocked(i)
+			p.waitMu.Unlock()
+			notifyPositions(updates)
+			return true
+		}
+	}
+	p.waitMu.Unlock()
+	return false
+}
+
+// returnToPool hands an instance ID to the head of the queue, or puts it back
+// into available when nobody is waiting. It returns false if available is full.
+func (p *ChromePool) returnToPool(id int) bool {
+	p.waitMu.Lock()
+	if len(p.waiters) > 0 {
+		w := p.waiters[0]
+		p.waiters = p.waiters[1:]
+		updates := p.positionUpdatesLocked(0)
+		p.waitMu.Unlock()
+
+		w.ch <- id
+		notifyPositions(updates)
+		return true
+	}
+	defer p.waitMu.Unlock()
+
+	select {
+	case p.available <- id:
+		if p.idleSince == nil {
+			p.idleSince = make(map[int]time.Time)
+		}
+		p.idleSince[id] = time.Now()
+		return true
 	default:
-		// No instance available
-		return nil, ErrNoInstanceAvailable
+		return false
+	}
+}
+
+// positionUpdatesLocked collects new positions for waiters from index from
+// onwards. Must be called with waitMu held.
+func (p *ChromePool) positionUpdatesLocked(from int) []positionUpdate {
+	var updates []positionUpdate
+	for i := from; i < len(p.waiters); i++ {
+		if p.waiters[i].onPosition != nil {
+			updates = append(updates, positionUpdate{fn: p.waiters[i].onPosition, position: i + 1})
+		}
+	}
+	return updates
+}
+
+func notifyPositions(updates []positionUpdate) {
+	for _, u := range updates {
+		u.fn(u.position)
 	}
 }
 
@@ -186,18 +402,23 @@ func (p *ChromePool) Release(instance *Instance) {
 	instance.SetStatus(StatusIdle)
 	instance.IncrementRenders()
 
-	// Try to return instance to available queue
+	// Discard during shutdown, otherwise hand over to the next queued request
+	// or return to the available queue
 	select {
-	case p.available <- instance.ID():
-		p.logger.Debug("Instance released",
-			zap.Int("instance_id", instance.ID()),
-			zap.Int32("active_count", p.activeCount.Load()),
-		)
 	case <-p.ctx.Done():
 		p.logger.Debug("Discarding instance during shutdown",
 			zap.Int("instance_id", instance.ID()),
 		)
+		return
 	default:
+	}
+
+	if p.returnToPool(instance.ID()) {
+		p.logger.Debug("Instance released",
+			zap.Int("instance_id", instance.ID()),
+			zap.Int32("active_count", p.activeCount.Load()),
+		)
+	} else {
 		p.logger.Error("Available queue full - possible double release",
 			zap.Int("instance_id", instance.ID()),
 		)
@@ -256,8 +477,13 @@ func (p *ChromePool) Shutdown() error {
 		time.Sleep(50 * time.Millisecond)
 	}
 
-	// Terminate all instances
-	for i, instance := range p.instances {
+	// Terminate all instances (spawns finishing after this point terminate themselves)
+	p.waitMu.Lock()
+	instances := make([]*Instance, len(p.instances))
+	copy(instances, p.instances)
+	p.waitMu.Unlock()
+
+	for i, instance := range instances {
 		if instance != nil {
 			if err := instance.Terminate(); err != nil {
 				p.logger.Error("Failed to terminate instance",
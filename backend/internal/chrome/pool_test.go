@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -64,7 +65,7 @@ func TestAcquire_PoolExhausted(t *testing.T) {
 
 	// Don't add any instances to available channel - simulates exhausted pool
 	// Acquire should return ErrNoInstanceAvailable immediately
-	_, err := pool.Acquire()
+	_, err := pool.Acquire(context.Background())
 	if !errors.Is(err, ErrNoInstanceAvailable) {
 		t.Errorf("Acquire() error = %v, want ErrNoInstanceAvailable", err)
 	}
@@ -90,7 +91,7 @@ func TestAcquire_ShuttingDown(t *testing.T) {
 	cancel()
 
 	// Acquire should return ErrPoolShuttingDown
-	_, err := pool.Acquire()
+	_, err := pool.Acquire(context.Background())
 	if !errors.Is(err, ErrPoolShuttingDown) {
 		t.Errorf("Acquire() error = %v, want ErrPoolShuttingDown", err)
 	}
@@ -287,8 +288,316 @@ func TestAcquire_ReturnsErrorAfterShutdown(t *testing.T) {
 	pool.Shutdown()
 
 	// Acquire should return error
-	_, err := pool.Acquire()
+	_, err := pool.Acquire(context.Background())
 	if !errors.Is(err, ErrPoolShuttingDown) {
 		t.Errorf("Acquire() after shutdown error = %v, want ErrPoolShuttingDown", err)
 	}
 }
+
+func newQueueTestPool(queueSize int, queueTimeout time.Duration) *ChromePool {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ChromePool{
+		config:    InstanceConfig{PoolSize: 1, QueueSize: queueSize, QueueTimeout: queueTimeout},
+		logger:    zap.NewNop(),
+		instances: make([]*Instance, 1),
+		available: make(chan int, 1),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// waitForQueueLength polls until the pool queue reaches n entries
+func waitForQueueLength(t *testing.T, pool *ChromePool, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for pool.queueLength() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("queue length = %d, want %d", pool.queueLength(), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWaitForInstance_FreeInstance(t *testing.T) {
+	pool := newQueueTestPool(4, time.Second)
+	defer pool.cancel()
+	pool.available <- 0
+
+	id, err := pool.waitForInstance(context.Background(), nil)
+	if err != nil || id != 0 {
+		t.Fatalf("waitForInstance() = %d, %v, want 0, nil", id, err)
+	}
+}
+
+func TestWaitForInstance_QueueDisabled(t *testing.T) {
+	pool := newQueueTestPool(0, time.Second)
+	defer pool.cancel()
+
+	_, err := pool.waitForInstance(context.Background(), nil)
+	if err != ErrNoInstanceAvailable {
+		t.Errorf("waitForInstance() error = %v, want ErrNoInstanceAvailable", err)
+	}
+}
+
+func TestWaitForInstance_QueueFull(t *testing.T) {
+	pool := newQueueTestPool(1, time.Second)
+	defer pool.cancel()
+
+	go pool.waitForInstance(context.Background(), nil)
+	waitForQueueLength(t, pool, 1)
+
+	_, err := pool.waitForInstance(context.Background(), nil)
+	if !errors.Is(err, ErrQueueFull) {
+		t.Errorf("waitForInstance() error = %v, want ErrQueueFull", err)
+	}
+	if !errors.Is(err, ErrNoInstanceAvailable) {
+		t.Error("ErrQueueFull should wrap ErrNoInstanceAvailable")
+	}
+}
+
+func TestWaitForInstance_Timeout(t *testing.T) {
+	pool := newQueueTestPool(1, 50*time.Millisecond)
+	defer pool.cancel()
+
+	_, err := pool.waitForInstance(context.Background(), nil)
+	if !errors.Is(err, ErrQueueTimeout) {
+		t.Errorf("waitForInstance() error = %v, want ErrQueueTimeout", err)
+	}
+	if pool.queueLength() != 0 {
+		t.Errorf("queue length after timeout = %d, want 0", pool.queueLength())
+	}
+}
+
+func TestWaitForInstance_ContextCanceled(t *testing.T) {
+	pool := newQueueTestPool(1, time.Second)
+	defer pool.cancel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := pool.waitForInstance(ctx, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("waitForInstance() error = %v, want context.Canceled", err)
+	}
+	if pool.queueLength() != 0 {
+		t.Errorf("queue length after cancel = %d, want 0", pool.queueLength())
+	}
+}
+
+func TestWaitForInstance_FIFOHandoff(t *testing.T) {
+	pool := newQueueTestPool(4, time.Second)
+	defer pool.cancel()
+
+	var mu sync.Mutex
+	positions := map[string][]int{}
+	track := func(name string) func(int) {
+		return func(position int) {
+			mu.Lock()
+			positions[name] = append(positions[name], position)
+			mu.Unlock()
+		}
+	}
+
+	order := make(chan string, 2)
+	go func() {
+		pool.waitForInstance(context.Background(), track("first"))
+		order <- "first"
+	}()
+	waitForQueueLength(t, pool, 1)
+	go func() {
+		pool.waitForInstance(context.Background(), track("second"))
+		order <- "second"
+	}()
+	waitForQueueLength(t, pool, 2)
+
+	// Each released instance goes to the head of the queue, not to available
+	pool.returnToPool(0)
+	if got := <-order; got != "first" {
+		t.Errorf("first handoff went to %q, want first", got)
+	}
+	pool.returnToPool(0)
+	if got := <-order; got != "second" {
+		t.Errorf("second handoff went to %q, want second", got)
+	}
+	if len(pool.available) != 0 {
+		t.Errorf("available = %d, want 0 (instances handed to waiters)", len(pool.available))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(positions["first"]) != 1 || positions["first"][0] != 1 {
+		t.Errorf("first positions = %v, want [1]", positions["first"])
+	}
+	if len(positions["second"]) != 2 || positions["second"][0] != 2 || positions["second"][1] != 1 {
+		t.Errorf("second positions = %v, want [2 1]", positions["second"])
+	}
+}
+
+func TestReturnToPool_NoWaiters(t *testing.T) {
+	pool := newQueueTestPool(4, time.Second)
+	defer pool.cancel()
+
+	if !pool.returnToPool(0) {
+		t.Fatal("returnToPool() = false, want true")
+	}
+	if len(pool.available) != 1 {
+		t.Errorf("available = %d, want 1", len(pool.available))
+	}
+	// Available is full, a second return indicates a double release
+	if pool.returnToPool(0) {
+		t.Error("returnToPool() on full available = true, want false")
+	}
+}
+
+func newScalingTestPool(minInstances, maxInstances int, idleTimeout time.Duration) *ChromePool {
+	pool := newQueueTestPool(4, time.Second)
+	pool.config.MinInstances = minInstances
+	pool.config.MaxInstances = maxInstances
+	pool.config.IdleTimeout = idleTimeout
+	pool.instances = make([]*Instance, maxInstances)
+	pool.available = make(chan int, maxInstances)
+	pool.newInstance = func(id int) (*Instance, error) {
+		return &Instance{id: id, logger: zap.NewNop()}, nil
+	}
+	for i := 0; i < minInstances; i++ {
+		pool.instances[i] = &Instance{id: i, logger: zap.NewNop()}
+	}
+	return pool
+}
+
+func TestWaitForInstance_SpawnsWhenQueued(t *testing.T) {
+	pool := newScalingTestPool(1, 2, time.Minute)
+	defer pool.cancel()
+
+	// Instance 0 is busy, so the queued request gets a freshly spawned instance
+	id, err := pool.waitForInstance(context.Background(), nil)
+	if err != nil || id != 1 {
+		t.Fatalf("waitForInstance() = %d, %v, want 1, nil", id, err)
+	}
+
+	stats := pool.Stats()
+	if stats.TotalInstances != 2 {
+		t.Errorf("TotalInstances = %d, want 2", stats.TotalInstances)
+	}
+	if stats.SpawnedTotal != 1 {
+		t.Errorf("SpawnedTotal = %d, want 1", stats.SpawnedTotal)
+	}
+	if stats.SpawningInstances != 0 {
+		t.Errorf("SpawningInstances = %d, want 0", stats.SpawningInstances)
+	}
+
+	// Pool is at MaxInstances, so the next request waits for a release
+	_, err = pool.waitForInstance(context.Background(), nil)
+	if !errors.Is(err, ErrQueueTimeout) {
+		t.Errorf("waitForInstance() at max error = %v, want ErrQueueTimeout", err)
+	}
+	if got := pool.Stats().SpawnedTotal; got != 1 {
+		t.Errorf("SpawnedTotal at max = %d, want 1", got)
+	}
+}
+
+func TestWaitForInstance_SpawnFailure(t *testing.T) {
+	pool := newScalingTestPool(1, 2, time.Minute)
+	pool.config.QueueTimeout = 50 * time.Millisecond
+	defer pool.cancel()
+	pool.newInstance = func(id int) (*Instance, error) {
+		return nil, errors.New("chrome failed to start")
+	}
+
+	_, err := pool.waitForInstance(context.Background(), nil)
+	if !errors.Is(err, ErrQueueTimeout) {
+		t.Errorf("waitForInstance() error = %v, want ErrQueueTimeout", err)
+	}
+
+	// The spawn may still be waiting to retry
+	deadline := time.Now().Add(time.Second)
+	for pool.Stats().SpawningInstances != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	stats := pool.Stats()
+	if stats.TotalInstances != 1 || stats.SpawnedTotal != 0 || stats.SpawningInstances != 0 {
+		t.Errorf("Stats() = %+v, want 1 instance and no spawns", stats)
+	}
+}
+
+func TestWaitForInstance_QueueDisabledSpawns(t *testing.T) {
+	pool := newScalingTestPool(1, 2, time.Minute)
+	pool.config.QueueSize = 0
+	defer pool.cancel()
+
+	// No queue, but the pool can grow, so the request waits for a new instance
+	id, err := pool.waitForInstance(context.Background(), nil)
+	if err != nil || id != 1 {
+		t.Fatalf("waitForInstance() = %d, %v, want 1, nil", id, err)
+	}
+
+	// At MaxInstances the request is rejected right away
+	_, err = pool.waitForInstance(context.Background(), nil)
+	if err != ErrNoInstanceAvailable {
+		t.Errorf("waitForInstance() at max error = %v, want ErrNoInstanceAvailable", err)
+	}
+}
+
+func TestWaitForInstance_QueueDisabledSpawnFailure(t *testing.T) {
+	pool := newScalingTestPool(1, 2, time.Minute)
+	pool.config.QueueSize = 0
+	defer pool.cancel()
+	var attempts atomic.Int32
+	pool.newInstance = func(id int) (*Instance, error) {
+		attempts.Add(1)
+		return nil, errors.New("chrome failed to start")
+	}
+
+	// The request was only queued for the spawn, so it fails with it
+	_, err := pool.waitForInstance(context.Background(), nil)
+	if !errors.Is(err, ErrSpawnFailed) {
+		t.Errorf("waitForInstance() error = %v, want ErrSpawnFailed", err)
+	}
+	if got := attempts.Load(); got != maxSpawnAttempts {
+		t.Errorf("spawn attempts = %d, want %d", got, maxSpawnAttempts)
+	}
+	if pool.queueLength() != 0 {
+		t.Errorf("queue length = %d, want 0", pool.queueLength())
+	}
+}
+
+func TestReapIdle(t *testing.T) {
+	pool := newScalingTestPool(1, 3, 50*time.Millisecond)
+	defer pool.cancel()
+	pool.instances[1] = &Instance{id: 1, logger: zap.NewNop()}
+	pool.instances[2] = &Instance{id: 2, logger: zap.NewNop()}
+	pool.returnToPool(0)
+	pool.returnToPool(1)
+	pool.returnToPool(2)
+
+	// Nothing has been idle long enough yet
+	pool.reapIdle()
+	if got := pool.Stats().TotalInstances; got != 3 {
+		t.Fatalf("TotalInstances before idle timeout = %d, want 3", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	pool.reapIdle()
+
+	stats := pool.Stats()
+	if stats.TotalInstances != 1 {
+		t.Errorf("TotalInstances = %d, want MinInstances (1)", stats.TotalInstances)
+	}
+	if stats.AvailableInstances != 1 {
+		t.Errorf("AvailableInstances = %d, want 1", stats.AvailableInstances)
+	}
+	if stats.ReapedTotal != 2 {
+		t.Errorf("ReapedTotal = %d, want 2", stats.ReapedTotal)
+	}
+
+	// Reaped slots can be spawned again
+	if _, err := pool.waitForInstance(context.Background(), nil); err != nil {
+		t.Fatalf("waitForInstance() error = %v", err)
+	}
+	if _, err := pool.waitForInstance(context.Background(), nil); err != nil {
+		t.Fatalf("waitForInstance() after reap error = %v", err)
+	}
+	if got := pool.Stats().SpawnedTotal; got != 1 {
+		t.Errorf("SpawnedTotal = %d, want 1", got)
+	}
+}
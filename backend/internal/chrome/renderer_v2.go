@@ -2,8 +2,12 @@ package chrome
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
@@ -13,7 +17,6 @@ import (
 	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/css"
 	"github.com/chromedp/cdproto/dom"
-	"github.com/chromedp/cdproto/emulation"
 	"github.com/chromedp/cdproto/fetch"
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/cdproto/page"
@@ -21,13 +24,24 @@ import (
 	"github.com/chromedp/chromedp"
 	"go.uber.org/zap"
 
+	"github.com/user/jsbug/internal/security"
 	"github.com/user/jsbug/internal/types"
 )
 
 const (
 	maxConsoleErrorsSize = 5120 // Maximum total size of console error messages in bytes (5KB)
+	ssrfLookupTimeout    = 2 * time.Second
+
+	// renderGrace is how long a render may run past opts.Timeout to extract
+	// the HTML, take the screenshot and print the PDF
+	renderGrace = 10 * time.Second
 )
 
+// ErrSSRFBlocked is returned when the main document navigation (including a
+// redirect hop) targets a private/internal network address, or when any
+// response came from one after the host passed the check (DNS rebinding)
+var ErrSSRFBlocked = errors.New("navigation blocked by SSRF policy")
+
 // RenderOptions contains options for rendering a page
 type RenderOptions struct {
 	URL               string
@@ -36,7 +50,27 @@ type RenderOptions struct {
 	WaitEvent         string
 	Blocklist         *Blocklist
 	IsMobile          bool
+	Device            *types.Device      // Viewport, touch and platform hints, nil = desktop or mobile by IsMobile
+	Locale            string             // BCP 47 locale for Intl and Accept-Language, empty = Chrome default
+	Timezone          string             // IANA time zone, empty = server time zone
+	Geolocation       *types.Geolocation // Position reported to the page, nil = none
 	CaptureScreenshot bool
+	FollowRedirects   bool                       // Follow main document redirects; otherwise stop at the first 3xx
+	Headers           map[string]string          // Extra HTTP headers sent with requests to the target origin
+	Cookies           []types.Cookie             // Cookies set before navigation
+	Actions           []types.Action             // Page actions run after the wait event
+	ScrollToBottom    *types.ScrollOptions       // Scroll to load lazy content after actions, nil = off
+	Screenshot        *types.ScreenshotOptions   // Used with CaptureScreenshot, nil = viewport PNG
+	PDF               *types.PDFOptions          // Print the page to PDF, nil = off
+	HAR               *types.HAROptions          // Build a HAR of the network activity, nil = off
+	Snapshots         *types.SnapshotOptions     // Extract the HTML at lifecycle milestones, nil = off
+	ResponseBodies    *types.ResponseBodyOptions // Capture XHR/fetch response bodies, nil = off
+	DialogAction      string                     // accept or dismiss JavaScript dialogs, empty = dismiss
+	DialogPromptText  string                     // Answer to prompt() when accepting
+	Permissions       []string                   // Permissions granted to the page, the others are denied
+	WebVitals         bool                       // Collect Core Web Vitals and paint metrics
+	Throttling        *types.Throttling          // Network and CPU emulation, nil = off
+	ScriptsDisabled   bool                       // Render without page JavaScript (chrome_nojs mode)
 }
 
 // RenderResult contains the results of rendering a page
@@ -48,25 +82,48 @@ type RenderResult struct {
 	PageSizeBytes int
 	RenderTime    float64 // seconds
 	Network       []types.NetworkRequest
+	Document      *types.NetworkRequest // Main document request (final redirect hop)
 	Console       []types.ConsoleMessage
 	JSErrors      []types.JSError
 	Lifecycle     []types.LifecycleEvent
-	Screenshot    []byte `json:"-"` // PNG screenshot data, excluded from JSON serialization
+	RedirectChain []types.RedirectHop
+	Headers       map[string]string // Main document response headers, canonical keys
+	Actions       []types.ActionResult
+	Scroll        *types.ScrollResult
+	Screenshot    []byte `json:"-"` // Screenshot image data (PNG unless another format was requested), excluded from JSON serialization
+	PDF           []byte `json:"-"` // PDF data when requested, excluded from JSON serialization
+	HAR           *types.HAR
+	WebVitals     *types.WebVitals
+	Dialogs       []types.Dialog
+	Snapshots     []DOMSnapshot // HTML at lifecycle milestones, ending with "final"
+}
+
+// GetXRobotsTag returns the X-Robots-Tag header of the main document
+func (r *RenderResult) GetXRobotsTag() string {
+	return r.Headers["X-Robots-Tag"]
+}
+
+// GetLinkHeader returns the Link header of the main document
+func (r *RenderResult) GetLinkHeader() string {
+	return r.Headers["Link"]
 }
 
 // RendererV2 handles page rendering using Chrome with improved task-based architecture
 type RendererV2 struct {
-	instance  *Instance
-	logger    *zap.Logger
-	serviceID string
+	instance       *Instance
+	logger         *zap.Logger
+	serviceID      string
+	ssrfProtection bool
 }
 
-// NewRendererV2 creates a new RendererV2
+// NewRendererV2 creates a new RendererV2 that checks every request Chrome makes
+// (subresources, iframes, redirect hops) against the SSRF policy
 func NewRendererV2(instance *Instance, logger *zap.Logger) *RendererV2 {
 	return &RendererV2{
-		instance:  instance,
-		logger:    logger,
-		serviceID: "jsbug-renderer",
+		instance:       instance,
+		logger:         logger,
+		serviceID:      "jsbug-renderer",
+		ssrfProtection: true,
 	}
 }
 
@@ -80,9 +137,26 @@ type renderState struct {
 	lifecycle     []types.LifecycleEvent
 	timedOut      bool
 	screenshot    []byte
+	pdf           []byte
+	actions       []types.ActionResult
+	scroll        *types.ScrollResult
+	webVitals     *types.WebVitals
+	dialogs       []types.Dialog
+	ssrfHosts     map[string]error     // Per-render cache of SSRF host checks
+	ssrfBlocked   bool                 // Main document navigation was blocked, or a response came from a private address
+	buildHAR      bool                 // HAR export requested
+	bodies        *responseBodyCapture // XHR/fetch body capture, nil = off
+	snapshots     *domSnapshots        // DOM snapshots at lifecycle milestones, nil = off
+	deadline      time.Time            // opts.Timeout after start, shared by the wait, actions, scroll and HAR bodies
 	mu            sync.Mutex
 }
 
+// budget returns a context for a render step that ends at the shared
+// deadline, so steps together never take longer than opts.Timeout
+func (s *renderState) budget(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithDeadline(ctx, s.deadline)
+}
+
 // Render navigates to a URL and captures page data using the task-based pattern
 func (r *RendererV2) Render(ctx context.Context, opts RenderOptions) (*RenderResult, error) {
 	startTime := time.Now()
@@ -91,19 +165,30 @@ func (r *RendererV2) Render(ctx context.Context, opts RenderOptions) (*RenderRes
 	tabCtx, tabCancel := r.instance.GetContext()
 	defer tabCancel()
 
-	// Cancel tab when request context times out or is cancelled
+	// Steps share a soft deadline of opts.Timeout; the hard deadline adds
+	// renderGrace for extraction so the whole render stays bounded
+	deadline := startTime.Add(opts.Timeout)
+	ctx, cancel := context.WithDeadline(ctx, deadline.Add(renderGrace))
+	defer cancel()
+
+	// Cancel tab when the request context or the hard deadline ends
 	// This allows both soft timeout (in navigation) and hard timeout (via context) to work
 	stop := context.AfterFunc(ctx, tabCancel)
 	defer stop()
 
 	// Initialize render state
 	state := &renderState{
-		headers: make(map[string]string),
+		headers:   make(map[string]string),
+		ssrfHosts: make(map[string]error),
+		buildHAR:  opts.HAR != nil,
+		deadline:  deadline,
 	}
 
 	// Create event collector for network/console data
 	collector := NewEventCollector(r.logger)
 	collector.SetPageURL(opts.URL)
+	state.bodies = newResponseBodyCapture(opts.ResponseBodies, collector, r.logger)
+	state.snapshots = newDOMSnapshots(opts.Snapshots)
 
 	// Track active fetch handler goroutines
 	var fetchHandlerCount int64
@@ -116,22 +201,31 @@ func (r *RendererV2) Render(ctx context.Context, opts RenderOptions) (*RenderRes
 	// Check hard timeout FIRST (highest priority - prevents shadowing by redirect cancellation)
 	if errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(ctx.Err(), context.Canceled) {
 		return &RenderResult{
-			HTML:       state.html,
-			FinalURL:   state.finalURL,
-			StatusCode: state.statusCode,
-			RenderTime: renderTime.Seconds(),
-			Network:    collector.GetNetworkResults(),
-			Console:    collector.GetConsoleResults(),
-			JSErrors:   collector.GetJSErrors(),
-			Lifecycle:  state.lifecycle,
+			HTML:          state.html,
+			FinalURL:      state.finalURL,
+			StatusCode:    state.statusCode,
+			RenderTime:    renderTime.Seconds(),
+			Network:       collector.GetNetworkResults(),
+			Console:       collector.GetConsoleResults(),
+			JSErrors:      collector.GetJSErrors(),
+			Lifecycle:     state.lifecycle,
+			RedirectChain: collector.GetRedirectChain(),
+			Dialogs:       state.dialogs,
 		}, fmt.Errorf("hard timeout exceeded: %w", ctx.Err())
 	}
 
 	// Check redirect cancellation (intentional cancel with 3xx status code captured)
 	state.mu.Lock()
 	statusCode := state.statusCode
+	ssrfBlocked := state.ssrfBlocked
 	state.mu.Unlock()
 
+	// Main document was blocked by SSRF policy - navigation cannot produce a valid page
+	if ssrfBlocked && !(statusCode >= 300 && statusCode < 400) {
+		result := r.buildResult(state, collector, renderTime)
+		return result, fmt.Errorf("%w: %s", ErrSSRFBlocked, opts.URL)
+	}
+
 	if errors.Is(err, context.Canceled) && statusCode >= 300 && statusCode < 400 {
 		// Redirect detected - return success with captured data
 		return r.buildResult(state, collector, renderTime), nil
@@ -171,16 +265,36 @@ func (r *RendererV2) buildResult(state *renderState, collector *EventCollector,
 		PageSizeBytes: len(state.html),
 		RenderTime:    renderTime.Seconds(),
 		Network:       collector.GetNetworkResults(),
+		Document:      collector.GetDocumentRequest(),
 		Console:       collector.GetConsoleResults(),
 		JSErrors:      collector.GetJSErrors(),
 		Lifecycle:     state.lifecycle,
+		RedirectChain: collector.GetRedirectChain(),
+		Headers:       state.headers,
+		Actions:       state.actions,
+		Scroll:        state.scroll,
 		Screenshot:    state.screenshot,
+		PDF:           state.pdf,
+		WebVitals:     state.webVitals,
+		Dialogs:       state.dialogs,
+		Snapshots:     state.snapshots.results(),
 	}
 
-	// Get redirect info if a redirect was detected
-	redirectURL, _ := collector.GetRedirectInfo()
-	if redirectURL != "" {
-		result.RedirectURL = redirectURL
+	if state.buildHAR {
+		title := state.finalURL
+		if title == "" {
+			title = collector.pageURL
+		}
+		result.HAR = collector.BuildHAR(title)
+	}
+
+	// Get redirect info if the render stopped at a redirect (not followed, or a loop)
+	if result.StatusCode >= 300 && result.StatusCode < 400 {
+		if n := len(result.RedirectChain); n > 0 {
+			result.RedirectURL = result.RedirectChain[n-1].Location
+		} else if redirectURL, _ := collector.GetRedirectInfo(); redirectURL != "" {
+			result.RedirectURL = redirectURL
+		}
 	}
 
 	return result
@@ -201,6 +315,13 @@ func (r *RendererV2) buildTasks(opts RenderOptions, state *renderState, collecto
 					go func(event *fetch.EventRequestPaused) {
 						defer atomic.AddInt64(fetchHandlerCount, -1)
 
+						// Check SSRF policy before creating the CDP command context,
+						// DNS resolution may take most of the lookup budget
+						var ssrfErr error
+						if r.ssrfProtection {
+							ssrfErr = r.checkSSRF(ctx, state, event.Request.URL)
+						}
+
 						// Create timeout context for CDP commands to prevent goroutine leaks
 						cmdCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
 						defer cancel()
@@ -209,6 +330,30 @@ func (r *RendererV2) buildTasks(opts RenderOptions, state *renderState, collecto
 						c := chromedp.FromContext(cmdCtx)
 						ctxExecutor := cdp.WithExecutor(cmdCtx, c.Target)
 
+						if ssrfErr != nil {
+							r.logger.Warn("SSRF blocked request",
+								zap.String("url", event.Request.URL),
+								zap.String("resource_type", string(event.ResourceType)),
+								zap.Error(ssrfErr))
+
+							err := fetch.FailRequest(event.RequestID, network.ErrorReasonAccessDenied).Do(ctxExecutor)
+							if err != nil {
+								r.logger.Warn("Failed to block request",
+									zap.String("url", event.Request.URL),
+									zap.Error(err))
+							}
+
+							collector.markBlocked(string(event.NetworkID), event.Request.URL, string(event.ResourceType), types.BlockedReasonSSRF)
+
+							// Main frame document: the navigation itself (or one of its redirect hops) was blocked
+							if event.ResourceType == network.ResourceTypeDocument && string(event.FrameID) == string(c.Target.TargetID) {
+								state.mu.Lock()
+								state.ssrfBlocked = true
+								state.mu.Unlock()
+							}
+							return
+						}
+
 						// Check if request should be blocked
 						shouldBlock := opts.Blocklist != nil && opts.Blocklist.ShouldBlock(event.Request.URL, string(event.ResourceType))
 
@@ -222,23 +367,15 @@ func (r *RendererV2) buildTasks(opts RenderOptions, state *renderState, collecto
 							}
 
 							// Track blocked request in collector
-							collector.mu.Lock()
-							if req, ok := collector.networkRequests[string(event.NetworkID)]; ok {
-								req.Blocked = true
-							} else {
-								collector.networkRequests[string(event.NetworkID)] = &NetworkRequestData{
-									RequestID:    string(event.NetworkID),
-									URL:          event.Request.URL,
-									ResourceType: string(event.ResourceType),
-									Blocked:      true,
-									StartTime:    time.Now(),
-									EndTime:      time.Now(),
-								}
-							}
-							collector.mu.Unlock()
+							collector.markBlocked(string(event.NetworkID), event.Request.URL, string(event.ResourceType), types.BlockedReasonBlocklist)
 						} else {
-							// Allow the request to continue
-							err := fetch.ContinueRequest(event.RequestID).Do(ctxExecutor)
+							// Allow the request to continue. Custom headers (e.g. Authorization)
+							// are only sent to the target origin, not to third-party subresources.
+							continueParams := fetch.ContinueRequest(event.RequestID)
+							if len(opts.Headers) > 0 && types.CustomHeadersAllowed(opts.URL, event.Request.URL) {
+								continueParams = continueParams.WithHeaders(mergeHeaders(event.Request.Headers, opts.Headers))
+							}
+							err := continueParams.Do(ctxExecutor)
 							if err != nil {
 								r.logger.Warn("Failed to continue request, failing instead to prevent hang",
 									zap.String("url", event.Request.URL),
@@ -249,17 +386,31 @@ func (r *RendererV2) buildTasks(opts RenderOptions, state *renderState, collecto
 						}
 					}(ev)
 
+				case *page.EventJavascriptDialogOpening:
+					// Answer the dialog in a goroutine, the listener must not block
+					go r.handleDialog(ctx, opts, state, ev, timeOrigin)
+
 				case *network.EventRequestWillBeSent:
 					collector.handleRequestWillBeSent(ev)
 
-					// Check for redirect
-					if ev.RedirectResponse != nil &&
-						urlsMatchIgnoringFragment(ev.RedirectResponse.URL, opts.URL) &&
-						ev.DocumentURL == ev.Request.URL &&
-						ev.RedirectResponse.Status != 0 {
+					if ev.RedirectResponse == nil || ev.RedirectResponse.Status == 0 {
+						break
+					}
+
+					// Check for redirect: stop at the first hop when not following,
+					// or when a followed chain keeps coming back to the same URL
+					var stop bool
+					if opts.FollowRedirects {
+						stop = collector.isMainDocumentRequest(string(ev.RequestID)) && collector.redirectLoopDetected()
+					} else {
+						stop = urlsMatchIgnoringFragment(ev.RedirectResponse.URL, opts.URL) &&
+							ev.DocumentURL == ev.Request.URL
+					}
+					if stop {
 						state.mu.Lock()
 						state.statusCode = int(ev.RedirectResponse.Status)
 						state.finalURL = ev.Request.URL
+						state.headers = headersToMap(ev.RedirectResponse.Headers)
 						state.mu.Unlock()
 
 						// Cancel to abort navigation on redirect
@@ -275,35 +426,41 @@ func (r *RendererV2) buildTasks(opts RenderOptions, state *renderState, collecto
 				case *network.EventResponseReceived:
 					collector.handleResponseReceived(ev)
 
-					// Capture initial response status code and headers
+					// Chrome resolves hosts on its own, so a host that passed
+					// checkSSRF can still rebind to a private address; the
+					// page may already hold the response, so the render fails
+					if r.ssrfProtection && isPrivateRemoteIP(ev.Response.RemoteIPAddress) {
+						r.logger.Warn("SSRF blocked response from private address",
+							zap.String("url", ev.Response.URL),
+							zap.String("remote_ip", ev.Response.RemoteIPAddress))
+						collector.markBlocked(string(ev.RequestID), ev.Response.URL, string(ev.Type), types.BlockedReasonSSRF)
+						state.mu.Lock()
+						state.ssrfBlocked = true
+						state.mu.Unlock()
+						if err := chromedp.Cancel(ctx); err != nil {
+							r.logger.Warn("Unable to cancel chrome instance on SSRF block",
+								zap.String("url", opts.URL))
+						}
+						break
+					}
+
+					// Capture main document status code and headers (final hop when following redirects)
+					isMainDocument := collector.isMainDocumentRequest(string(ev.RequestID))
 					state.mu.Lock()
-					if urlsMatchIgnoringFragment(ev.Response.URL, opts.URL) && state.statusCode == 0 {
+					if (isMainDocument || urlsMatchIgnoringFragment(ev.Response.URL, opts.URL)) && state.statusCode == 0 {
 						state.statusCode = int(ev.Response.Status)
 
 						// Capture response headers
-						if ev.Response.Headers != nil {
-							for key, value := range ev.Response.Headers {
-								switch v := value.(type) {
-								case string:
-									state.headers[key] = v
-								case []interface{}:
-									strValues := make([]string, 0, len(v))
-									for _, item := range v {
-										if str, ok := item.(string); ok {
-											strValues = append(strValues, str)
-										}
-									}
-									if len(strValues) > 0 {
-										state.headers[key] = strings.Join(strValues, ", ")
-									}
-								}
-							}
-						}
+						state.headers = headersToMap(ev.Response.Headers)
 					}
 					state.mu.Unlock()
 
 				case *network.EventLoadingFinished:
 					collector.handleLoadingFinished(ev)
+					state.bodies.capture(ctx, ev.RequestID)
+
+				case *network.EventDataReceived:
+					collector.handleDataReceived(ev)
 
 				case *network.EventLoadingFailed:
 					collector.handleLoadingFailed(ev)
@@ -357,6 +514,8 @@ func (r *RendererV2) buildTasks(opts RenderOptions, state *renderState, collecto
 						Time:  float64(delta) / 1000.0,
 					})
 					state.mu.Unlock()
+
+					r.captureSnapshot(ctx, opts, state, string(ev.Name), float64(delta)/1000.0)
 				}
 			})
 			return nil
@@ -364,44 +523,44 @@ func (r *RendererV2) buildTasks(opts RenderOptions, state *renderState, collecto
 
 		network.Enable(),
 
-		// Enable fetch interception for request blocking
+		// Enable fetch interception for every request: SSRF checks apply even
+		// when the blocklist is empty, custom headers are added per request,
+		// and redirect hops are paused individually
 		chromedp.ActionFunc(func(ctx context.Context) error {
-			if opts.Blocklist != nil && !opts.Blocklist.IsEmpty() {
-				patterns := []*fetch.RequestPattern{
-					{RequestStage: fetch.RequestStageRequest},
-				}
-				return fetch.Enable().WithPatterns(patterns).Do(ctx)
+			if !r.ssrfProtection && len(opts.Headers) == 0 && (opts.Blocklist == nil || opts.Blocklist.IsEmpty()) {
+				return nil
 			}
-			return nil
+			patterns := []*fetch.RequestPattern{
+				{RequestStage: fetch.RequestStageRequest},
+			}
+			return fetch.Enable().WithPatterns(patterns).Do(ctx)
 		}),
 
 		network.ClearBrowserCookies(),
+
+		// Apply custom cookies (after clearing cookies, before navigation)
+		r.applyCookies(opts),
+
 		page.Enable(),
 		css.Disable(),
 
 		r.enableLifeCycle(),
 
-		// Set user agent
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			if opts.UserAgent != "" {
-				return emulation.SetUserAgentOverride(opts.UserAgent).Do(ctx)
-			}
-			return nil
-		}),
+		// Set user agent, viewport and touch
+		r.emulateDevice(opts),
 
-		// Set viewport
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			width, height := DesktopWidth, DesktopHeight
-			if opts.IsMobile {
-				width, height = MobileWidth, MobileHeight
-			}
-			return emulation.SetDeviceMetricsOverride(
-				int64(width),
-				int64(height),
-				1.0,
-				opts.IsMobile,
-			).Do(ctx)
-		}),
+		// Set locale, time zone and geolocation
+		r.emulateLocale(opts),
+		r.grantPermissions(opts),
+
+		// Emulate a slow network and CPU
+		r.applyThrottling(opts),
+
+		// Turn off page JavaScript before the first script can run
+		r.disableScripts(opts),
+
+		// Register web vitals observers before any page script runs
+		r.installWebVitals(opts),
 
 		// Navigate and wait for page ready (with soft timeout)
 		r.navigateAndWait(opts, state, collector),
@@ -409,8 +568,21 @@ func (r *RendererV2) buildTasks(opts RenderOptions, state *renderState, collecto
 		chromedp.WaitReady("body", chromedp.ByQuery),
 		chromedp.WaitVisible("body", chromedp.ByQuery),
 
+		// Run scripted page actions (click, type, scroll...) before capture
+		r.runActions(opts, state),
+
+		// Scroll to trigger lazy-loaded content (after actions so cookie walls
+		// that lock scrolling can be dismissed first)
+		r.scrollToBottom(opts, state, collector),
+
+		// Read web vitals once the page is fully loaded and interacted with
+		r.collectWebVitals(opts, state),
+
 		r.extractHTML(&state.html),
 
+		// Close the snapshot timeline with the final HTML
+		r.finishSnapshots(opts, state, timeOrigin),
+
 		chromedp.Location(&state.finalURL),
 
 		// Fallback status code retrieval (if event listener missed it)
@@ -455,13 +627,12 @@ func (r *RendererV2) buildTasks(opts RenderOptions, state *renderState, collecto
 			return nil
 		}),
 
-		// Capture screenshot (viewport only, PNG format) - only when requested
+		// Capture screenshot (viewport, full page or element) - only when requested
 		chromedp.ActionFunc(func(ctx context.Context) error {
 			if !opts.CaptureScreenshot {
 				return nil
 			}
-			var screenshotBuf []byte
-			err := chromedp.CaptureScreenshot(&screenshotBuf).Do(ctx)
+			screenshotBuf, err := captureScreenshot(ctx, opts.Screenshot)
 			if err != nil {
 				r.logger.Warn("Failed to capture screenshot",
 					zap.String("url", opts.URL),
@@ -477,6 +648,15 @@ func (r *RendererV2) buildTasks(opts RenderOptions, state *renderState, collecto
 			return nil
 		}),
 
+		// Print to PDF - only when requested
+		r.printPDF(opts, state),
+
+		// Wait for response bodies still being read
+		r.waitForResponseBodies(opts, state),
+
+		// Fetch response bodies for the HAR export, reusing those read above - only when requested
+		r.captureHARBodies(opts, state, collector),
+
 		// Wait for all fetch handlers to complete BEFORE closing page
 		chromedp.ActionFunc(func(ctx context.Context) error {
 			timeout := time.After(5 * time.Second)
@@ -524,10 +704,10 @@ func (r *RendererV2) navigateAndWait(opts RenderOptions, state *renderState, col
 		if waitEvent == "" {
 			waitEvent = "load"
 		}
-		err = r.waitForLifecycleEvent(ctx, waitEvent, collector, opts.Timeout)
+		err = r.waitForLifecycleEvent(ctx, waitEvent, collector, time.Until(state.deadline))
 
 		// If timeout occurred, mark it but don't fail
-		if err != nil && err.Error() == "wait timeout exceeded" {
+		if errors.Is(err, errWaitTimeout) {
 			state.mu.Lock()
 			state.timedOut = true
 			state.mu.Unlock()
@@ -546,8 +726,20 @@ func (r *RendererV2) navigateAndWait(opts RenderOptions, state *renderState, col
 	}
 }
 
-// waitForLifecycleEvent waits for a specific lifecycle event with frame/loader matching
+// errWaitTimeout is returned when the wait event does not occur within the
+// render timeout. Renders continue with HTML extraction.
+var errWaitTimeout = errors.New("wait timeout exceeded")
+
+// waitPollInterval is how often condition wait events are evaluated
+const waitPollInterval = 100 * time.Millisecond
+
+// waitForLifecycleEvent waits for a specific lifecycle event with frame/loader matching.
+// Condition wait events (selector:, js:, domStable) are polled in the page instead.
 func (r *RendererV2) waitForLifecycleEvent(ctx context.Context, eventName string, collector *EventCollector, timeout time.Duration) error {
+	if cond, ok := types.ParseWaitCondition(eventName); ok {
+		return r.waitForCondition(ctx, cond, timeout)
+	}
+
 	ch := make(chan struct{})
 
 	listenerCtx, cancel := context.WithCancel(ctx)
@@ -576,7 +768,74 @@ func (r *RendererV2) waitForLifecycleEvent(ctx context.Context, eventName string
 	case <-ctx.Done():
 		return ctx.Err()
 	case <-time.After(timeout):
-		return fmt.Errorf("wait timeout exceeded")
+		return errWaitTimeout
+	}
+}
+
+// waitForCondition polls a condition wait event until it is true.
+// Evaluation errors (e.g. during a client-side navigation) count as false.
+func (r *RendererV2) waitForCondition(ctx context.Context, cond types.WaitCondition, timeout time.Duration) error {
+	expression := waitConditionExpression(cond)
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		result, exception, err := cdpruntime.Evaluate(expression).
+			WithReturnByValue(true).
+			WithAwaitPromise(true).
+			Do(waitCtx)
+		if err == nil && exception == nil && result != nil && string(result.Value) == "true" {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-waitCtx.Done():
+			return errWaitTimeout
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitConditionExpression builds the JS expression polled for a condition.
+// Selectors are embedded as JSON strings; expressions run as written, closed
+// on a new line so a trailing // comment cannot swallow the wrapper.
+func waitConditionExpression(cond types.WaitCondition) string {
+	switch cond.Kind {
+	case types.WaitKindSelector:
+		selector, _ := json.Marshal(cond.Value)
+		return fmt.Sprintf(`(() => {
+	let el;
+	try { el = document.querySelector(%s); } catch (e) { return false; }
+	if (!el) return false;
+	const style = window.getComputedStyle(el);
+	if (style.display === 'none' || style.visibility === 'hidden') return false;
+	const rect = el.getBoundingClientRect();
+	return rect.width > 0 || rect.height > 0;
+})()`, selector)
+	case types.WaitKindJS:
+		return fmt.Sprintf(`(async () => {
+	try { return !!(await (%s
+	)); } catch (e) { return false; }
+})()`, cond.Value)
+	default:
+		// domStable: the observer is installed on the first poll and the
+		// quiet period restarts on every mutation
+		return fmt.Sprintf(`(() => {
+	if (document.readyState === 'loading') return false;
+	if (!window.__jsbugDomStable) {
+		const state = { last: performance.now() };
+		new MutationObserver(() => { state.last = performance.now(); })
+			.observe(document, { subtree: true, childList: true, attributes: true, characterData: true });
+		window.__jsbugDomStable = state;
+	}
+	return performance.now() - window.__jsbugDomStable.last >= %d;
+})()`, cond.QuietPeriod.Milliseconds())
 	}
 }
 
@@ -610,6 +869,108 @@ func (r *RendererV2) extractHTML(output *string) chromedp.ActionFunc {
 	}
 }
 
+// headersToMap converts CDP response headers to a flat map with canonical keys.
+// Chrome joins repeated headers with newlines; they are rejoined with
+// types.JoinHeaderValues to match the HTTP fetch mode.
+func headersToMap(headers network.Headers) map[string]string {
+	result := make(map[string]string, len(headers))
+	for key, value := range headers {
+		name := http.CanonicalHeaderKey(key)
+		switch v := value.(type) {
+		case string:
+			result[name] = types.JoinHeaderValues(name, strings.Split(v, "\n"))
+		case []interface{}:
+			strValues := make([]string, 0, len(v))
+			for _, item := range v {
+				if str, ok := item.(string); ok {
+					strValues = append(strValues, str)
+				}
+			}
+			if len(strValues) > 0 {
+				result[name] = types.JoinHeaderValues(name, strValues)
+			}
+		}
+	}
+	return result
+}
+
+// applyCookies sets custom cookies for the tab. Cookies without a domain are
+// scoped to the target URL host. Custom headers are added by the fetch
+// handler, only to requests for the target origin.
+func (r *RendererV2) applyCookies(opts RenderOptions) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if len(opts.Cookies) > 0 {
+			params := make([]*network.CookieParam, 0, len(opts.Cookies))
+			for _, c := range opts.Cookies {
+				param := &network.CookieParam{
+					Name:  c.Name,
+					Value: c.Value,
+					Path:  c.Path,
+				}
+				if param.Path == "" {
+					param.Path = "/"
+				}
+				if c.Domain != "" {
+					param.Domain = c.Domain
+				} else {
+					param.URL = opts.URL
+				}
+				params = append(params, param)
+			}
+			if err := network.SetCookies(params).Do(ctx); err != nil {
+				return fmt.Errorf("set cookies failed: %w", err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// checkSSRF validates the host of a request URL against the SSRF policy.
+// Results are cached per host for the duration of the render. Non-network
+// schemes (data:, blob:, about:) are always allowed.
+func (r *RendererV2) checkSSRF(ctx context.Context, state *renderState, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "ws", "wss":
+	default:
+		return nil
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return fmt.Errorf("URL has no hostname")
+	}
+
+	state.mu.Lock()
+	cached, ok := state.ssrfHosts[host]
+	state.mu.Unlock()
+	if ok {
+		return cached
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, ssrfLookupTimeout)
+	defer cancel()
+	err = security.ValidateHost(lookupCtx, host)
+
+	state.mu.Lock()
+	state.ssrfHosts[host] = err
+	state.mu.Unlock()
+
+	return err
+}
+
+// isPrivateRemoteIP reports whether Chrome's remote address for a response
+// (IPv6 in brackets) is private. Empty for cached and data: responses.
+func isPrivateRemoteIP(remoteIP string) bool {
+	ip := net.ParseIP(strings.Trim(remoteIP, "[]"))
+	return ip != nil && security.IsPrivateIP(ip)
+}
+
 // enableLifeCycle enables page lifecycle events
 func (r *RendererV2) enableLifeCycle() chromedp.ActionFunc {
 	return func(ctx context.Context) error {
@@ -3,19 +3,35 @@
 package chrome
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/chromedp/chromedp"
 	"go.uber.org/zap"
 
 	"github.com/user/jsbug/internal/types"
 )
 
+// newTestRendererV2 creates a RendererV2 without SSRF protection, so it can
+// render pages from the loopback test server
+func newTestRendererV2(instance *Instance, logger *zap.Logger) *RendererV2 {
+	r := NewRendererV2(instance, logger)
+	r.ssrfProtection = false
+	return r
+}
+
 func setupV2TestServer() *httptest.Server {
 	mux := http.NewServeMux()
 
@@ -41,11 +57,41 @@ func setupV2TestServer() *httptest.Server {
 		fmt.Fprint(w, "<html><body>Status page</body></html>")
 	})
 
+	// Page with robots and Link headers
+	mux.HandleFunc("/headers", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("X-Robots-Tag", "noindex")
+		w.Header().Set("Link", `<https://example.com/canonical>; rel="canonical"`)
+		fmt.Fprint(w, "<html><head><title>Headers</title></head><body>Headers</body></html>")
+	})
+
 	// Redirect page
 	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/simple", http.StatusFound)
 	})
 
+	// Redirects to /set-session until the session cookie is set
+	mux.HandleFunc("/session", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("session"); err != nil {
+			http.Redirect(w, r, "/set-session", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><head><title>Session</title></head><body>Logged in</body></html>")
+	})
+	mux.HandleFunc("/set-session", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "1", Path: "/"})
+		http.Redirect(w, r, "/session", http.StatusFound)
+	})
+
+	// Endless redirect loop
+	mux.HandleFunc("/loop-a", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop-b", http.StatusFound)
+	})
+	mux.HandleFunc("/loop-b", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop-a", http.StatusFound)
+	})
+
 	// Slow page
 	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(2 * time.Second)
@@ -69,6 +115,64 @@ console.log('Hello from console');
 </html>`)
 	})
 
+	// Page that renders content and keeps changing the DOM after load
+	mux.HandleFunc("/late", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html>
+<html>
+<head><title>Late Page</title></head>
+<body>
+<div id="app"></div>
+<script>
+let step = 0;
+const timer = setInterval(() => {
+	step++;
+	document.getElementById('app').textContent = 'Step ' + step;
+	if (step === 5) {
+		clearInterval(timer);
+		document.body.insertAdjacentHTML('beforeend', '<div id="late">Late content</div>');
+		window.appReady = true;
+	}
+}, 100);
+</script>
+</body>
+</html>`)
+	})
+
+	// Page with content behind a button and a search form
+	mux.HandleFunc("/interactive", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html>
+<html>
+<head><title>Interactive Page</title></head>
+<body>
+<button id="more" onclick="document.body.insertAdjacentHTML('beforeend', '<p id=extra>Extra content</p>')">Load more</button>
+<input id="q" onkeydown="if (event.key === 'Enter') document.title = 'Search: ' + this.value">
+</body>
+</html>`)
+	})
+
+	// Infinite scroll page that appends a page of items near the bottom
+	mux.HandleFunc("/infinite", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html>
+<html>
+<head><title>Infinite Page</title></head>
+<body>
+<div id="feed" style="height: 2000px"><a href="/item/0">Item 0</a></div>
+<script>
+let pages = 0;
+window.addEventListener('scroll', () => {
+	if (pages >= 2 || window.scrollY + window.innerHeight < document.body.scrollHeight - 100) return;
+	pages++;
+	document.getElementById('feed').insertAdjacentHTML('beforeend',
+		'<div style="height: 2000px"><a href="/item/' + pages + '">Item ' + pages + '</a></div>');
+});
+</script>
+</body>
+</html>`)
+	})
+
 	// Page with console messages
 	mux.HandleFunc("/console", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/html")
@@ -145,7 +249,7 @@ func TestRendererV2_SimpleRender(t *testing.T) {
 	}
 	defer instance.Close()
 
-	renderer := NewRendererV2(instance, logger)
+	renderer := newTestRendererV2(instance, logger)
 
 	result, err := renderer.Render(context.Background(), RenderOptions{
 		URL:       server.URL + "/simple",
@@ -174,6 +278,302 @@ func TestRendererV2_SimpleRender(t *testing.T) {
 	}
 }
 
+func TestRendererV2_ConditionWaitEvents(t *testing.T) {
+	server := setupV2TestServer()
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	for _, waitEvent := range []string{"selector:#late", "js:window.appReady === true", "domStable:300"} {
+		t.Run(waitEvent, func(t *testing.T) {
+			result, err := renderer.Render(context.Background(), RenderOptions{
+				URL:       server.URL + "/late",
+				Timeout:   10 * time.Second,
+				WaitEvent: waitEvent,
+			})
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if !strings.Contains(result.HTML, "Late content") {
+				t.Error("HTML should contain content added after load")
+			}
+		})
+	}
+}
+
+func TestRendererV2_ConditionWaitSoftTimeout(t *testing.T) {
+	server := setupV2TestServer()
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	// The selector never matches: the render continues after the timeout
+	result, err := renderer.Render(context.Background(), RenderOptions{
+		URL:       server.URL + "/simple",
+		Timeout:   500 * time.Millisecond,
+		WaitEvent: "selector:#missing",
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(result.HTML, "Hello World") {
+		t.Error("HTML should be extracted after the wait times out")
+	}
+}
+
+func TestRendererV2_Actions(t *testing.T) {
+	server := setupV2TestServer()
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	result, err := renderer.Render(context.Background(), RenderOptions{
+		URL:       server.URL + "/interactive",
+		Timeout:   10 * time.Second,
+		WaitEvent: types.WaitLoad,
+		Actions: []types.Action{
+			{Type: types.ActionClick, Selector: "#cookie-wall", TimeoutMs: 300, Optional: true},
+			{Type: types.ActionClick, Selector: "#more"},
+			{Type: types.ActionWaitForSelector, Selector: "#extra"},
+			{Type: types.ActionType, Selector: "#q", Text: "shoes"},
+			{Type: types.ActionPressKey, Selector: "#q", Key: "Enter"},
+			{Type: types.ActionEvaluate, Script: "document.title"},
+			{Type: types.ActionEvaluate, Script: "missingFunction()"},
+			{Type: types.ActionScroll},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if !strings.Contains(result.HTML, "Extra content") {
+		t.Error("HTML should contain content loaded by the click")
+	}
+
+	wantStatus := []string{
+		types.ActionStatusError, // optional, missing element
+		types.ActionStatusSuccess,
+		types.ActionStatusSuccess,
+		types.ActionStatusSuccess,
+		types.ActionStatusSuccess,
+		types.ActionStatusSuccess,
+		types.ActionStatusError, // script error stops the sequence
+		types.ActionStatusSkipped,
+	}
+	if len(result.Actions) != len(wantStatus) {
+		t.Fatalf("len(Actions) = %d, want %d", len(result.Actions), len(wantStatus))
+	}
+	for i, want := range wantStatus {
+		if result.Actions[i].Status != want {
+			t.Errorf("Actions[%d].Status = %q (%s), want %q", i, result.Actions[i].Status, result.Actions[i].Error, want)
+		}
+	}
+	if got := string(result.Actions[5].Result); got != `"Search: shoes"` {
+		t.Errorf("evaluate result = %s, want \"Search: shoes\"", got)
+	}
+}
+
+func TestRendererV2_ScrollToBottom(t *testing.T) {
+	server := setupV2TestServer()
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	result, err := renderer.Render(context.Background(), RenderOptions{
+		URL:            server.URL + "/infinite",
+		Timeout:        15 * time.Second,
+		WaitEvent:      types.WaitLoad,
+		ScrollToBottom: &types.ScrollOptions{MaxSteps: 20, StepDelayMs: 100},
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if result.Scroll == nil {
+		t.Fatal("Scroll should be set")
+	}
+	if result.Scroll.StopReason != types.ScrollStopBottom {
+		t.Errorf("StopReason = %q, want %q", result.Scroll.StopReason, types.ScrollStopBottom)
+	}
+	if result.Scroll.FinalHeight <= result.Scroll.InitialHeight {
+		t.Errorf("FinalHeight = %d, want more than InitialHeight %d", result.Scroll.FinalHeight, result.Scroll.InitialHeight)
+	}
+	if !strings.Contains(result.HTML, "Item 2") {
+		t.Error("HTML should contain items loaded by scrolling")
+	}
+	if len(result.Scroll.InitialLinks) != 1 {
+		t.Errorf("len(InitialLinks) = %d, want 1", len(result.Scroll.InitialLinks))
+	}
+}
+
+func TestRendererV2_ScreenshotOptions(t *testing.T) {
+	server := setupV2TestServer()
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	tests := []struct {
+		name       string
+		path       string
+		opts       *types.ScreenshotOptions
+		wantFormat string
+		check      func(t *testing.T, cfg image.Config)
+	}{
+		{
+			name:       "full page capped at max_height",
+			path:       "/infinite",
+			opts:       &types.ScreenshotOptions{FullPage: true, Format: types.ScreenshotFormatJPEG, MaxHeight: 1500},
+			wantFormat: "jpeg",
+			check: func(t *testing.T, cfg image.Config) {
+				if cfg.Height != 1500 {
+					t.Errorf("height = %d, want 1500", cfg.Height)
+				}
+			},
+		},
+		{
+			name:       "element clip",
+			path:       "/interactive",
+			opts:       &types.ScreenshotOptions{Selector: "#more"},
+			wantFormat: "png",
+			check: func(t *testing.T, cfg image.Config) {
+				if cfg.Width >= 400 || cfg.Height >= 100 {
+					t.Errorf("size = %dx%d, want the button only", cfg.Width, cfg.Height)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := renderer.Render(context.Background(), RenderOptions{
+				URL:               server.URL + tt.path,
+				Timeout:           10 * time.Second,
+				WaitEvent:         types.WaitLoad,
+				CaptureScreenshot: true,
+				Screenshot:        tt.opts,
+			})
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(result.Screenshot))
+			if err != nil {
+				t.Fatalf("DecodeConfig() error = %v", err)
+			}
+			if format != tt.wantFormat {
+				t.Errorf("format = %q, want %q", format, tt.wantFormat)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestRendererV2_PrintPDF(t *testing.T) {
+	server := setupV2TestServer()
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	result, err := renderer.Render(context.Background(), RenderOptions{
+		URL:       server.URL + "/simple",
+		Timeout:   10 * time.Second,
+		WaitEvent: types.WaitLoad,
+		PDF:       &types.PDFOptions{Paper: "letter", Landscape: true, PrintBackground: true},
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if !bytes.HasPrefix(result.PDF, []byte("%PDF-")) {
+		t.Errorf("PDF should start with %%PDF-, got %d bytes", len(result.PDF))
+	}
+	if len(result.Screenshot) != 0 {
+		t.Error("Screenshot should not be captured when only a PDF is requested")
+	}
+}
+
+func TestRendererV2_HAR(t *testing.T) {
+	server := setupV2TestServer()
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	result, err := renderer.Render(context.Background(), RenderOptions{
+		URL:             server.URL + "/redirect",
+		Timeout:         10 * time.Second,
+		WaitEvent:       types.WaitLoad,
+		FollowRedirects: true,
+		HAR:             &types.HAROptions{IncludeBodies: true},
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if result.HAR == nil {
+		t.Fatal("HAR should be set")
+	}
+	entries := result.HAR.Log.Entries
+	if len(entries) < 2 {
+		t.Fatalf("len(Entries) = %d, want the redirect hop and the page", len(entries))
+	}
+	if entries[0].Response.Status != http.StatusFound || !strings.HasSuffix(entries[0].Response.RedirectURL, "/simple") {
+		t.Errorf("first entry = %d -> %q, want 302 -> /simple", entries[0].Response.Status, entries[0].Response.RedirectURL)
+	}
+	if entries[1].Response.Status != http.StatusOK || !strings.Contains(entries[1].Response.Content.Text, "<html") {
+		t.Errorf("second entry = %d with %d body bytes, want 200 with the HTML body",
+			entries[1].Response.Status, len(entries[1].Response.Content.Text))
+	}
+}
+
 func TestRendererV2_UserAgentApplied(t *testing.T) {
 	var receivedUA string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -189,7 +589,7 @@ func TestRendererV2_UserAgentApplied(t *testing.T) {
 	}
 	defer instance.Close()
 
-	renderer := NewRendererV2(instance, logger)
+	renderer := newTestRendererV2(instance, logger)
 	customUA := "CustomBot/1.0"
 
 	_, err = renderer.Render(context.Background(), RenderOptions{
@@ -219,7 +619,7 @@ func TestRendererV2_SoftTimeout(t *testing.T) {
 	}
 	defer instance.Close()
 
-	renderer := NewRendererV2(instance, logger)
+	renderer := newTestRendererV2(instance, logger)
 
 	// Use a very short soft timeout for the wait event
 	result, err := renderer.Render(context.Background(), RenderOptions{
@@ -252,7 +652,7 @@ func TestRendererV2_HardTimeout(t *testing.T) {
 	}
 	defer instance.Close()
 
-	renderer := NewRendererV2(instance, logger)
+	renderer := newTestRendererV2(instance, logger)
 
 	// Create context with hard timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
@@ -284,7 +684,7 @@ func TestRendererV2_NetworkEventsCapture(t *testing.T) {
 	}
 	defer instance.Close()
 
-	renderer := NewRendererV2(instance, logger)
+	renderer := newTestRendererV2(instance, logger)
 
 	result, err := renderer.Render(context.Background(), RenderOptions{
 		URL:       server.URL + "/resources",
@@ -324,7 +724,7 @@ func TestRendererV2_BlockedRequests(t *testing.T) {
 	}
 	defer instance.Close()
 
-	renderer := NewRendererV2(instance, logger)
+	renderer := newTestRendererV2(instance, logger)
 
 	// Block images
 	blocklist := NewBlocklist(false, false, false, []string{"image"})
@@ -356,7 +756,7 @@ func TestRendererV2_BlockedRequests(t *testing.T) {
 	}
 }
 
-func TestRendererV2_LifecycleEvents(t *testing.T) {
+func TestRendererV2_SSRFProtection(t *testing.T) {
 	server := setupV2TestServer()
 	defer server.Close()
 
@@ -369,6 +769,74 @@ func TestRendererV2_LifecycleEvents(t *testing.T) {
 
 	renderer := NewRendererV2(instance, logger)
 
+	t.Run("main document", func(t *testing.T) {
+		_, err := renderer.Render(context.Background(), RenderOptions{
+			URL:       server.URL + "/simple",
+			Timeout:   10 * time.Second,
+			WaitEvent: types.WaitLoad,
+		})
+		if !errors.Is(err, ErrSSRFBlocked) {
+			t.Errorf("Render() error = %v, want ErrSSRFBlocked", err)
+		}
+	})
+
+	t.Run("subresource", func(t *testing.T) {
+		result, err := renderer.Render(context.Background(), RenderOptions{
+			URL:       `data:text/html,<html><body><img src="` + server.URL + `/image.png"></body></html>`,
+			Timeout:   10 * time.Second,
+			WaitEvent: types.WaitLoad,
+		})
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+
+		found := false
+		for _, req := range result.Network {
+			if strings.Contains(req.URL, "image.png") {
+				found = true
+				if !req.Blocked || req.BlockedReason != types.BlockedReasonSSRF {
+					t.Errorf("image Blocked = %v, BlockedReason = %q, want blocked by ssrf", req.Blocked, req.BlockedReason)
+				}
+			}
+		}
+		if !found {
+			t.Error("Network should include the blocked image request")
+		}
+	})
+}
+
+func TestIsPrivateRemoteIP(t *testing.T) {
+	tests := []struct {
+		remoteIP string
+		want     bool
+	}{
+		{remoteIP: "127.0.0.1", want: true},
+		{remoteIP: "10.1.2.3", want: true},
+		{remoteIP: "[::1]", want: true},
+		{remoteIP: "93.184.216.34", want: false},
+		{remoteIP: "[2606:2800:220:1:248:1893:25c8:1946]", want: false},
+		{remoteIP: "", want: false},
+	}
+	for _, tt := range tests {
+		if got := isPrivateRemoteIP(tt.remoteIP); got != tt.want {
+			t.Errorf("isPrivateRemoteIP(%q) = %v, want %v", tt.remoteIP, got, tt.want)
+		}
+	}
+}
+
+func TestRendererV2_LifecycleEvents(t *testing.T) {
+	server := setupV2TestServer()
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
 	result, err := renderer.Render(context.Background(), RenderOptions{
 		URL:       server.URL + "/simple",
 		Timeout:   10 * time.Second,
@@ -414,7 +882,7 @@ func TestRendererV2_Redirect(t *testing.T) {
 	}
 	defer instance.Close()
 
-	renderer := NewRendererV2(instance, logger)
+	renderer := newTestRendererV2(instance, logger)
 
 	result, err := renderer.Render(context.Background(), RenderOptions{
 		URL:       server.URL + "/redirect",
@@ -437,7 +905,7 @@ func TestRendererV2_Redirect(t *testing.T) {
 	}
 }
 
-func TestRendererV2_HTMLExtraction(t *testing.T) {
+func TestRendererV2_FollowRedirects(t *testing.T) {
 	server := setupV2TestServer()
 	defer server.Close()
 
@@ -448,40 +916,188 @@ func TestRendererV2_HTMLExtraction(t *testing.T) {
 	}
 	defer instance.Close()
 
-	renderer := NewRendererV2(instance, logger)
+	renderer := newTestRendererV2(instance, logger)
 
 	result, err := renderer.Render(context.Background(), RenderOptions{
-		URL:       server.URL + "/js",
-		Timeout:   10 * time.Second,
-		WaitEvent: types.WaitLoad,
+		URL:             server.URL + "/redirect",
+		Timeout:         10 * time.Second,
+		WaitEvent:       types.WaitLoad,
+		FollowRedirects: true,
 	})
 
 	if err != nil {
 		t.Fatalf("Render() error = %v", err)
 	}
 
-	// Check that JavaScript executed and modified DOM
-	if !strings.Contains(result.HTML, "JavaScript executed") {
-		t.Error("HTML does not contain JS-modified content")
+	if result.StatusCode != 200 {
+		t.Errorf("StatusCode = %d, want 200 (redirect followed)", result.StatusCode)
 	}
-
-	if result.PageSizeBytes <= 0 {
-		t.Error("PageSizeBytes should be > 0")
+	if len(result.RedirectChain) != 1 {
+		t.Fatalf("len(RedirectChain) = %d, want 1", len(result.RedirectChain))
+	}
+	hop := result.RedirectChain[0]
+	if hop.StatusCode != 302 || hop.Location != server.URL+"/simple" {
+		t.Errorf("hop = %+v, want 302 -> /simple", hop)
+	}
+	if result.RedirectURL != "" {
+		t.Errorf("RedirectURL = %q, want empty when redirect was followed", result.RedirectURL)
 	}
 }
 
-func TestRendererV2_IsAvailable(t *testing.T) {
+func TestRendererV2_FollowRedirects_BackWithCookie(t *testing.T) {
+	server := setupV2TestServer()
+	defer server.Close()
+
 	logger := zap.NewNop()
 	instance, err := New(0, newTestConfig(), logger)
 	if err != nil {
 		t.Fatalf("Failed to create instance: %v", err)
 	}
+	defer instance.Close()
 
-	renderer := NewRendererV2(instance, logger)
-
-	if !renderer.IsAvailable() {
-		t.Error("Renderer should be available")
-	}
+	renderer := newTestRendererV2(instance, logger)
+
+	result, err := renderer.Render(context.Background(), RenderOptions{
+		URL:             server.URL + "/session",
+		Timeout:         10 * time.Second,
+		WaitEvent:       types.WaitLoad,
+		FollowRedirects: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	// /session -> /set-session (sets cookie) -> /session returns the page
+	if result.StatusCode != 200 {
+		t.Errorf("StatusCode = %d, want 200 (chain back to /session followed)", result.StatusCode)
+	}
+	if !strings.Contains(result.HTML, "Logged in") {
+		t.Error("HTML should contain the page served once the cookie is set")
+	}
+	if len(result.RedirectChain) != 2 {
+		t.Fatalf("len(RedirectChain) = %d, want 2", len(result.RedirectChain))
+	}
+	if flags := types.AnnotateRedirectChain(result.RedirectChain); !flags.Loop {
+		t.Error("RedirectFlags.Loop = false, want true for the hop back to /session")
+	}
+	if result.RedirectURL != "" {
+		t.Errorf("RedirectURL = %q, want empty when redirect was followed", result.RedirectURL)
+	}
+}
+
+func TestRendererV2_FollowRedirects_Loop(t *testing.T) {
+	server := setupV2TestServer()
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	result, err := renderer.Render(context.Background(), RenderOptions{
+		URL:             server.URL + "/loop-a",
+		Timeout:         10 * time.Second,
+		WaitEvent:       types.WaitLoad,
+		FollowRedirects: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Render() error = %v, want loop to stop without error", err)
+	}
+
+	// /loop-a is revisited once, then stopped on the way to its third visit
+	if result.StatusCode != 302 {
+		t.Errorf("StatusCode = %d, want 302", result.StatusCode)
+	}
+	if len(result.RedirectChain) != 4 {
+		t.Errorf("len(RedirectChain) = %d, want 4", len(result.RedirectChain))
+	}
+}
+
+func TestRendererV2_ResponseHeaders(t *testing.T) {
+	server := setupV2TestServer()
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	result, err := renderer.Render(context.Background(), RenderOptions{
+		URL:       server.URL + "/headers",
+		Timeout:   10 * time.Second,
+		WaitEvent: types.WaitLoad,
+	})
+
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if result.GetXRobotsTag() != "noindex" {
+		t.Errorf("GetXRobotsTag() = %q, want %q", result.GetXRobotsTag(), "noindex")
+	}
+	if !strings.Contains(result.GetLinkHeader(), "rel=\"canonical\"") {
+		t.Errorf("GetLinkHeader() = %q, want canonical link", result.GetLinkHeader())
+	}
+	if result.Headers["Content-Type"] != "text/html" {
+		t.Errorf("Headers[Content-Type] = %q, want %q", result.Headers["Content-Type"], "text/html")
+	}
+}
+
+func TestRendererV2_HTMLExtraction(t *testing.T) {
+	server := setupV2TestServer()
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	result, err := renderer.Render(context.Background(), RenderOptions{
+		URL:       server.URL + "/js",
+		Timeout:   10 * time.Second,
+		WaitEvent: types.WaitLoad,
+	})
+
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	// Check that JavaScript executed and modified DOM
+	if !strings.Contains(result.HTML, "JavaScript executed") {
+		t.Error("HTML does not contain JS-modified content")
+	}
+
+	if result.PageSizeBytes <= 0 {
+		t.Error("PageSizeBytes should be > 0")
+	}
+}
+
+func TestRendererV2_IsAvailable(t *testing.T) {
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+
+	renderer := newTestRendererV2(instance, logger)
+
+	if !renderer.IsAvailable() {
+		t.Error("Renderer should be available")
+	}
 
 	instance.Close()
 
@@ -492,7 +1108,7 @@ func TestRendererV2_IsAvailable(t *testing.T) {
 
 func TestRendererV2_NilInstance(t *testing.T) {
 	logger := zap.NewNop()
-	renderer := NewRendererV2(nil, logger)
+	renderer := newTestRendererV2(nil, logger)
 
 	if renderer.IsAvailable() {
 		t.Error("Renderer with nil instance should not be available")
@@ -510,7 +1126,7 @@ func TestRendererV2_ViewportConfiguration(t *testing.T) {
 	}
 	defer instance.Close()
 
-	renderer := NewRendererV2(instance, logger)
+	renderer := newTestRendererV2(instance, logger)
 
 	// Test with mobile viewport
 	result, err := renderer.Render(context.Background(), RenderOptions{
@@ -540,7 +1156,7 @@ func TestRendererV2_ConsoleMessages(t *testing.T) {
 	}
 	defer instance.Close()
 
-	renderer := NewRendererV2(instance, logger)
+	renderer := newTestRendererV2(instance, logger)
 
 	result, err := renderer.Render(context.Background(), RenderOptions{
 		URL:       server.URL + "/console",
@@ -568,7 +1184,7 @@ func TestRendererV2_JSErrors(t *testing.T) {
 	}
 	defer instance.Close()
 
-	renderer := NewRendererV2(instance, logger)
+	renderer := newTestRendererV2(instance, logger)
 
 	result, err := renderer.Render(context.Background(), RenderOptions{
 		URL:       server.URL + "/error",
@@ -599,3 +1215,628 @@ func TestRendererV2_JSErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestRendererV2_WebVitals(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html>
+<html>
+<head><title>Vitals Page</title></head>
+<body>
+<h1 id="headline" style="font-size: 64px">Largest text on the page</h1>
+<p id="shifted">Shifted paragraph</p>
+<script>
+const start = performance.now();
+while (performance.now() - start < 120) {}
+setTimeout(() => {
+	document.getElementById('headline').insertAdjacentHTML('afterend', '<div style="height: 200px">Banner</div>');
+}, 200);
+</script>
+</body>
+</html>`)
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	result, err := renderer.Render(context.Background(), RenderOptions{
+		URL:       server.URL,
+		Timeout:   10 * time.Second,
+		WaitEvent: "domStable:500",
+		WebVitals: true,
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	vitals := result.WebVitals
+	if vitals == nil {
+		t.Fatal("WebVitals should be set")
+	}
+	if vitals.FCPMs <= 0 || vitals.LCPMs <= 0 {
+		t.Errorf("FCP = %v, LCP = %v, want both > 0", vitals.FCPMs, vitals.LCPMs)
+	}
+	if vitals.LCPElement != "#headline" {
+		t.Errorf("LCPElement = %q, want #headline", vitals.LCPElement)
+	}
+	if vitals.CLS <= 0 || len(vitals.LayoutShifts) == 0 {
+		t.Errorf("CLS = %v with %d shifts, want the banner shift", vitals.CLS, len(vitals.LayoutShifts))
+	}
+	if len(vitals.LongTasks) == 0 {
+		t.Error("LongTasks should include the 120ms busy loop")
+	}
+	if _, ok := vitals.PerformanceMetrics["TaskDuration"]; !ok {
+		t.Errorf("PerformanceMetrics = %v, want TaskDuration", vitals.PerformanceMetrics)
+	}
+}
+
+// webVitalsFakeObserverScript replaces PerformanceObserver so tests can feed
+// the observer script controlled entry sequences through window.__feed
+const webVitalsFakeObserverScript = `(() => {
+	const callbacks = {};
+	window.PerformanceObserver = class {
+		constructor(cb) { this.cb = cb; }
+		observe(opts) { callbacks[opts.type] = this.cb; }
+	};
+	window.__feed = (type, entries) => callbacks[type]({ getEntries: () => entries });
+	return true;
+})()`
+
+func TestWebVitalsObserverScript_CLSAndTBT(t *testing.T) {
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	shift := func(time, value float64, input bool) string {
+		return fmt.Sprintf(`__feed('layout-shift', [{ startTime: %v, value: %v, hadRecentInput: %v, sources: [] }]);`, time, value, input)
+	}
+	longTask := func(start, duration float64) string {
+		return fmt.Sprintf(`__feed('longtask', [{ startTime: %v, duration: %v }]);`, start, duration)
+	}
+	fcp := func(time float64) string {
+		return fmt.Sprintf(`__feed('paint', [{ name: 'first-contentful-paint', startTime: %v }]);`, time)
+	}
+
+	tests := []struct {
+		name    string
+		feed    []string
+		wantCLS float64
+		wantTBT float64
+	}{
+		{name: "no entries"},
+		{
+			name:    "single window",
+			feed:    []string{shift(100, 0.1, false), shift(600, 0.05, false)},
+			wantCLS: 0.15,
+		},
+		{
+			name:    "largest window wins after a 1s gap",
+			feed:    []string{shift(100, 0.1, false), shift(2000, 0.2, false), shift(2500, 0.05, false)},
+			wantCLS: 0.25,
+		},
+		{
+			name: "window capped at 5s",
+			feed: []string{
+				shift(0, 0.1, false), shift(900, 0.1, false), shift(1800, 0.1, false),
+				shift(2700, 0.1, false), shift(3600, 0.1, false), shift(4500, 0.1, false),
+				shift(5400, 0.1, false),
+			},
+			wantCLS: 0.6,
+		},
+		{
+			name:    "input shifts ignored",
+			feed:    []string{shift(100, 0.1, false), shift(200, 0.5, true)},
+			wantCLS: 0.1,
+		},
+		{
+			name: "long tasks after FCP",
+			feed: []string{
+				fcp(500),
+				longTask(100, 200), // Before FCP
+				longTask(450, 150), // Straddles FCP: 100ms after it, 50 blocking
+				longTask(1000, 80), // 30 blocking
+				longTask(2000, 40), // Not a blocking task
+			},
+			wantTBT: 80,
+		},
+		{
+			name:    "long task seen before FCP is known",
+			feed:    []string{longTask(450, 150), fcp(500)},
+			wantTBT: 50,
+		},
+		{
+			name:    "no FCP counts long tasks from 0",
+			feed:    []string{longTask(100, 120)},
+			wantTBT: 70,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tabCtx, cancel := instance.GetContext()
+			defer cancel()
+			ctx, cancelTimeout := context.WithTimeout(tabCtx, 10*time.Second)
+			defer cancelTimeout()
+
+			var ok bool
+			var snapshot webVitalsSnapshot
+			err := chromedp.Run(ctx,
+				chromedp.Navigate("about:blank"),
+				chromedp.Evaluate(webVitalsFakeObserverScript, &ok),
+				chromedp.Evaluate(webVitalsObserverScript+";true", &ok),
+				chromedp.Evaluate("(() => {"+strings.Join(tt.feed, "\n")+" return true; })()", &ok),
+				chromedp.ActionFunc(func(ctx context.Context) error {
+					return evaluateJSON(ctx, webVitalsCollectScript, &snapshot)
+				}),
+			)
+			if err != nil {
+				t.Fatalf("chromedp.Run() error = %v", err)
+			}
+
+			vitals := snapshot.toWebVitals()
+			if math.Abs(vitals.CLS-tt.wantCLS) > 1e-9 {
+				t.Errorf("CLS = %v, want %v", vitals.CLS, tt.wantCLS)
+			}
+			if math.Abs(vitals.TBTMs-tt.wantTBT) > 1e-9 {
+				t.Errorf("TBT = %v, want %v", vitals.TBTMs, tt.wantTBT)
+			}
+		})
+	}
+}
+
+func TestRendererV2_Throttling(t *testing.T) {
+	server := setupV2TestServer()
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	throttling, err := types.ResolveThrottling(types.NetworkProfileCustom, &types.NetworkConditions{LatencyMs: 300}, 2)
+	if err != nil {
+		t.Fatalf("ResolveThrottling() error = %v", err)
+	}
+
+	start := time.Now()
+	result, err := renderer.Render(context.Background(), RenderOptions{
+		URL:        server.URL + "/simple",
+		Timeout:    10 * time.Second,
+		WaitEvent:  types.WaitLoad,
+		Throttling: throttling,
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if result.StatusCode != 200 {
+		t.Errorf("StatusCode = %d, want 200", result.StatusCode)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Errorf("render took %v, want at least the emulated 300ms latency", elapsed)
+	}
+}
+
+func TestRendererV2_DeviceProfile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html>
+<html>
+<head><title>Device Page</title></head>
+<body>
+<div id="device"></div>
+<script>
+document.getElementById('device').textContent =
+	window.innerWidth + 'x' + window.innerHeight + '@' + window.devicePixelRatio +
+	' touch=' + navigator.maxTouchPoints + ' platform=' + navigator.platform;
+</script>
+</body>
+</html>`)
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	device, err := types.ResolveDevice(types.DevicePixel8, 0, 0, 0, false)
+	if err != nil {
+		t.Fatalf("ResolveDevice() error = %v", err)
+	}
+
+	result, err := renderer.Render(context.Background(), RenderOptions{
+		URL:       server.URL,
+		UserAgent: device.UserAgent,
+		Timeout:   10 * time.Second,
+		WaitEvent: types.WaitLoad,
+		IsMobile:  device.Mobile,
+		Device:    device,
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := "412x915@2.625 touch=5 platform=Linux armv8l"
+	if !strings.Contains(result.HTML, want) {
+		t.Errorf("HTML does not report the emulated device %q", want)
+	}
+}
+
+func TestRendererV2_LocaleEmulation(t *testing.T) {
+	var receivedLang string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedLang = r.Header.Get("Accept-Language")
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html>
+<html>
+<head><title>Locale Page</title></head>
+<body>
+<div id="locale"></div>
+<div id="geo"></div>
+<script>
+document.getElementById('locale').textContent = navigator.language + ' ' +
+	Intl.DateTimeFormat().resolvedOptions().locale + ' ' +
+	Intl.DateTimeFormat().resolvedOptions().timeZone;
+navigator.geolocation.getCurrentPosition(p => {
+	document.getElementById('geo').textContent = 'geo=' + p.coords.latitude + ',' + p.coords.longitude;
+	window.geoReady = true;
+}, () => { window.geoReady = true; });
+</script>
+</body>
+</html>`)
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	result, err := renderer.Render(context.Background(), RenderOptions{
+		URL:         server.URL,
+		Timeout:     10 * time.Second,
+		WaitEvent:   "js:window.geoReady === true",
+		Locale:      "de-DE",
+		Timezone:    "Asia/Tokyo",
+		Geolocation: &types.Geolocation{Latitude: 52.52, Longitude: 13.405, Accuracy: 100},
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if receivedLang != "de-DE,de;q=0.9" {
+		t.Errorf("Accept-Language = %q, want %q", receivedLang, "de-DE,de;q=0.9")
+	}
+	if !strings.Contains(result.HTML, "de-DE de-DE Asia/Tokyo") {
+		t.Error("HTML does not report the emulated locale and time zone")
+	}
+	if !strings.Contains(result.HTML, "geo=52.52,13.405") {
+		t.Error("HTML does not report the emulated geolocation")
+	}
+}
+
+func TestRendererV2_ResponseBodies(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html>
+<html>
+<head><title>SPA</title></head>
+<body>
+<ul id="products"></ul>
+<script>
+Promise.all([
+	fetch('/api/products').then(r => r.text()),
+	fetch('/api/config').then(r => r.text()),
+]).then(() => { window.apiDone = true; });
+</script>
+</body>
+</html>`)
+	})
+	mux.HandleFunc("/api/products", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"error":"bots not allowed"}`)
+	})
+	mux.HandleFunc("/api/config", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"theme":"dark"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	opts := &types.ResponseBodyOptions{URLPatterns: []string{"/api/products"}}
+	opts.ApplyDefaults()
+
+	result, err := renderer.Render(context.Background(), RenderOptions{
+		URL:            server.URL,
+		Timeout:        10 * time.Second,
+		WaitEvent:      "js:window.apiDone === true",
+		ResponseBodies: opts,
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	var products, config *types.NetworkRequest
+	for i := range result.Network {
+		switch result.Network[i].URL {
+		case server.URL + "/api/products":
+			products = &result.Network[i]
+		case server.URL + "/api/config":
+			config = &result.Network[i]
+		}
+	}
+	if products == nil || config == nil {
+		t.Fatal("API requests missing from network results")
+	}
+	if products.ResponseBody == nil || products.ResponseBody.Body != `{"error":"bots not allowed"}` {
+		t.Errorf("products ResponseBody = %+v, want the 403 body", products.ResponseBody)
+	}
+	if config.ResponseBody != nil {
+		t.Errorf("config ResponseBody = %+v, want nil (filtered out by url_patterns)", config.ResponseBody)
+	}
+}
+
+func TestRendererV2_Dialogs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html>
+<html>
+<head><title>Dialog Page</title></head>
+<body>
+<div id="answers"></div>
+<script>
+alert('Welcome!');
+const ok = confirm('Accept cookies?');
+const name = prompt('Your name?', 'guest');
+document.getElementById('answers').textContent = 'confirm=' + ok + ' prompt=' + name;
+</script>
+</body>
+</html>`)
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	for _, tt := range []struct {
+		action string
+		want   string
+	}{
+		{types.DialogActionAccept, "confirm=true prompt=jsbug"},
+		{types.DialogActionDismiss, "confirm=false prompt=null"},
+	} {
+		t.Run(tt.action, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+			defer cancel()
+
+			result, err := renderer.Render(ctx, RenderOptions{
+				URL:              server.URL,
+				Timeout:          5 * time.Second,
+				WaitEvent:        types.WaitLoad,
+				DialogAction:     tt.action,
+				DialogPromptText: "jsbug",
+			})
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if !strings.Contains(result.HTML, tt.want) {
+				t.Errorf("HTML does not contain %q", tt.want)
+			}
+			if len(result.Dialogs) != 3 {
+				t.Fatalf("len(Dialogs) = %d, want 3", len(result.Dialogs))
+			}
+			if d := result.Dialogs[1]; d.Type != "confirm" || d.Message != "Accept cookies?" || d.Action != tt.action {
+				t.Errorf("Dialogs[1] = %+v", d)
+			}
+		})
+	}
+}
+
+func TestRendererV2_ScriptsDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html>
+<html>
+<head><title>App Shell</title></head>
+<body>
+<div id="app"></div>
+<noscript><h1>JavaScript is off</h1></noscript>
+<script>document.getElementById('app').innerHTML = '<h1>Rendered by JS</h1>';</script>
+</body>
+</html>`)
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	result, err := renderer.Render(ctx, RenderOptions{
+		URL:               server.URL,
+		Timeout:           5 * time.Second,
+		WaitEvent:         types.WaitLoad,
+		CaptureScreenshot: true,
+		ScriptsDisabled:   true,
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if strings.Contains(result.HTML, "Rendered by JS") {
+		t.Error("page script ran with scripts disabled")
+	}
+	if !strings.Contains(result.HTML, "<h1>JavaScript is off</h1>") {
+		t.Error("HTML should contain the noscript content as markup")
+	}
+	if len(result.Screenshot) == 0 {
+		t.Error("expected a screenshot")
+	}
+	if len(result.Network) == 0 {
+		t.Error("expected network requests")
+	}
+}
+
+func TestRendererV2_Snapshots(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html>
+<html>
+<head><title>Loading</title></head>
+<body>
+<div id="app"></div>
+<script>
+window.addEventListener('load', () => setTimeout(() => {
+	document.title = 'Product';
+	document.getElementById('app').innerHTML = '<h1>Late Heading</h1>';
+}, 300));
+</script>
+</body>
+</html>`)
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := renderer.Render(ctx, RenderOptions{
+		URL:       server.URL,
+		Timeout:   8 * time.Second,
+		WaitEvent: "domStable:1000",
+		Snapshots: &types.SnapshotOptions{Events: []string{"DOMContentLoaded", "load"}},
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	byEvent := map[string]string{}
+	for _, s := range result.Snapshots {
+		byEvent[s.Event] = s.HTML
+	}
+	if html, ok := byEvent["DOMContentLoaded"]; !ok || strings.Contains(html, "Late Heading") {
+		t.Error("DOMContentLoaded snapshot missing or already has the late heading")
+	}
+	if _, ok := byEvent["load"]; !ok {
+		t.Error("load snapshot missing")
+	}
+	last := result.Snapshots[len(result.Snapshots)-1]
+	if last.Event != types.SnapshotFinal || !strings.Contains(last.HTML, "Late Heading") {
+		t.Errorf("last snapshot = %q, want final with the late heading", last.Event)
+	}
+}
+
+func TestRendererV2_CustomHeadersTargetOriginOnly(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string]string{}
+	record := func(name string, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		received[name] = r.Header.Get("Authorization")
+	}
+
+	// A different port is a different origin
+	thirdParty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record("third-party", r)
+		w.Header().Set("Content-Type", "application/javascript")
+		fmt.Fprint(w, "window.thirdPartyLoaded = true;")
+	}))
+	defer thirdParty.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		record("document", r)
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><head><script src="/app.js"></script><script src="%s/lib.js"></script></head><body>Page</body></html>`, thirdParty.URL)
+	})
+	mux.HandleFunc("/app.js", func(w http.ResponseWriter, r *http.Request) {
+		record("same-origin", r)
+		w.Header().Set("Content-Type", "application/javascript")
+		fmt.Fprint(w, "window.appLoaded = true;")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	logger := zap.NewNop()
+	instance, err := New(0, newTestConfig(), logger)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+	defer instance.Close()
+
+	renderer := newTestRendererV2(instance, logger)
+
+	_, err = renderer.Render(context.Background(), RenderOptions{
+		URL:       server.URL,
+		Timeout:   10 * time.Second,
+		WaitEvent: types.WaitLoad,
+		Headers:   map[string]string{"Authorization": "Bearer secret"},
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, name := range []string{"document", "same-origin"} {
+		if received[name] != "Bearer secret" {
+			t.Errorf("%s Authorization = %q, want the custom header", name, received[name])
+		}
+	}
+	if got, ok := received["third-party"]; !ok || got != "" {
+		t.Errorf("third-party Authorization = %q (requested %v), want the request without it", got, ok)
+	}
+}
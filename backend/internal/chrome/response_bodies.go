@@ -0,0 +1,188 @@
+package chrome
+
+import (
+	"context"
+	"encoding/base64"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+const (
+	responseBodyTimeout     = 5 * time.Second // Per GetResponseBody call
+	responseBodyWaitTimeout = 5 * time.Second // For reads still running at the end of the render
+)
+
+// responseBodyCapture reads XHR and fetch response bodies as soon as they
+// finish loading, before Chrome evicts them or the page navigates away. All
+// methods are no-ops on a nil capture.
+type responseBodyCapture struct {
+	opts      *types.ResponseBodyOptions
+	collector *EventCollector
+	logger    *zap.Logger
+	wg        sync.WaitGroup
+
+	mu       sync.Mutex
+	total    int  // Bytes captured so far
+	finished bool // Set once the render stops waiting for reads
+}
+
+// newResponseBodyCapture returns nil when no capture was requested
+func newResponseBodyCapture(opts *types.ResponseBodyOptions, collector *EventCollector, logger *zap.Logger) *responseBodyCapture {
+	if opts == nil {
+		return nil
+	}
+	return &responseBodyCapture{opts: opts, collector: collector, logger: logger}
+}
+
+// capture reads the body of a finished request in the background when it is
+// an XHR or fetch that passes the URL and MIME type filters
+func (c *responseBodyCapture) capture(ctx context.Context, requestID network.RequestID) {
+	if c == nil {
+		return
+	}
+	url, mimeType, ok := c.collector.responseBodyCandidate(string(requestID))
+	if !ok || !c.opts.Matches(url, mimeType) {
+		return
+	}
+	started, budgetLeft := c.start()
+	if !budgetLeft {
+		c.collector.setCapturedBody(string(requestID), &types.ResponseBody{Skipped: types.BodySkippedTotalBudget})
+		return
+	}
+	if !started {
+		return
+	}
+
+	go func() {
+		defer c.wg.Done()
+
+		cmdCtx, cancel := context.WithTimeout(ctx, responseBodyTimeout)
+		defer cancel()
+
+		executor := cdp.WithExecutor(cmdCtx, chromedp.FromContext(cmdCtx).Target)
+		body, err := network.GetResponseBody(requestID).Do(executor)
+		if err != nil {
+			c.logger.Debug("Failed to get response body",
+				zap.String("url", url),
+				zap.Error(err))
+			c.collector.setCapturedBody(string(requestID), &types.ResponseBody{Skipped: types.BodySkippedUnavailable})
+			return
+		}
+		c.collector.setCapturedBody(string(requestID), c.take(body, mimeType))
+	}()
+}
+
+// start counts a read in wg unless the render finished waiting for reads or
+// the total budget is used up. Adding under mu keeps wg.Add from racing the
+// final wg.Wait.
+func (c *responseBodyCapture) start() (started, budgetLeft bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.finished {
+		return false, true
+	}
+	if c.opts.MaxTotalBytes-c.total <= 0 {
+		return false, false
+	}
+	c.wg.Add(1)
+	return true, true
+}
+
+// finish stops new reads so wg.Wait can run
+func (c *responseBodyCapture) finish() {
+	c.mu.Lock()
+	c.finished = true
+	c.mu.Unlock()
+}
+
+// take truncates a body to the per-body limit and the remaining total budget
+// and charges it against the budget
+func (c *responseBodyCapture) take(body []byte, mimeType string) *types.ResponseBody {
+	c.mu.Lock()
+	limit := min(c.opts.MaxBodyBytes, c.opts.MaxTotalBytes-c.total)
+	if limit <= 0 {
+		c.mu.Unlock()
+		return &types.ResponseBody{Size: len(body), Skipped: types.BodySkippedTotalBudget}
+	}
+	kept := body
+	if len(kept) > limit {
+		kept = kept[:limit]
+	}
+	text := isTextMimeType(mimeType) && utf8.Valid(body)
+	if text {
+		// Do not split a multi-byte character
+		for len(kept) > 0 && len(kept) < len(body) && !utf8.RuneStart(body[len(kept)]) {
+			kept = kept[:len(kept)-1]
+		}
+	}
+	c.total += len(kept)
+	c.mu.Unlock()
+
+	result := &types.ResponseBody{Size: len(body), Truncated: len(kept) < len(body)}
+	if text {
+		result.Body = string(kept)
+	} else {
+		result.Body = base64.StdEncoding.EncodeToString(kept)
+		result.Base64 = true
+	}
+	return result
+}
+
+// waitForResponseBodies stops new body reads and waits for those still in
+// flight so they finish before the page closes
+func (r *RendererV2) waitForResponseBodies(opts RenderOptions, state *renderState) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		c := state.bodies
+		if c == nil {
+			return nil
+		}
+		c.finish()
+
+		done := make(chan struct{})
+		go func() {
+			c.wg.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(responseBodyWaitTimeout):
+			r.logger.Warn("Timeout waiting for response bodies", zap.String("url", opts.URL))
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return nil
+	}
+}
+
+// responseBodyCandidate returns the URL and MIME type of a finished XHR or
+// fetch request that may have a body
+func (ec *EventCollector) responseBodyCandidate(requestID string) (url, mimeType string, ok bool) {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+
+	req, found := ec.networkRequests[requestID]
+	if !found || req.Blocked || req.Failed || req.Status == 204 || (req.Status >= 300 && req.Status < 400) {
+		return "", "", false
+	}
+	if req.ResourceType != string(network.ResourceTypeXHR) && req.ResourceType != string(network.ResourceTypeFetch) {
+		return "", "", false
+	}
+	return req.URL, req.MimeType, true
+}
+
+func (ec *EventCollector) setCapturedBody(requestID string, body *types.ResponseBody) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if req, ok := ec.networkRequests[requestID]; ok {
+		req.ResponseBody = body
+	}
+}
@@ -0,0 +1,89 @@
+package chrome
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/network"
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+func TestResponseBodyCapture_Take(t *testing.T) {
+	opts := &types.ResponseBodyOptions{MaxBodyBytes: 9, MaxTotalBytes: 20}
+	c := newResponseBodyCapture(opts, NewEventCollector(zap.NewNop()), zap.NewNop())
+
+	body := c.take([]byte(`{"a":1}`), "application/json")
+	if body.Body != `{"a":1}` || body.Truncated || body.Base64 || body.Size != 7 {
+		t.Errorf("take() = %+v, want the whole JSON body", body)
+	}
+
+	// Per-body limit, without splitting the multi-byte character at the cut
+	body = c.take([]byte("héllo wörld"), "text/plain")
+	if body.Body != "héllo w" || !body.Truncated || body.Size != 13 {
+		t.Errorf("take() = %+v, want %q truncated", body, "héllo w")
+	}
+
+	body = c.take([]byte{0xff, 0x00, 0x01}, "application/octet-stream")
+	if !body.Base64 || body.Body != "/wAB" {
+		t.Errorf("take() = %+v, want base64 binary body", body)
+	}
+
+	// 18 of 20 bytes used: cut to the remaining budget, then nothing is left
+	body = c.take([]byte("more"), "text/plain")
+	if body.Body != "mo" || !body.Truncated {
+		t.Errorf("take() = %+v, want %q truncated to the total budget", body, "mo")
+	}
+	body = c.take([]byte("x"), "text/plain")
+	if body.Skipped != types.BodySkippedTotalBudget || body.Body != "" {
+		t.Errorf("take() = %+v, want skipped for the total budget", body)
+	}
+}
+
+func TestResponseBodyCapture_StartAfterFinish(t *testing.T) {
+	opts := &types.ResponseBodyOptions{MaxBodyBytes: 10, MaxTotalBytes: 10}
+	c := newResponseBodyCapture(opts, NewEventCollector(zap.NewNop()), zap.NewNop())
+
+	if started, budgetLeft := c.start(); !started || !budgetLeft {
+		t.Fatalf("start() = %v, %v, want true, true", started, budgetLeft)
+	}
+	c.wg.Done()
+
+	// Late LoadingFinished events after the wait must not start reads
+	c.finish()
+	if started, _ := c.start(); started {
+		t.Error("start() after finish = true, want false")
+	}
+	c.wg.Wait()
+}
+
+func TestEventCollector_ResponseBodyCandidate(t *testing.T) {
+	ec := NewEventCollector(zap.NewNop())
+	ec.networkRequests["xhr"] = &NetworkRequestData{URL: "https://a.test/api", ResourceType: string(network.ResourceTypeXHR), Status: 200, MimeType: "application/json"}
+	ec.networkRequests["fetch"] = &NetworkRequestData{URL: "https://a.test/f", ResourceType: string(network.ResourceTypeFetch), Status: 403}
+	ec.networkRequests["script"] = &NetworkRequestData{URL: "https://a.test/app.js", ResourceType: string(network.ResourceTypeScript), Status: 200}
+	ec.networkRequests["redirect"] = &NetworkRequestData{URL: "https://a.test/r", ResourceType: string(network.ResourceTypeXHR), Status: 302}
+	ec.networkRequests["failed"] = &NetworkRequestData{URL: "https://a.test/x", ResourceType: string(network.ResourceTypeXHR), Failed: true}
+
+	if url, mimeType, ok := ec.responseBodyCandidate("xhr"); !ok || url != "https://a.test/api" || mimeType != "application/json" {
+		t.Errorf("responseBodyCandidate(xhr) = %q, %q, %v", url, mimeType, ok)
+	}
+	if _, _, ok := ec.responseBodyCandidate("fetch"); !ok {
+		t.Error("responseBodyCandidate(fetch) = false, want error responses captured too")
+	}
+	for _, id := range []string{"script", "redirect", "failed", "missing"} {
+		if _, _, ok := ec.responseBodyCandidate(id); ok {
+			t.Errorf("responseBodyCandidate(%s) = true, want false", id)
+		}
+	}
+
+	ec.setCapturedBody("xhr", &types.ResponseBody{Body: "{}", Size: 2})
+	if got := ec.GetNetworkResults(); len(got) == 0 {
+		t.Fatal("GetNetworkResults() returned no requests")
+	}
+	for _, req := range ec.GetNetworkResults() {
+		if req.URL == "https://a.test/api" && (req.ResponseBody == nil || req.ResponseBody.Body != "{}") {
+			t.Errorf("ResponseBody = %+v, want the captured body", req.ResponseBody)
+		}
+	}
+}
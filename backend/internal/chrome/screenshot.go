@@ -0,0 +1,97 @@
+package chrome
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/chromedp/cdproto/page"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+// screenshotFormats maps request formats to CDP formats
+var screenshotFormats = map[string]page.CaptureScreenshotFormat{
+	types.ScreenshotFormatPNG:  page.CaptureScreenshotFormatPng,
+	types.ScreenshotFormatJPEG: page.CaptureScreenshotFormatJpeg,
+	types.ScreenshotFormatWebP: page.CaptureScreenshotFormatWebp,
+}
+
+// elementRectScript returns the document-relative box of the first match, or null
+const elementRectScript = `(() => {
+	const el = document.querySelector(%s);
+	if (!el) return null;
+	const r = el.getBoundingClientRect();
+	return { x: r.left + window.scrollX, y: r.top + window.scrollY, width: r.width, height: r.height };
+})()`
+
+type elementRect struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// captureScreenshot captures the viewport, the full page or a single element.
+// nil options capture the viewport as PNG.
+func captureScreenshot(ctx context.Context, opts *types.ScreenshotOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &types.ScreenshotOptions{}
+	}
+	o := *opts
+	o.ApplyDefaults()
+
+	params := page.CaptureScreenshot().WithFromSurface(true).WithFormat(screenshotFormats[o.Format])
+	if o.Format != types.ScreenshotFormatPNG {
+		params = params.WithQuality(int64(o.Quality))
+	}
+
+	switch {
+	case o.FullPage:
+		_, _, _, _, _, contentSize, err := page.GetLayoutMetrics().Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		params = params.WithCaptureBeyondViewport(true).WithClip(&page.Viewport{
+			Width:  contentSize.Width,
+			Height: math.Min(contentSize.Height, float64(o.MaxHeight)),
+			Scale:  1,
+		})
+	case o.Selector != "":
+		rect, err := findElementRect(ctx, o.Selector)
+		if err != nil {
+			return nil, err
+		}
+		params = params.WithCaptureBeyondViewport(true).WithClip(&page.Viewport{
+			X:      rect.X,
+			Y:      rect.Y,
+			Width:  rect.Width,
+			Height: math.Min(rect.Height, float64(o.MaxHeight)),
+			Scale:  1,
+		})
+	}
+
+	return params.Do(ctx)
+}
+
+// findElementRect returns the document-relative box of the first element
+// matching selector
+func findElementRect(ctx context.Context, selector string) (*elementRect, error) {
+	encoded, err := json.Marshal(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var rect *elementRect
+	if err := evaluateJSON(ctx, fmt.Sprintf(elementRectScript, encoded), &rect); err != nil {
+		return nil, err
+	}
+	if rect == nil {
+		return nil, fmt.Errorf("no element matches selector %q", selector)
+	}
+	if rect.Width < 1 || rect.Height < 1 {
+		return nil, fmt.Errorf("element matching %q has no size", selector)
+	}
+	return rect, nil
+}
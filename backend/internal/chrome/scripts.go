@@ -0,0 +1,24 @@
+package chrome
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+)
+
+// disableScripts turns off page JavaScript for the tab (chrome_nojs mode), so
+// the page lays out as in a browser without JavaScript and <noscript> content
+// is shown. DevTools evaluation used by actions and wait conditions still runs.
+func (r *RendererV2) disableScripts(opts RenderOptions) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if !opts.ScriptsDisabled {
+			return nil
+		}
+		if err := emulation.SetScriptExecutionDisabled(true).Do(ctx); err != nil {
+			return fmt.Errorf("disable script execution failed: %w", err)
+		}
+		return nil
+	}
+}
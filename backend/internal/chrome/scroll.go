@@ -0,0 +1,191 @@
+package chrome
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	cdpruntime "github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+// Network settle settings between scroll steps
+const (
+	scrollSettleQuiet   = 300 * time.Millisecond // No in-flight requests for this long
+	scrollSettleTimeout = 3 * time.Second        // Long-polling pages never settle
+)
+
+// scrollBaselineScript lists loaded images and links before scrolling.
+// Native lazy images count only once loaded. Attributes are returned as
+// written, since the parser resolves them against the page URL, not <base>.
+const scrollBaselineScript = `(() => ({
+	images: Array.from(document.images)
+		.filter(img => img.src && (img.loading !== 'lazy' || (img.complete && img.naturalWidth > 0)))
+		.map(img => img.getAttribute('src')),
+	links: Array.from(document.querySelectorAll('a[href]'), a => a.getAttribute('href')),
+	height: document.documentElement.scrollHeight,
+}))()`
+
+// scrollStepScript scrolls one viewport down and reports the new position
+const scrollStepScript = `(() => {
+	window.scrollBy(0, window.innerHeight);
+	return { bottom: window.scrollY + window.innerHeight, height: document.documentElement.scrollHeight };
+})()`
+
+// scrollPositionScript reports the position after content settled
+const scrollPositionScript = `(() => ({
+	bottom: window.scrollY + window.innerHeight,
+	height: document.documentElement.scrollHeight,
+}))()`
+
+type scrollBaseline struct {
+	Images []string `json:"images"`
+	Links  []string `json:"links"`
+	Height int      `json:"height"`
+}
+
+type scrollPosition struct {
+	Bottom float64 `json:"bottom"`
+	Height int     `json:"height"`
+}
+
+// scrollToBottom scrolls the page one viewport at a time so lazy-loaded
+// content is requested, waiting for the network to settle after each step.
+// Scrolling gets whatever is left of the render timeout and returns to the
+// top before capture. Failures are logged and never fail the render.
+func (r *RendererV2) scrollToBottom(opts RenderOptions, state *renderState, collector *EventCollector) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if opts.ScrollToBottom == nil {
+			return nil
+		}
+		scrollOpts := *opts.ScrollToBottom
+		scrollOpts.ApplyDefaults()
+
+		start := time.Now()
+		budgetCtx, cancel := state.budget(ctx)
+		defer cancel()
+
+		var baseline scrollBaseline
+		if err := evaluateJSON(budgetCtx, scrollBaselineScript, &baseline); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			r.logger.Warn("Scroll baseline failed", zap.String("url", opts.URL), zap.Error(err))
+			return nil
+		}
+
+		result := &types.ScrollResult{
+			InitialHeight: baseline.Height,
+			FinalHeight:   baseline.Height,
+			InitialImages: baseline.Images,
+			InitialLinks:  baseline.Links,
+		}
+
+		stepDelay := time.Duration(scrollOpts.StepDelayMs) * time.Millisecond
+		for result.StopReason == "" {
+			if result.Steps >= scrollOpts.MaxSteps {
+				result.StopReason = types.ScrollStopMaxSteps
+				break
+			}
+			if result.FinalHeight >= scrollOpts.MaxHeight {
+				result.StopReason = types.ScrollStopMaxHeight
+				break
+			}
+
+			var pos scrollPosition
+			if err := evaluateJSON(budgetCtx, scrollStepScript, &pos); err != nil {
+				result.StopReason = r.scrollErrorReason(budgetCtx, opts.URL, err)
+				break
+			}
+			result.Steps++
+
+			select {
+			case <-time.After(stepDelay):
+			case <-budgetCtx.Done():
+			}
+			r.waitForNetworkSettle(budgetCtx, collector)
+
+			if err := evaluateJSON(budgetCtx, scrollPositionScript, &pos); err != nil {
+				result.StopReason = r.scrollErrorReason(budgetCtx, opts.URL, err)
+				break
+			}
+			result.FinalHeight = pos.Height
+
+			// At the end of the page and nothing more was loaded
+			if pos.Bottom >= float64(pos.Height)-1 {
+				result.StopReason = types.ScrollStopBottom
+			}
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// Back to the top so the screenshot shows the first viewport
+		if _, _, err := cdpruntime.Evaluate("window.scrollTo(0, 0)").Do(ctx); err != nil {
+			r.logger.Debug("Failed to scroll back to top", zap.String("url", opts.URL), zap.Error(err))
+		}
+
+		result.Duration = time.Since(start).Seconds()
+
+		state.mu.Lock()
+		state.scroll = result
+		state.mu.Unlock()
+
+		return nil
+	}
+}
+
+// scrollErrorReason returns the stop reason for a failed scroll step
+func (r *RendererV2) scrollErrorReason(budgetCtx context.Context, pageURL string, err error) string {
+	if budgetCtx.Err() != nil {
+		return types.ScrollStopTimeout
+	}
+	r.logger.Warn("Scroll step failed", zap.String("url", pageURL), zap.Error(err))
+	return types.ScrollStopError
+}
+
+// waitForNetworkSettle waits until no requests are in flight for
+// scrollSettleQuiet, or scrollSettleTimeout passes
+func (r *RendererV2) waitForNetworkSettle(ctx context.Context, collector *EventCollector) {
+	deadline := time.After(scrollSettleTimeout)
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	var quietSince time.Time
+	for {
+		if collector.ActiveRequestCount() == 0 {
+			if quietSince.IsZero() {
+				quietSince = time.Now()
+			} else if time.Since(quietSince) >= scrollSettleQuiet {
+				return
+			}
+		} else {
+			quietSince = time.Time{}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// evaluateJSON evaluates an expression and decodes its JSON value into out
+func evaluateJSON(ctx context.Context, expression string, out interface{}) error {
+	result, exception, err := cdpruntime.Evaluate(expression).WithReturnByValue(true).Do(ctx)
+	if err != nil {
+		return err
+	}
+	if exception != nil {
+		return fmt.Errorf("script error: %s", exceptionMessage(exception))
+	}
+	return json.Unmarshal(result.Value, out)
+}
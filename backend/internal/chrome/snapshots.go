@@ -0,0 +1,155 @@
+package chrome
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+const (
+	snapshotTimeout     = 5 * time.Second // Per HTML extraction
+	snapshotWaitTimeout = 5 * time.Second // For extractions still running at the end of the render
+)
+
+// DOMSnapshot is the page HTML at a lifecycle milestone
+type DOMSnapshot struct {
+	Event string
+	Time  float64 // Seconds since render start, when the lifecycle event fired
+	HTML  string
+}
+
+// domSnapshots extracts the page HTML when a requested lifecycle event of the
+// main frame fires. All methods are no-ops on nil snapshots.
+type domSnapshots struct {
+	events map[string]bool
+	wg     sync.WaitGroup
+
+	mu        sync.Mutex
+	taken     map[string]bool
+	finished  bool // Set once the final HTML is extracted
+	snapshots []DOMSnapshot
+}
+
+// newDOMSnapshots returns nil when no snapshots were requested
+func newDOMSnapshots(opts *types.SnapshotOptions) *domSnapshots {
+	if opts == nil {
+		return nil
+	}
+	events := opts.Events
+	if len(events) == 0 {
+		events = types.DefaultSnapshotEvents
+	}
+	s := &domSnapshots{
+		events: make(map[string]bool, len(events)),
+		taken:  make(map[string]bool, len(events)),
+	}
+	for _, event := range events {
+		s.events[event] = true
+	}
+	return s
+}
+
+// claim reports whether event should be snapshotted, at most once per event.
+// A claimed snapshot is counted in wg until it is added.
+func (s *domSnapshots) claim(event string) bool {
+	if s == nil || !s.events[event] {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.finished || s.taken[event] {
+		return false
+	}
+	s.taken[event] = true
+	s.wg.Add(1)
+	return true
+}
+
+func (s *domSnapshots) add(snapshot DOMSnapshot) {
+	s.mu.Lock()
+	s.snapshots = append(s.snapshots, snapshot)
+	s.mu.Unlock()
+}
+
+// results returns the snapshots in time order
+func (s *domSnapshots) results() []DOMSnapshot {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := append([]DOMSnapshot(nil), s.snapshots...)
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Time < result[j].Time })
+	return result
+}
+
+// captureSnapshot extracts the HTML in the background when event is one of
+// the requested milestones. Called from the event listener, which must not
+// block on CDP commands.
+func (r *RendererV2) captureSnapshot(ctx context.Context, opts RenderOptions, state *renderState, event string, eventTime float64) {
+	s := state.snapshots
+	if !s.claim(event) {
+		return
+	}
+
+	go func() {
+		defer s.wg.Done()
+
+		cmdCtx, cancel := context.WithTimeout(ctx, snapshotTimeout)
+		defer cancel()
+
+		var html string
+		executor := cdp.WithExecutor(cmdCtx, chromedp.FromContext(cmdCtx).Target)
+		if err := r.extractHTML(&html).Do(executor); err != nil {
+			r.logger.Debug("Failed to take DOM snapshot",
+				zap.String("url", opts.URL),
+				zap.String("event", event),
+				zap.Error(err))
+			return
+		}
+		s.add(DOMSnapshot{Event: event, Time: eventTime, HTML: html})
+	}()
+}
+
+// finishSnapshots adds the final HTML as the "final" milestone, stops taking
+// snapshots and waits for extractions still in flight. Runs right after the
+// final HTML extraction.
+func (r *RendererV2) finishSnapshots(opts RenderOptions, state *renderState, timeOrigin int64) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		s := state.snapshots
+		if s == nil {
+			return nil
+		}
+
+		s.mu.Lock()
+		s.finished = true
+		s.snapshots = append(s.snapshots, DOMSnapshot{
+			Event: types.SnapshotFinal,
+			Time:  float64(time.Now().UnixMilli()-timeOrigin) / 1000.0,
+			HTML:  state.html,
+		})
+		s.mu.Unlock()
+
+		done := make(chan struct{})
+		go func() {
+			s.wg.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(snapshotWaitTimeout):
+			r.logger.Warn("Timeout waiting for DOM snapshots", zap.String("url", opts.URL))
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return nil
+	}
+}
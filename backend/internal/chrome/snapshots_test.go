@@ -0,0 +1,55 @@
+package chrome
+
+import (
+	"testing"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+func TestDOMSnapshots_Claim(t *testing.T) {
+	if newDOMSnapshots(nil) != nil {
+		t.Fatal("newDOMSnapshots(nil) should be nil")
+	}
+	var none *domSnapshots
+	if none.claim("load") || none.results() != nil {
+		t.Error("nil snapshots should claim nothing")
+	}
+
+	s := newDOMSnapshots(&types.SnapshotOptions{Events: []string{"load"}})
+	if s.claim("DOMContentLoaded") {
+		t.Error("claimed an event that was not requested")
+	}
+	if !s.claim("load") {
+		t.Fatal("did not claim a requested event")
+	}
+	if s.claim("load") {
+		t.Error("claimed the same event twice")
+	}
+	s.add(DOMSnapshot{Event: "load", Time: 0.5})
+	s.wg.Done()
+
+	// Events after the final HTML are not snapshotted
+	s = newDOMSnapshots(&types.SnapshotOptions{})
+	s.finished = true
+	if s.claim("networkIdle") {
+		t.Error("claimed an event after the snapshots finished")
+	}
+}
+
+func TestDOMSnapshots_Results(t *testing.T) {
+	s := newDOMSnapshots(&types.SnapshotOptions{})
+	s.add(DOMSnapshot{Event: types.SnapshotFinal, Time: 1.2})
+	s.add(DOMSnapshot{Event: "load", Time: 0.7})
+	s.add(DOMSnapshot{Event: "DOMContentLoaded", Time: 0.3})
+
+	results := s.results()
+	want := []string{"DOMContentLoaded", "load", types.SnapshotFinal}
+	if len(results) != len(want) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(want))
+	}
+	for i, event := range want {
+		if results[i].Event != event {
+			t.Errorf("results[%d].Event = %q, want %q", i, results[i].Event, event)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package chrome
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// applyThrottling emulates the network conditions and CPU slowdown in
+// opts.Throttling for the tab. Applied before navigation so the main
+// document is throttled too.
+func (r *RendererV2) applyThrottling(opts RenderOptions) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		t := opts.Throttling
+		if t == nil {
+			return nil
+		}
+
+		if n := t.Network; n != nil {
+			err := network.EmulateNetworkConditions(
+				n.Offline,
+				n.LatencyMs,
+				n.DownloadBytesPerSecond(),
+				n.UploadBytesPerSecond(),
+			).Do(ctx)
+			if err != nil {
+				return fmt.Errorf("emulate network conditions failed: %w", err)
+			}
+		}
+
+		if t.CPUThrottlingRate > 1 {
+			if err := emulation.SetCPUThrottlingRate(t.CPUThrottlingRate).Do(ctx); err != nil {
+				return fmt.Errorf("set CPU throttling rate failed: %w", err)
+			}
+		}
+
+		return nil
+	}
+}
@@ -53,4 +53,14 @@ type InstanceConfig struct {
 	Timeout           time.Duration // General timeout for operations (warmup, render)
 	RestartAfterCount int
 	RestartAfterTime  time.Duration
+
+	// Autoscaling: MinInstances start up front, up to MaxInstances are spawned
+	// while requests are queued, idle instances above the minimum are reaped
+	MinInstances int           // 0 = PoolSize
+	MaxInstances int           // 0 = MinInstances (fixed-size pool)
+	IdleTimeout  time.Duration // Idle time before an instance above MinInstances is reaped
+
+	// Queue settings: requests wait for a free instance in FIFO order
+	QueueSize    int           // Max queued requests, 0 = fail immediately when all instances are busy
+	QueueTimeout time.Duration // Max time a request waits in the queue, 0 = until its context ends
 }
@@ -0,0 +1,234 @@
+package chrome
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/performance"
+	"github.com/chromedp/chromedp"
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+// webVitalsObserverScript is evaluated in every new document before any page
+// script runs. Buffered observers also pick up entries recorded before they
+// were registered. Elements are stored as CSS selectors since nodes may be
+// removed by the time the results are read. CLS and TBT are accumulated over
+// every entry; only the reported shift and long task lists are capped.
+var webVitalsObserverScript = fmt.Sprintf(`(() => {
+	if (window.__jsbugVitals) return;
+	const vitals = window.__jsbugVitals = { fp: 0, fcp: 0, lcp: null, cls: 0, tbt: 0, tbtBeforeFcp: 0, shifts: [], longTasks: [] };
+	const selector = (el) => {
+		if (el && el.nodeType !== 1) el = el.parentElement;
+		const parts = [];
+		while (el && el.nodeType === 1 && parts.length < 6) {
+			if (el.id) { parts.unshift('#' + CSS.escape(el.id)); break; }
+			let part = el.localName;
+			const parent = el.parentElement;
+			if (parent) {
+				const same = Array.from(parent.children).filter(c => c.localName === el.localName);
+				if (same.length > 1) part += ':nth-of-type(' + (same.indexOf(el) + 1) + ')';
+			}
+			parts.unshift(part);
+			if (el.localName === 'body') break;
+			el = parent;
+		}
+		return parts.join(' > ');
+	};
+	const observe = (type, cb) => {
+		try {
+			new PerformanceObserver(list => list.getEntries().forEach(cb)).observe({ type, buffered: true });
+		} catch (e) {}
+	};
+	// Blocking part of a long task after FCP; tasks straddling FCP count from FCP on
+	const blocking = (start, duration, fcp) => {
+		const end = start + duration;
+		return end <= fcp ? 0 : Math.max(0, end - Math.max(start, fcp) - %[4]v);
+	};
+	// Long tasks seen before FCP, counted from 0 in tbtBeforeFcp until FCP is known
+	let pendingTasks = [];
+	observe('paint', e => {
+		if (e.name === 'first-paint') vitals.fp = e.startTime;
+		if (e.name === 'first-contentful-paint' && !vitals.fcp) {
+			vitals.fcp = e.startTime;
+			pendingTasks.forEach(t => { vitals.tbt += blocking(t.start, t.duration, vitals.fcp); });
+			pendingTasks = [];
+			vitals.tbtBeforeFcp = 0;
+		}
+	});
+	observe('largest-contentful-paint', e => {
+		vitals.lcp = { time: e.startTime, size: e.size, url: e.url || '', element: e.element ? selector(e.element) : '' };
+	});
+	// CLS is the largest session window: shifts less than %[5]vms apart, at most %[6]vms long
+	const session = { score: 0, start: 0, last: 0, open: false };
+	observe('layout-shift', e => {
+		if (!e.hadRecentInput) {
+			if (session.open && e.startTime - session.last < %[5]v && e.startTime - session.start < %[6]v) {
+				session.score += e.value;
+			} else {
+				session.score = e.value;
+				session.start = e.startTime;
+				session.open = true;
+			}
+			session.last = e.startTime;
+			vitals.cls = Math.max(vitals.cls, session.score);
+		}
+		if (vitals.shifts.length >= %[1]d) return;
+		const elements = (e.sources || []).map(s => s.node ? selector(s.node) : '').filter(Boolean).slice(0, %[2]d);
+		vitals.shifts.push({ time: e.startTime, value: e.value, input: e.hadRecentInput, elements });
+	});
+	observe('longtask', e => {
+		if (vitals.fcp) {
+			vitals.tbt += blocking(e.startTime, e.duration, vitals.fcp);
+		} else {
+			pendingTasks.push({ start: e.startTime, duration: e.duration });
+			vitals.tbtBeforeFcp += blocking(e.startTime, e.duration, 0);
+		}
+		if (vitals.longTasks.length < %[3]d) vitals.longTasks.push({ start: e.startTime, duration: e.duration });
+	});
+})()`, types.MaxLayoutShifts, types.MaxShiftElements, types.MaxLongTasks,
+	types.LongTaskBlockingMs, types.CLSSessionGapMs, types.CLSSessionMaxDurationMs)
+
+// webVitalsCollectScript reads what the observers recorded so far
+const webVitalsCollectScript = `(() => window.__jsbugVitals
+	? Object.assign({ installed: true }, window.__jsbugVitals)
+	: { installed: false })()`
+
+// webVitalsPerformanceMetrics are the Performance domain metrics reported
+var webVitalsPerformanceMetrics = map[string]bool{
+	"TaskDuration":        true,
+	"ScriptDuration":      true,
+	"LayoutDuration":      true,
+	"RecalcStyleDuration": true,
+	"LayoutCount":         true,
+	"RecalcStyleCount":    true,
+	"JSHeapUsedSize":      true,
+	"JSHeapTotalSize":     true,
+	"Nodes":               true,
+	"JSEventListeners":    true,
+	"Documents":           true,
+	"Frames":              true,
+}
+
+type webVitalsSnapshot struct {
+	Installed bool    `json:"installed"`
+	FP        float64 `json:"fp"`
+	FCP       float64 `json:"fcp"`
+	CLS       float64 `json:"cls"`
+	TBT       float64 `json:"tbt"`
+	// TBT of long tasks seen while there was no FCP yet, counted from 0
+	TBTBeforeFCP float64 `json:"tbtBeforeFcp"`
+	LCP          *struct {
+		Time    float64 `json:"time"`
+		Size    int     `json:"size"`
+		URL     string  `json:"url"`
+		Element string  `json:"element"`
+	} `json:"lcp"`
+	Shifts []struct {
+		Time     float64  `json:"time"`
+		Value    float64  `json:"value"`
+		Input    bool     `json:"input"`
+		Elements []string `json:"elements"`
+	} `json:"shifts"`
+	LongTasks []struct {
+		Start    float64 `json:"start"`
+		Duration float64 `json:"duration"`
+	} `json:"longTasks"`
+}
+
+// installWebVitals enables the Performance domain and registers the
+// observers before navigation when opts.WebVitals is set
+func (r *RendererV2) installWebVitals(opts RenderOptions) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if !opts.WebVitals {
+			return nil
+		}
+		if err := performance.Enable().Do(ctx); err != nil {
+			return fmt.Errorf("enable performance domain failed: %w", err)
+		}
+		if _, err := page.AddScriptToEvaluateOnNewDocument(webVitalsObserverScript).Do(ctx); err != nil {
+			return fmt.Errorf("install web vitals observers failed: %w", err)
+		}
+		return nil
+	}
+}
+
+// collectWebVitals reads the observed entries and Performance domain
+// metrics. Failures are logged and never fail the render.
+func (r *RendererV2) collectWebVitals(opts RenderOptions, state *renderState) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if !opts.WebVitals {
+			return nil
+		}
+
+		var snapshot webVitalsSnapshot
+		if err := evaluateJSON(ctx, webVitalsCollectScript, &snapshot); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			r.logger.Warn("Failed to collect web vitals", zap.String("url", opts.URL), zap.Error(err))
+			return nil
+		}
+		if !snapshot.Installed {
+			r.logger.Warn("Web vitals observers not installed", zap.String("url", opts.URL))
+			return nil
+		}
+
+		vitals := snapshot.toWebVitals()
+
+		metrics, err := performance.GetMetrics().Do(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			r.logger.Debug("Failed to get performance metrics", zap.String("url", opts.URL), zap.Error(err))
+		}
+		for _, m := range metrics {
+			if webVitalsPerformanceMetrics[m.Name] {
+				if vitals.PerformanceMetrics == nil {
+					vitals.PerformanceMetrics = make(map[string]float64)
+				}
+				vitals.PerformanceMetrics[m.Name] = m.Value
+			}
+		}
+
+		state.mu.Lock()
+		state.webVitals = vitals
+		state.mu.Unlock()
+
+		return nil
+	}
+}
+
+// toWebVitals converts the observer entries and totals
+func (s *webVitalsSnapshot) toWebVitals() *types.WebVitals {
+	vitals := &types.WebVitals{
+		FPMs:  s.FP,
+		FCPMs: s.FCP,
+		CLS:   s.CLS,
+		TBTMs: s.TBT + s.TBTBeforeFCP,
+	}
+	if s.LCP != nil {
+		vitals.LCPMs = s.LCP.Time
+		vitals.LCPSize = s.LCP.Size
+		vitals.LCPURL = s.LCP.URL
+		vitals.LCPElement = s.LCP.Element
+	}
+	for _, shift := range s.Shifts {
+		vitals.LayoutShifts = append(vitals.LayoutShifts, types.LayoutShift{
+			TimeMs:         shift.Time,
+			Value:          shift.Value,
+			HadRecentInput: shift.Input,
+			Elements:       shift.Elements,
+		})
+	}
+	for _, task := range s.LongTasks {
+		vitals.LongTasks = append(vitals.LongTasks, types.LongTask{
+			StartMs:    task.Start,
+			DurationMs: task.Duration,
+		})
+	}
+	return vitals
+}
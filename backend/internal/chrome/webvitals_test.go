@@ -0,0 +1,50 @@
+package chrome
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWebVitalsSnapshot_ToWebVitals(t *testing.T) {
+	raw := `{
+		"installed": true,
+		"fp": 110, "fcp": 120, "cls": 0.45, "tbt": 300, "tbtBeforeFcp": 0,
+		"lcp": {"time": 900, "size": 50000, "url": "https://example.com/hero.jpg", "element": "#hero > img"},
+		"shifts": [
+			{"time": 300, "value": 0.1, "input": false, "elements": ["div.banner"]},
+			{"time": 500, "value": 0.3, "input": true, "elements": []}
+		],
+		"longTasks": [{"start": 100, "duration": 80}, {"start": 200, "duration": 120}]
+	}`
+
+	var snapshot webVitalsSnapshot
+	if err := json.Unmarshal([]byte(raw), &snapshot); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	vitals := snapshot.toWebVitals()
+
+	if vitals.LCPMs != 900 || vitals.LCPElement != "#hero > img" || vitals.LCPURL != "https://example.com/hero.jpg" || vitals.LCPSize != 50000 {
+		t.Errorf("LCP = %v %q %q %d", vitals.LCPMs, vitals.LCPElement, vitals.LCPURL, vitals.LCPSize)
+	}
+	if vitals.FPMs != 110 || vitals.FCPMs != 120 {
+		t.Errorf("FP = %v, FCP = %v, want 110 and 120", vitals.FPMs, vitals.FCPMs)
+	}
+	if len(vitals.LayoutShifts) != 2 || !vitals.LayoutShifts[1].HadRecentInput || vitals.LayoutShifts[0].Elements[0] != "div.banner" {
+		t.Errorf("LayoutShifts = %+v", vitals.LayoutShifts)
+	}
+	// CLS and TBT are the observer totals over all entries, not recomputed
+	// from the capped lists
+	if vitals.CLS != 0.45 {
+		t.Errorf("CLS = %v, want 0.45", vitals.CLS)
+	}
+	if vitals.TBTMs != 300 {
+		t.Errorf("TBT = %v, want 300", vitals.TBTMs)
+	}
+}
+
+func TestWebVitalsSnapshot_ToWebVitals_NoFCP(t *testing.T) {
+	snapshot := webVitalsSnapshot{Installed: true, TBTBeforeFCP: 70}
+	if vitals := snapshot.toWebVitals(); vitals.TBTMs != 70 {
+		t.Errorf("TBT = %v, want 70 (long tasks counted from 0 without FCP)", vitals.TBTMs)
+	}
+}
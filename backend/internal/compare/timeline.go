@@ -0,0 +1,60 @@
+package compare
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+// SnapshotTimeline lists when the key SEO elements of a page appeared,
+// changed or disappeared across DOM snapshots in time order. Elements
+// present in the first snapshot are reported as appeared at that milestone.
+func SnapshotTimeline(milestones []types.DOMSnapshot) []types.SnapshotChange {
+	changes := []types.SnapshotChange{}
+	var prev types.DOMSnapshot
+	for _, m := range milestones {
+		add := func(element, from, to string) {
+			if change := snapshotChange(from, to); change != "" {
+				changes = append(changes, types.SnapshotChange{
+					Event:   m.Event,
+					Time:    m.Time,
+					Element: element,
+					Change:  change,
+					From:    from,
+					To:      to,
+				})
+			}
+		}
+		add(types.SnapshotElementTitle, prev.Title, m.Title)
+		add(types.SnapshotElementH1, strings.Join(prev.H1, " | "), strings.Join(m.H1, " | "))
+		add(types.SnapshotElementCanonical, prev.CanonicalURL, m.CanonicalURL)
+		add(types.SnapshotElementWordCount, countString(prev.WordCount), countString(m.WordCount))
+		add(types.SnapshotElementLinkCount, countString(prev.LinkCount), countString(m.LinkCount))
+		prev = m
+	}
+	return changes
+}
+
+// snapshotChange classifies a value change, empty when unchanged
+func snapshotChange(from, to string) string {
+	switch {
+	case from == to:
+		return ""
+	case from == "":
+		return types.SnapshotChangeAppeared
+	case to == "":
+		return types.SnapshotChangeRemoved
+	default:
+		return types.SnapshotChangeChanged
+	}
+}
+
+// countString formats a count, with 0 as empty so a count going from or to 0
+// reads as appeared or removed
+func countString(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,45 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+func TestSnapshotTimeline(t *testing.T) {
+	milestones := []types.DOMSnapshot{
+		{Event: "DOMContentLoaded", Time: 0.2, Title: "Loading...", WordCount: 3},
+		{Event: "load", Time: 0.8, Title: "Product", H1: []string{"Product"}, WordCount: 250, LinkCount: 12},
+		{Event: "networkIdle", Time: 1.4, Title: "Product", H1: []string{"Product"}, WordCount: 250, LinkCount: 12, CanonicalURL: "https://example.com/p"},
+		{Event: types.SnapshotFinal, Time: 1.5, Title: "Product", WordCount: 250, LinkCount: 12, CanonicalURL: "https://example.com/p"},
+	}
+
+	got := SnapshotTimeline(milestones)
+
+	want := []types.SnapshotChange{
+		{Event: "DOMContentLoaded", Time: 0.2, Element: types.SnapshotElementTitle, Change: types.SnapshotChangeAppeared, To: "Loading..."},
+		{Event: "DOMContentLoaded", Time: 0.2, Element: types.SnapshotElementWordCount, Change: types.SnapshotChangeAppeared, To: "3"},
+		{Event: "load", Time: 0.8, Element: types.SnapshotElementTitle, Change: types.SnapshotChangeChanged, From: "Loading...", To: "Product"},
+		{Event: "load", Time: 0.8, Element: types.SnapshotElementH1, Change: types.SnapshotChangeAppeared, To: "Product"},
+		{Event: "load", Time: 0.8, Element: types.SnapshotElementWordCount, Change: types.SnapshotChangeChanged, From: "3", To: "250"},
+		{Event: "load", Time: 0.8, Element: types.SnapshotElementLinkCount, Change: types.SnapshotChangeAppeared, To: "12"},
+		{Event: "networkIdle", Time: 1.4, Element: types.SnapshotElementCanonical, Change: types.SnapshotChangeAppeared, To: "https://example.com/p"},
+		{Event: types.SnapshotFinal, Time: 1.5, Element: types.SnapshotElementH1, Change: types.SnapshotChangeRemoved, From: "Product"},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("len = %d, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("change[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSnapshotTimeline_Empty(t *testing.T) {
+	got := SnapshotTimeline(nil)
+	if got == nil || len(got) != 0 {
+		t.Errorf("SnapshotTimeline(nil) = %v, want empty slice", got)
+	}
+}
@@ -38,6 +38,15 @@ type ChromeConfig struct {
 	WarmupURL         string        `yaml:"warmup_url"`
 	RestartAfterCount int           `yaml:"restart_after_count"`
 	RestartAfterTime  time.Duration `yaml:"restart_after_time"`
+
+	// Autoscaling settings
+	MinInstances int           `yaml:"min_instances"`
+	MaxInstances int           `yaml:"max_instances"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+
+	// Queue settings, nil = default. Pointers so an explicit 0 is kept.
+	QueueSize    *int           `yaml:"queue_size"`
+	QueueTimeout *time.Duration `yaml:"queue_timeout"`
 }
 
 // LoggingConfig contains logging settings
@@ -55,8 +64,17 @@ type CaptchaConfig struct {
 
 // APIConfig contains API key authentication settings
 type APIConfig struct {
-	Enabled bool     `yaml:"enabled"`
-	Keys    []string `yaml:"keys"`
+	Enabled bool           `yaml:"enabled"`
+	Keys    []string       `yaml:"keys"`
+	Batch   JobQueueConfig `yaml:"batch"`
+	Crawl   JobQueueConfig `yaml:"crawl"`
+}
+
+// JobQueueConfig contains settings for asynchronous API jobs (batch renders, crawls)
+type JobQueueConfig struct {
+	MaxQueuedJobs  int           `yaml:"max_queued_jobs"`
+	MaxRunningJobs int           `yaml:"max_running_jobs"`
+	ResultTTL      time.Duration `yaml:"result_ttl"`
 }
 
 // Default values
@@ -72,6 +90,23 @@ const (
 	defaultWarmupURL         = "https://example.com/"
 	defaultRestartAfterCount = 50
 	defaultRestartAfterTime  = 30 * time.Minute
+
+	// Autoscaling defaults (min_instances defaults to pool_size, max_instances to min_instances)
+	defaultIdleTimeout = 5 * time.Minute
+
+	// Batch defaults
+	defaultBatchMaxQueuedJobs  = 16
+	defaultBatchMaxRunningJobs = 2
+	defaultBatchResultTTL      = 1 * time.Hour
+
+	// Crawl defaults
+	defaultCrawlMaxQueuedJobs  = 8
+	defaultCrawlMaxRunningJobs = 1
+	defaultCrawlResultTTL      = 1 * time.Hour
+
+	// Queue defaults
+	defaultQueueSize    = 16
+	defaultQueueTimeout = 10 * time.Second
 )
 
 // Validation constraints
@@ -82,6 +117,10 @@ const (
 	// Pool validation
 	minPoolSize = 1
 	maxPoolSize = 16
+
+	// Queue validation
+	minQueueSize = 0 // 0 = no queueing, fail immediately when all instances are busy
+	maxQueueSize = 256
 )
 
 var validLogLevels = map[string]bool{
@@ -110,6 +149,7 @@ func Load(path string) (*Config, error) {
 
 	cfg.applyDefaults()
 	cfg.applyEnvOverrides()
+	cfg.applyInstanceDefaults()
 
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
@@ -144,6 +184,39 @@ func (c *Config) applyDefaults() {
 	if c.Chrome.RestartAfterTime == 0 {
 		c.Chrome.RestartAfterTime = defaultRestartAfterTime
 	}
+	if c.Chrome.IdleTimeout == 0 {
+		c.Chrome.IdleTimeout = defaultIdleTimeout
+	}
+	if c.Chrome.QueueSize == nil {
+		queueSize := defaultQueueSize
+		c.Chrome.QueueSize = &queueSize
+	}
+	if c.Chrome.QueueTimeout == nil {
+		queueTimeout := defaultQueueTimeout
+		c.Chrome.QueueTimeout = &queueTimeout
+	}
+	// Batch defaults
+	if c.API.Batch.MaxQueuedJobs == 0 {
+		c.API.Batch.MaxQueuedJobs = defaultBatchMaxQueuedJobs
+	}
+	if c.API.Batch.MaxRunningJobs == 0 {
+		c.API.Batch.MaxRunningJobs = defaultBatchMaxRunningJobs
+	}
+	if c.API.Batch.ResultTTL == 0 {
+		c.API.Batch.ResultTTL = defaultBatchResultTTL
+	}
+
+	// Crawl defaults
+	if c.API.Crawl.MaxQueuedJobs == 0 {
+		c.API.Crawl.MaxQueuedJobs = defaultCrawlMaxQueuedJobs
+	}
+	if c.API.Crawl.MaxRunningJobs == 0 {
+		c.API.Crawl.MaxRunningJobs = defaultCrawlMaxRunningJobs
+	}
+	if c.API.Crawl.ResultTTL == 0 {
+		c.API.Crawl.ResultTTL = defaultCrawlResultTTL
+	}
+
 	// Logging defaults
 	if c.Logging.Level == "" {
 		c.Logging.Level = defaultLogLevel
@@ -153,6 +226,17 @@ func (c *Config) applyDefaults() {
 	}
 }
 
+// applyInstanceDefaults derives unset min_instances and max_instances from
+// pool_size. Runs after env overrides so JSBUG_POOL_SIZE still sizes the pool.
+func (c *Config) applyInstanceDefaults() {
+	if c.Chrome.MinInstances == 0 {
+		c.Chrome.MinInstances = c.Chrome.PoolSize
+	}
+	if c.Chrome.MaxInstances == 0 {
+		c.Chrome.MaxInstances = c.Chrome.MinInstances
+	}
+}
+
 // applyEnvOverrides applies environment variable overrides
 func (c *Config) applyEnvOverrides() {
 	if port := os.Getenv("JSBUG_PORT"); port != "" {
@@ -167,6 +251,24 @@ func (c *Config) applyEnvOverrides() {
 		}
 	}
 
+	if minInstances := os.Getenv("JSBUG_MIN_INSTANCES"); minInstances != "" {
+		if m, err := strconv.Atoi(minInstances); err == nil {
+			c.Chrome.MinInstances = m
+		}
+	}
+
+	if maxInstances := os.Getenv("JSBUG_MAX_INSTANCES"); maxInstances != "" {
+		if m, err := strconv.Atoi(maxInstances); err == nil {
+			c.Chrome.MaxInstances = m
+		}
+	}
+
+	if queueSize := os.Getenv("JSBUG_QUEUE_SIZE"); queueSize != "" {
+		if q, err := strconv.Atoi(queueSize); err == nil {
+			c.Chrome.QueueSize = &q
+		}
+	}
+
 	if logLevel := os.Getenv("JSBUG_LOG_LEVEL"); logLevel != "" {
 		c.Logging.Level = logLevel
 	}
@@ -211,6 +313,26 @@ func (c *Config) Validate() error {
 	if c.Chrome.PoolSize < minPoolSize || c.Chrome.PoolSize > maxPoolSize {
 		return fmt.Errorf("invalid pool_size: %d (must be %d-%d)", c.Chrome.PoolSize, minPoolSize, maxPoolSize)
 	}
+	// min_instances and max_instances of 0 fall back to pool_size and min_instances
+	minInstances := c.Chrome.MinInstances
+	if minInstances == 0 {
+		minInstances = c.Chrome.PoolSize
+	}
+	if minInstances < minPoolSize || minInstances > maxPoolSize {
+		return fmt.Errorf("invalid min_instances: %d (must be %d-%d)", c.Chrome.MinInstances, minPoolSize, maxPoolSize)
+	}
+	if c.Chrome.MaxInstances != 0 && (c.Chrome.MaxInstances < minInstances || c.Chrome.MaxInstances > maxPoolSize) {
+		return fmt.Errorf("invalid max_instances: %d (must be %d-%d)", c.Chrome.MaxInstances, minInstances, maxPoolSize)
+	}
+	if c.Chrome.IdleTimeout < 0 {
+		return fmt.Errorf("invalid idle_timeout: %s (must not be negative)", c.Chrome.IdleTimeout)
+	}
+	if q := c.Chrome.QueueSize; q != nil && (*q < minQueueSize || *q > maxQueueSize) {
+		return fmt.Errorf("invalid queue_size: %d (must be %d-%d)", *q, minQueueSize, maxQueueSize)
+	}
+	if q := c.Chrome.QueueTimeout; q != nil && *q < 0 {
+		return fmt.Errorf("invalid queue_timeout: %s (must not be negative)", *q)
+	}
 	// Validate log level
 	if !validLogLevels[c.Logging.Level] {
 		return fmt.Errorf("invalid log level: %s (must be one of: debug, info, warn, error)", c.Logging.Level)
@@ -235,6 +357,12 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("API keys must not be empty")
 		}
 	}
+	if c.API.Batch.MaxQueuedJobs < 0 || c.API.Batch.MaxRunningJobs < 0 || c.API.Batch.ResultTTL < 0 {
+		return fmt.Errorf("invalid api.batch config: values must not be negative")
+	}
+	if c.API.Crawl.MaxQueuedJobs < 0 || c.API.Crawl.MaxRunningJobs < 0 || c.API.Crawl.ResultTTL < 0 {
+		return fmt.Errorf("invalid api.crawl config: values must not be negative")
+	}
 
 	return nil
 }
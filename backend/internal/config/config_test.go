@@ -269,6 +269,67 @@ logging: {}
 	if cfg.Chrome.RestartAfterTime != defaultRestartAfterTime {
 		t.Errorf("Chrome.RestartAfterTime = %v, want default %v", cfg.Chrome.RestartAfterTime, defaultRestartAfterTime)
 	}
+	if cfg.Chrome.MinInstances != defaultPoolSize || cfg.Chrome.MaxInstances != defaultPoolSize {
+		t.Errorf("Chrome.MinInstances/MaxInstances = %d/%d, want pool_size %d", cfg.Chrome.MinInstances, cfg.Chrome.MaxInstances, defaultPoolSize)
+	}
+	if cfg.Chrome.IdleTimeout != defaultIdleTimeout {
+		t.Errorf("Chrome.IdleTimeout = %v, want default %v", cfg.Chrome.IdleTimeout, defaultIdleTimeout)
+	}
+	if *cfg.Chrome.QueueSize != defaultQueueSize {
+		t.Errorf("Chrome.QueueSize = %d, want default %d", *cfg.Chrome.QueueSize, defaultQueueSize)
+	}
+	if *cfg.Chrome.QueueTimeout != defaultQueueTimeout {
+		t.Errorf("Chrome.QueueTimeout = %v, want default %v", *cfg.Chrome.QueueTimeout, defaultQueueTimeout)
+	}
+}
+
+func TestLoad_QueueConfigFromYAML(t *testing.T) {
+	content := `
+server: {}
+chrome:
+  queue_size: 32
+  queue_timeout: 5s
+logging: {}
+`
+	path := createTempConfig(t, content)
+	defer os.Remove(path)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if *cfg.Chrome.QueueSize != 32 {
+		t.Errorf("Chrome.QueueSize = %d, want %d", *cfg.Chrome.QueueSize, 32)
+	}
+	if *cfg.Chrome.QueueTimeout != 5*time.Second {
+		t.Errorf("Chrome.QueueTimeout = %v, want %v", *cfg.Chrome.QueueTimeout, 5*time.Second)
+	}
+}
+
+func TestLoad_QueueConfigZeroFromYAML(t *testing.T) {
+	content := `
+server: {}
+chrome:
+  queue_size: 0
+  queue_timeout: 0s
+logging: {}
+`
+	path := createTempConfig(t, content)
+	defer os.Remove(path)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// 0 means no queue and no queue timeout, not the defaults
+	if *cfg.Chrome.QueueSize != 0 {
+		t.Errorf("Chrome.QueueSize = %d, want 0", *cfg.Chrome.QueueSize)
+	}
+	if *cfg.Chrome.QueueTimeout != 0 {
+		t.Errorf("Chrome.QueueTimeout = %v, want 0", *cfg.Chrome.QueueTimeout)
+	}
 }
 
 func TestLoad_InvalidPoolSize(t *testing.T) {
@@ -354,6 +415,23 @@ logging: {}
 	if cfg.Chrome.PoolSize != 8 {
 		t.Errorf("Chrome.PoolSize = %d, want %d (from env)", cfg.Chrome.PoolSize, 8)
 	}
+	if cfg.Chrome.MinInstances != 8 || cfg.Chrome.MaxInstances != 8 {
+		t.Errorf("Chrome.MinInstances/MaxInstances = %d/%d, want pool_size %d (from env)", cfg.Chrome.MinInstances, cfg.Chrome.MaxInstances, 8)
+	}
+}
+
+func TestLoad_DefaultConfigFile(t *testing.T) {
+	os.Setenv("JSBUG_POOL_SIZE", "2")
+	defer os.Unsetenv("JSBUG_POOL_SIZE")
+
+	cfg, err := Load("../../config.yaml.default")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Chrome.MinInstances != 2 || cfg.Chrome.MaxInstances != 2 {
+		t.Errorf("Chrome.MinInstances/MaxInstances = %d/%d, want pool_size %d", cfg.Chrome.MinInstances, cfg.Chrome.MaxInstances, 2)
+	}
 }
 
 func TestValidate_ValidPoolConfig(t *testing.T) {
@@ -621,3 +699,154 @@ func TestValidate_InvalidPoolSize(t *testing.T) {
 		})
 	}
 }
+
+func TestValidate_InvalidQueueConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		queueSize    int
+		queueTimeout time.Duration
+	}{
+		{"negative_size", -1, time.Second},
+		{"size_too_high", 257, time.Second},
+		{"negative_timeout", 4, -time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Server: ServerConfig{Port: 8080},
+				Chrome: ChromeConfig{
+					PoolSize:     4,
+					QueueSize:    &tt.queueSize,
+					QueueTimeout: &tt.queueTimeout,
+				},
+				Logging: LoggingConfig{Level: "info", Format: "json"},
+			}
+
+			if err := cfg.Validate(); err == nil {
+				t.Errorf("Validate() expected error for queue_size %d, queue_timeout %v", tt.queueSize, tt.queueTimeout)
+			}
+		})
+	}
+}
+
+func TestLoad_AutoscaleConfigFromYAML(t *testing.T) {
+	content := `
+server: {}
+chrome:
+  pool_size: 2
+  max_instances: 6
+  idle_timeout: 1m
+logging: {}
+`
+	path := createTempConfig(t, content)
+	defer os.Remove(path)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Chrome.MinInstances != 2 {
+		t.Errorf("Chrome.MinInstances = %d, want pool_size 2", cfg.Chrome.MinInstances)
+	}
+	if cfg.Chrome.MaxInstances != 6 {
+		t.Errorf("Chrome.MaxInstances = %d, want 6", cfg.Chrome.MaxInstances)
+	}
+	if cfg.Chrome.IdleTimeout != time.Minute {
+		t.Errorf("Chrome.IdleTimeout = %v, want %v", cfg.Chrome.IdleTimeout, time.Minute)
+	}
+}
+
+func TestValidate_InvalidAutoscaleConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		minInstances int
+		maxInstances int
+		idleTimeout  time.Duration
+	}{
+		{"negative_min", -1, 4, time.Minute},
+		{"min_too_high", 17, 17, time.Minute},
+		{"max_below_min", 4, 2, time.Minute},
+		{"max_too_high", 4, 17, time.Minute},
+		{"negative_idle_timeout", 2, 4, -time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Server: ServerConfig{Port: 8080},
+				Chrome: ChromeConfig{
+					PoolSize:     4,
+					MinInstances: tt.minInstances,
+					MaxInstances: tt.maxInstances,
+					IdleTimeout:  tt.idleTimeout,
+				},
+				Logging: LoggingConfig{Level: "info", Format: "json"},
+			}
+
+			if err := cfg.Validate(); err == nil {
+				t.Errorf("Validate() expected error for min %d, max %d, idle_timeout %v", tt.minInstances, tt.maxInstances, tt.idleTimeout)
+			}
+		})
+	}
+}
+
+func TestLoad_BatchDefaults(t *testing.T) {
+	content := `
+server: {}
+chrome: {}
+logging: {}
+api:
+  batch:
+    max_running_jobs: 4
+`
+	path := createTempConfig(t, content)
+	defer os.Remove(path)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.API.Batch.MaxQueuedJobs != defaultBatchMaxQueuedJobs {
+		t.Errorf("API.Batch.MaxQueuedJobs = %d, want default %d", cfg.API.Batch.MaxQueuedJobs, defaultBatchMaxQueuedJobs)
+	}
+	if cfg.API.Batch.MaxRunningJobs != 4 {
+		t.Errorf("API.Batch.MaxRunningJobs = %d, want 4", cfg.API.Batch.MaxRunningJobs)
+	}
+	if cfg.API.Batch.ResultTTL != defaultBatchResultTTL {
+		t.Errorf("API.Batch.ResultTTL = %v, want default %v", cfg.API.Batch.ResultTTL, defaultBatchResultTTL)
+	}
+}
+
+func TestLoad_CrawlDefaults(t *testing.T) {
+	content := `
+server: {}
+chrome: {}
+logging: {}
+api:
+  crawl:
+    max_queued_jobs: 3
+`
+	path := createTempConfig(t, content)
+	defer os.Remove(path)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.API.Crawl.MaxQueuedJobs != 3 {
+		t.Errorf("API.Crawl.MaxQueuedJobs = %d, want 3", cfg.API.Crawl.MaxQueuedJobs)
+	}
+	if cfg.API.Crawl.MaxRunningJobs != defaultCrawlMaxRunningJobs {
+		t.Errorf("API.Crawl.MaxRunningJobs = %d, want default %d", cfg.API.Crawl.MaxRunningJobs, defaultCrawlMaxRunningJobs)
+	}
+	if cfg.API.Crawl.ResultTTL != defaultCrawlResultTTL {
+		t.Errorf("API.Crawl.ResultTTL = %v, want default %v", cfg.API.Crawl.ResultTTL, defaultCrawlResultTTL)
+	}
+	if cfg.API.Batch.MaxQueuedJobs != defaultBatchMaxQueuedJobs {
+		t.Errorf("API.Batch.MaxQueuedJobs = %d, want default %d", cfg.API.Batch.MaxQueuedJobs, defaultBatchMaxQueuedJobs)
+	}
+}
@@ -0,0 +1,371 @@
+// Package crawl implements a breadth-first same-site crawler and the job
+// manager that runs crawls in the background.
+package crawl
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/jsbug/internal/parser"
+	"github.com/user/jsbug/internal/types"
+)
+
+// Retry settings for pages failing with POOL_EXHAUSTED
+const (
+	defaultMaxAttempts = 5
+	defaultRetryDelay  = 2 * time.Second
+)
+
+// skippedExtensions are link targets that are not HTML pages
+var skippedExtensions = map[string]bool{
+	".pdf": true, ".zip": true, ".gz": true, ".jpg": true, ".jpeg": true, ".png": true,
+	".gif": true, ".webp": true, ".svg": true, ".ico": true, ".mp3": true, ".mp4": true,
+	".webm": true, ".css": true, ".js": true, ".json": true, ".xml": true, ".txt": true,
+	".doc": true, ".docx": true, ".xls": true, ".xlsx": true, ".woff": true, ".woff2": true,
+}
+
+// PageFunc renders or fetches a single page
+type PageFunc func(ctx context.Context, url string) (*types.RenderData, *types.RenderError)
+
+// AllowFunc reports whether a URL may be crawled (robots.txt, SSRF checks)
+type AllowFunc func(ctx context.Context, url string) bool
+
+// PerHost returns an AllowFunc that calls check once per URL host and reuses
+// the result for the rest of the crawl. Unparseable URLs are not allowed.
+func PerHost(check func(ctx context.Context, host string) bool) AllowFunc {
+	var mu sync.Mutex
+	allowed := make(map[string]bool)
+
+	return func(ctx context.Context, rawURL string) bool {
+		u, err := url.Parse(rawURL)
+		if err != nil || u.Hostname() == "" {
+			return false
+		}
+		host := strings.ToLower(u.Hostname())
+
+		mu.Lock()
+		result, ok := allowed[host]
+		mu.Unlock()
+		if ok {
+			return result
+		}
+
+		result = check(ctx, host)
+		if ctx.Err() != nil {
+			// A canceled check says nothing about the host
+			return result
+		}
+		mu.Lock()
+		allowed[host] = result
+		mu.Unlock()
+		return result
+	}
+}
+
+// Options configures a crawl
+type Options struct {
+	SeedURL     string
+	MaxDepth    int
+	MaxPages    int
+	Concurrency int
+
+	Page    PageFunc
+	Robots  AllowFunc // nil = robots.txt not checked
+	Allowed AllowFunc // Filters discovered URLs before they are queued, nil = all
+
+	MaxAttempts int           // 0 = defaultMaxAttempts
+	RetryDelay  time.Duration // 0 = defaultRetryDelay
+}
+
+// Crawler crawls internal dofollow links breadth-first from a seed URL.
+// Its state can be read with Snapshot while Run is in progress.
+type Crawler struct {
+	options Options
+
+	mu         sync.Mutex
+	pages      []types.CrawlPage
+	discovered map[string]bool // Normalized URLs queued or crawled
+	links      []types.CrawlLink
+	linkSeen   map[types.CrawlLink]bool
+}
+
+// New creates a Crawler
+func New(options Options) *Crawler {
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaultMaxAttempts
+	}
+	if options.RetryDelay <= 0 {
+		options.RetryDelay = defaultRetryDelay
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = 1
+	}
+
+	return &Crawler{
+		options:    options,
+		discovered: make(map[string]bool),
+		linkSeen:   make(map[types.CrawlLink]bool),
+	}
+}
+
+// Run crawls until MaxDepth or MaxPages is reached or ctx is canceled.
+// Pages of one depth are crawled before the next, so each page's depth
+// is its shortest click depth from the seed.
+func (c *Crawler) Run(ctx context.Context) {
+	seed := normalizeURL(c.options.SeedURL)
+	c.mu.Lock()
+	c.discovered[seed] = true
+	c.mu.Unlock()
+
+	level := []string{seed}
+	for depth := 0; len(level) > 0 && ctx.Err() == nil; depth++ {
+		c.mu.Lock()
+		remaining := c.options.MaxPages - len(c.pages)
+		if len(level) > remaining {
+			level = level[:remaining]
+		}
+		first := len(c.pages)
+		for _, u := range level {
+			c.pages = append(c.pages, types.CrawlPage{URL: u, Depth: depth, Status: types.CrawlPagePending})
+		}
+		c.mu.Unlock()
+
+		outlinks := make([][]string, len(level))
+		slots := make(chan struct{}, c.options.Concurrency)
+		var wg sync.WaitGroup
+		for i := range level {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				break
+			}
+
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				outlinks[i] = c.crawlPage(ctx, first+i)
+			}(i)
+		}
+		wg.Wait()
+
+		// Queue the next depth in link order, recording the link graph
+		var next []string
+		c.mu.Lock()
+		for i, targets := range outlinks {
+			from := level[i]
+			for _, to := range targets {
+				link := types.CrawlLink{From: from, To: to}
+				if !c.linkSeen[link] {
+					c.linkSeen[link] = true
+					c.links = append(c.links, link)
+				}
+				if depth < c.options.MaxDepth && !c.discovered[to] {
+					c.discovered[to] = true
+					next = append(next, to)
+				}
+			}
+		}
+		c.mu.Unlock()
+
+		level = next
+	}
+
+	c.mu.Lock()
+	for i := range c.pages {
+		if c.pages[i].Status == types.CrawlPagePending {
+			c.pages[i].Status = types.CrawlPageCanceled
+		}
+	}
+	c.mu.Unlock()
+}
+
+// crawlPage crawls pages[i] and returns its internal dofollow link targets
+func (c *Crawler) crawlPage(ctx context.Context, i int) []string {
+	c.mu.Lock()
+	pageURL := c.pages[i].URL
+	c.mu.Unlock()
+
+	if c.options.Robots != nil && !c.options.Robots(ctx, pageURL) {
+		c.mu.Lock()
+		c.pages[i].Status = types.CrawlPageRobotsBlocked
+		c.pages[i].NonIndexableReason = types.NonIndexableRobotsBlocked
+		c.mu.Unlock()
+		return nil
+	}
+
+	var data *types.RenderData
+	var renderErr *types.RenderError
+	for attempt := 1; ; attempt++ {
+		data, renderErr = c.options.Page(ctx, pageURL)
+		if renderErr == nil || renderErr.Code != types.ErrPoolExhausted || attempt >= c.options.MaxAttempts {
+			break
+		}
+		select {
+		case <-time.After(c.options.RetryDelay):
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if renderErr != nil {
+		c.mu.Lock()
+		page := &c.pages[i]
+		page.Status = types.CrawlPageError
+		page.NonIndexableReason = types.NonIndexableCrawlFailed
+		page.Error = renderErr
+		if ctx.Err() != nil {
+			page.Status = types.CrawlPageCanceled
+		}
+		c.mu.Unlock()
+		return nil
+	}
+
+	// Links are not followed off-site after a redirect or from nofollow pages
+	followLinks := data.MetaFollow && parser.IsSameDomain(c.options.SeedURL, data.FinalURL)
+
+	var targets []string
+	internalLinks, externalLinks := 0, 0
+	seen := make(map[string]bool)
+	for _, link := range data.Links {
+		if link.IsExternal {
+			externalLinks++
+			continue
+		}
+		if !link.IsDofollow {
+			continue
+		}
+		internalLinks++
+
+		target := normalizeURL(link.Href)
+		if !followLinks || seen[target] || !isCrawlable(target) {
+			continue
+		}
+		// Discovered URLs already passed the filter; only new ones are checked
+		if c.options.Allowed != nil && !c.isDiscovered(target) && !c.options.Allowed(ctx, target) {
+			continue
+		}
+		seen[target] = true
+		targets = append(targets, target)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	page := &c.pages[i]
+	page.Status = types.CrawlPageCrawled
+	page.StatusCode = data.StatusCode
+	page.FinalURL = data.FinalURL
+	page.Title = data.Title
+	page.CanonicalURL = data.CanonicalURL
+	page.WordCount = data.WordCount
+	page.InternalLinks = internalLinks
+	page.ExternalLinks = externalLinks
+	page.Indexable, page.NonIndexableReason = indexability(data)
+
+	// A redirect target counts as crawled so links to it are not crawled again
+	if data.FinalURL != "" {
+		c.discovered[normalizeURL(data.FinalURL)] = true
+	}
+
+	return targets
+}
+
+// isDiscovered reports whether target is already queued or crawled
+func (c *Crawler) isDiscovered(target string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.discovered[target]
+}
+
+// Snapshot returns crawl progress and, when includeDetails is set, copies of
+// the page summaries with inlink counts and the link graph
+func (c *Crawler) Snapshot(includeDetails bool) (types.CrawlProgress, []types.CrawlPage, []types.CrawlLink) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	progress := types.CrawlProgress{Discovered: len(c.discovered)}
+	for _, page := range c.pages {
+		switch page.Status {
+		case types.CrawlPagePending:
+			progress.Pending++
+		case types.CrawlPageCrawled:
+			progress.Crawled++
+		case types.CrawlPageError:
+			progress.Failed++
+		case types.CrawlPageRobotsBlocked:
+			progress.RobotsBlocked++
+		case types.CrawlPageCanceled:
+			progress.Canceled++
+		}
+	}
+
+	if !includeDetails {
+		return progress, nil, nil
+	}
+
+	inlinks := make(map[string]int)
+	for _, link := range c.links {
+		if link.From != link.To {
+			inlinks[link.To]++
+		}
+	}
+
+	pages := make([]types.CrawlPage, len(c.pages))
+	copy(pages, c.pages)
+	for i := range pages {
+		pages[i].Inlinks = inlinks[pages[i].URL]
+	}
+
+	links := make([]types.CrawlLink, len(c.links))
+	copy(links, c.links)
+
+	return progress, pages, links
+}
+
+// indexability reports whether a crawled page is indexable and why not
+func indexability(data *types.RenderData) (bool, string) {
+	if data.StatusCode < 200 || data.StatusCode >= 300 {
+		return false, types.NonIndexableStatusCode
+	}
+	if !data.MetaIndexable {
+		return false, types.NonIndexableNoindex
+	}
+	if data.CanonicalURL != "" && normalizeURL(data.CanonicalURL) != normalizeURL(data.FinalURL) {
+		return false, types.NonIndexableCanonicalized
+	}
+	return true, ""
+}
+
+// normalizeURL strips the fragment and lowercases scheme and host so the
+// same page is crawled once
+func normalizeURL(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return rawURL
+	}
+	u.Fragment = ""
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
+
+// isCrawlable reports whether a URL is an http(s) URL that likely serves HTML
+func isCrawlable(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return false
+	}
+	return !skippedExtensions[strings.ToLower(path.Ext(u.Path))]
+}
@@ -0,0 +1,318 @@
+package crawl
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+// fakeSite serves RenderData for a fixed set of pages
+type fakeSite struct {
+	mu    sync.Mutex
+	pages map[string]*types.RenderData
+	calls map[string]int
+}
+
+func newFakeSite() *fakeSite {
+	return &fakeSite{pages: make(map[string]*types.RenderData), calls: make(map[string]int)}
+}
+
+// add registers an indexable page linking to internal hrefs
+func (s *fakeSite) add(url string, hrefs ...string) *types.RenderData {
+	data := &types.RenderData{
+		StatusCode:    200,
+		FinalURL:      url,
+		Title:         "Page " + url,
+		CanonicalURL:  url,
+		MetaIndexable: true,
+		MetaFollow:    true,
+		WordCount:     100,
+	}
+	for _, href := range hrefs {
+		data.Links = append(data.Links, types.Link{
+			Href:       href,
+			IsExternal: !strings.HasPrefix(href, "https://example.com"),
+			IsDofollow: true,
+		})
+	}
+	s.pages[url] = data
+	return data
+}
+
+func (s *fakeSite) page(ctx context.Context, url string) (*types.RenderData, *types.RenderError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[url]++
+	data, ok := s.pages[url]
+	if !ok {
+		return nil, &types.RenderError{Code: types.ErrRenderFailed, Message: "not found"}
+	}
+	return data, nil
+}
+
+func pageByURL(t *testing.T, pages []types.CrawlPage, url string) types.CrawlPage {
+	t.Helper()
+	for _, p := range pages {
+		if p.URL == url {
+			return p
+		}
+	}
+	t.Fatalf("page %s not crawled", url)
+	return types.CrawlPage{}
+}
+
+func TestCrawler_BreadthFirstDepth(t *testing.T) {
+	site := newFakeSite()
+	site.add("https://example.com/", "https://example.com/a", "https://example.com/b", "https://other.com/")
+	site.add("https://example.com/a", "https://example.com/b", "https://example.com/c#top")
+	site.add("https://example.com/b", "https://example.com/")
+	site.add("https://example.com/c", "https://example.com/d")
+	site.add("https://example.com/d")
+
+	c := New(Options{SeedURL: "https://example.com", MaxDepth: 2, MaxPages: 100, Concurrency: 2, Page: site.page})
+	c.Run(context.Background())
+
+	progress, pages, links := c.Snapshot(true)
+	if progress.Crawled != 4 {
+		t.Errorf("Crawled = %d, want 4 (d is beyond max depth)", progress.Crawled)
+	}
+
+	wantDepths := map[string]int{
+		"https://example.com/":  0,
+		"https://example.com/a": 1,
+		"https://example.com/b": 1,
+		"https://example.com/c": 2,
+	}
+	for url, depth := range wantDepths {
+		if p := pageByURL(t, pages, url); p.Depth != depth {
+			t.Errorf("%s depth = %d, want %d", url, p.Depth, depth)
+		}
+	}
+
+	root := pageByURL(t, pages, "https://example.com/")
+	if root.InternalLinks != 2 || root.ExternalLinks != 1 {
+		t.Errorf("root links = %d internal, %d external, want 2 and 1", root.InternalLinks, root.ExternalLinks)
+	}
+	if root.Inlinks != 1 {
+		t.Errorf("root Inlinks = %d, want 1", root.Inlinks)
+	}
+	if b := pageByURL(t, pages, "https://example.com/b"); b.Inlinks != 2 {
+		t.Errorf("b Inlinks = %d, want 2", b.Inlinks)
+	}
+
+	// Links from depth 2 pages are recorded even though they are not crawled
+	found := false
+	for _, link := range links {
+		if link.From == "https://example.com/c" && link.To == "https://example.com/d" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("link graph should include c -> d")
+	}
+
+	for url, n := range site.calls {
+		if n != 1 {
+			t.Errorf("%s fetched %d times, want 1", url, n)
+		}
+	}
+}
+
+func TestCrawler_MaxPages(t *testing.T) {
+	site := newFakeSite()
+	site.add("https://example.com/", "https://example.com/a", "https://example.com/b", "https://example.com/c")
+	site.add("https://example.com/a")
+	site.add("https://example.com/b")
+	site.add("https://example.com/c")
+
+	c := New(Options{SeedURL: "https://example.com/", MaxDepth: 3, MaxPages: 3, Page: site.page})
+	c.Run(context.Background())
+
+	progress, pages, _ := c.Snapshot(true)
+	if len(pages) != 3 || progress.Crawled != 3 {
+		t.Errorf("crawled %d pages, want 3", len(pages))
+	}
+	if progress.Discovered != 4 {
+		t.Errorf("Discovered = %d, want 4", progress.Discovered)
+	}
+}
+
+func TestCrawler_NofollowAndFilters(t *testing.T) {
+	site := newFakeSite()
+	root := site.add("https://example.com/", "https://example.com/file.pdf", "https://example.com/private", "https://example.com/a")
+	root.Links = append(root.Links, types.Link{Href: "https://example.com/nofollow", IsDofollow: false})
+	nofollowPage := site.add("https://example.com/a", "https://example.com/b")
+	nofollowPage.MetaFollow = false
+
+	c := New(Options{
+		SeedURL:  "https://example.com/",
+		MaxDepth: 3,
+		MaxPages: 100,
+		Page:     site.page,
+		Allowed: func(ctx context.Context, url string) bool {
+			return !strings.HasSuffix(url, "/private")
+		},
+	})
+	c.Run(context.Background())
+
+	_, pages, _ := c.Snapshot(true)
+	if len(pages) != 2 {
+		t.Fatalf("crawled %d pages, want seed and /a only: %+v", len(pages), pages)
+	}
+	pageByURL(t, pages, "https://example.com/a")
+}
+
+func TestCrawler_AllowedSkipsDiscovered(t *testing.T) {
+	site := newFakeSite()
+	site.add("https://example.com/", "https://example.com/a")
+	site.add("https://example.com/a", "https://example.com/", "https://example.com/a")
+
+	var mu sync.Mutex
+	checked := make(map[string]int)
+	c := New(Options{
+		SeedURL:  "https://example.com/",
+		MaxDepth: 3,
+		MaxPages: 100,
+		Page:     site.page,
+		Allowed: func(ctx context.Context, url string) bool {
+			mu.Lock()
+			defer mu.Unlock()
+			checked[url]++
+			return true
+		},
+	})
+	c.Run(context.Background())
+
+	if checked["https://example.com/"] != 0 || checked["https://example.com/a"] != 1 {
+		t.Errorf("Allowed calls = %v, want only the first discovery of /a checked", checked)
+	}
+	if _, _, links := c.Snapshot(true); len(links) != 3 {
+		t.Errorf("links = %+v, want links to discovered pages kept", links)
+	}
+}
+
+func TestPerHost(t *testing.T) {
+	calls := make(map[string]int)
+	allowed := PerHost(func(ctx context.Context, host string) bool {
+		calls[host]++
+		return host != "internal.example"
+	})
+
+	ctx := context.Background()
+	if !allowed(ctx, "https://Example.com/a") || !allowed(ctx, "https://example.com:8443/b") {
+		t.Error("PerHost() = false for an allowed host")
+	}
+	if allowed(ctx, "https://internal.example/") || allowed(ctx, "https://internal.example/x") {
+		t.Error("PerHost() = true for a blocked host")
+	}
+	if allowed(ctx, "not a url") {
+		t.Error("PerHost() = true for a URL without a host")
+	}
+	if calls["example.com"] != 1 || calls["internal.example"] != 1 {
+		t.Errorf("check calls = %v, want one per host", calls)
+	}
+}
+
+func TestCrawler_RobotsAndIndexability(t *testing.T) {
+	site := newFakeSite()
+	site.add("https://example.com/",
+		"https://example.com/blocked", "https://example.com/noindex",
+		"https://example.com/canonical", "https://example.com/missing", "https://example.com/gone")
+	site.add("https://example.com/noindex").MetaIndexable = false
+	site.add("https://example.com/canonical").CanonicalURL = "https://example.com/"
+	site.add("https://example.com/gone").StatusCode = 404
+
+	c := New(Options{
+		SeedURL:  "https://example.com/",
+		MaxDepth: 1,
+		MaxPages: 100,
+		Page:     site.page,
+		Robots: func(ctx context.Context, url string) bool {
+			return !strings.HasSuffix(url, "/blocked")
+		},
+	})
+	c.Run(context.Background())
+
+	progress, pages, _ := c.Snapshot(true)
+	want := types.CrawlProgress{Discovered: 6, Crawled: 4, Failed: 1, RobotsBlocked: 1}
+	if progress != want {
+		t.Errorf("Progress = %+v, want %+v", progress, want)
+	}
+
+	tests := []struct {
+		url       string
+		indexable bool
+		reason    string
+	}{
+		{"https://example.com/", true, ""},
+		{"https://example.com/blocked", false, types.NonIndexableRobotsBlocked},
+		{"https://example.com/noindex", false, types.NonIndexableNoindex},
+		{"https://example.com/canonical", false, types.NonIndexableCanonicalized},
+		{"https://example.com/missing", false, types.NonIndexableCrawlFailed},
+		{"https://example.com/gone", false, types.NonIndexableStatusCode},
+	}
+	for _, tt := range tests {
+		p := pageByURL(t, pages, tt.url)
+		if p.Indexable != tt.indexable || p.NonIndexableReason != tt.reason {
+			t.Errorf("%s indexable = %v (%q), want %v (%q)", tt.url, p.Indexable, p.NonIndexableReason, tt.indexable, tt.reason)
+		}
+	}
+	if _, ok := site.calls["https://example.com/blocked"]; ok {
+		t.Error("robots-blocked page should not be fetched")
+	}
+}
+
+func TestCrawler_RetriesPoolExhausted(t *testing.T) {
+	calls := 0
+	page := func(ctx context.Context, url string) (*types.RenderData, *types.RenderError) {
+		calls++
+		if calls < 3 {
+			return nil, &types.RenderError{Code: types.ErrPoolExhausted, Message: "busy"}
+		}
+		return &types.RenderData{StatusCode: 200, FinalURL: url, MetaIndexable: true, MetaFollow: true}, nil
+	}
+
+	c := New(Options{SeedURL: "https://example.com/", MaxPages: 1, Page: page, RetryDelay: time.Millisecond})
+	c.Run(context.Background())
+
+	progress, _, _ := c.Snapshot(false)
+	if progress.Crawled != 1 || calls != 3 {
+		t.Errorf("Crawled = %d after %d calls, want 1 after 3", progress.Crawled, calls)
+	}
+}
+
+func TestCrawler_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	page := func(ctx context.Context, url string) (*types.RenderData, *types.RenderError) {
+		cancel()
+		return nil, &types.RenderError{Code: types.ErrRenderFailed, Message: "canceled"}
+	}
+
+	c := New(Options{SeedURL: "https://example.com/", MaxDepth: 1, MaxPages: 10, Page: page})
+	c.Run(ctx)
+
+	progress, _, _ := c.Snapshot(false)
+	if progress.Canceled != 1 || progress.Failed != 0 {
+		t.Errorf("Progress = %+v, want the seed canceled", progress)
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"https://Example.COM", "https://example.com/"},
+		{"https://example.com/a#section", "https://example.com/a"},
+		{"HTTPS://example.com/a?q=1", "https://example.com/a?q=1"},
+	}
+	for _, tt := range tests {
+		if got := normalizeURL(tt.in); got != tt.want {
+			t.Errorf("normalizeURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package crawl
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/jobqueue"
+	"github.com/user/jsbug/internal/types"
+)
+
+// Default manager settings
+const (
+	DefaultMaxQueuedJobs  = 8
+	DefaultMaxRunningJobs = 1
+	DefaultResultTTL      = 1 * time.Hour
+)
+
+// ManagerConfig contains crawl manager settings
+type ManagerConfig struct {
+	MaxQueuedJobs  int           // Crawls waiting for a runner
+	MaxRunningJobs int           // Crawls running at the same time
+	ResultTTL      time.Duration // How long finished crawls are kept
+}
+
+// job is a crawl job
+type job struct {
+	logger  *zap.Logger
+	options Options
+	crawler *Crawler
+}
+
+// Manager queues crawl jobs and runs them in the background
+type Manager struct {
+	config ManagerConfig
+	logger *zap.Logger
+	jobs   *jobqueue.Queue[*job]
+}
+
+// NewManager creates a new Manager. Zero config values are replaced by defaults.
+// Call Start to begin processing jobs.
+func NewManager(config ManagerConfig, logger *zap.Logger) *Manager {
+	if config.MaxQueuedJobs <= 0 {
+		config.MaxQueuedJobs = DefaultMaxQueuedJobs
+	}
+	if config.MaxRunningJobs <= 0 {
+		config.MaxRunningJobs = DefaultMaxRunningJobs
+	}
+	if config.ResultTTL <= 0 {
+		config.ResultTTL = DefaultResultTTL
+	}
+
+	return &Manager{
+		config: config,
+		logger: logger,
+		jobs: jobqueue.New[*job](jobqueue.Config{
+			Name:           "Crawl",
+			MaxQueuedJobs:  config.MaxQueuedJobs,
+			MaxRunningJobs: config.MaxRunningJobs,
+			ResultTTL:      config.ResultTTL,
+		}, logger),
+	}
+}
+
+// Start launches the job runners and the cleanup of expired jobs.
+// They stop when Shutdown is called.
+func (m *Manager) Start(cleanupInterval time.Duration) {
+	m.jobs.Start(cleanupInterval)
+
+	m.logger.Info("Crawl manager started",
+		zap.Int("max_queued_jobs", m.config.MaxQueuedJobs),
+		zap.Int("max_running_jobs", m.config.MaxRunningJobs),
+	)
+}
+
+// Shutdown cancels all queued and running crawls
+func (m *Manager) Shutdown() {
+	m.jobs.Shutdown()
+}
+
+// Submit queues a crawl. owner is the API key that may read and cancel it.
+// It returns jobqueue.ErrQueueFull or jobqueue.ErrShuttingDown when the
+// crawl is not queued.
+func (m *Manager) Submit(owner string, options Options) (*types.ExtCrawlJob, error) {
+	queued, err := m.jobs.Submit(owner, &job{
+		logger:  m.logger,
+		options: options,
+		crawler: New(options),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.logger.Info("Crawl job queued",
+		zap.String("job_id", queued.ID),
+		zap.String("url", options.SeedURL),
+		zap.Int("max_depth", options.MaxDepth),
+		zap.Int("max_pages", options.MaxPages),
+	)
+
+	return snapshot(queued, false), nil
+}
+
+// Get returns a snapshot of the crawl, including pages and the link graph.
+// Returns false if the job does not exist or belongs to another owner.
+func (m *Manager) Get(id, owner string) (*types.ExtCrawlJob, bool) {
+	queued, ok := m.jobs.Get(id, owner)
+	if !ok {
+		return nil, false
+	}
+	return snapshot(queued, true), true
+}
+
+// Cancel stops a queued or running crawl. Pages crawled so far are kept.
+// Returns false if the job does not exist or belongs to another owner.
+func (m *Manager) Cancel(id, owner string) (*types.ExtCrawlJob, bool) {
+	queued, ok := m.jobs.Cancel(id, owner)
+	if !ok {
+		return nil, false
+	}
+	return snapshot(queued, false), true
+}
+
+// Run runs the crawl and logs its totals
+func (j *job) Run(ctx context.Context) {
+	j.crawler.Run(ctx)
+
+	progress, _, _ := j.crawler.Snapshot(false)
+	j.logger.Info("Crawl finished",
+		zap.String("url", j.options.SeedURL),
+		zap.Int("crawled", progress.Crawled),
+		zap.Int("failed", progress.Failed),
+		zap.Int("robots_blocked", progress.RobotsBlocked),
+	)
+}
+
+// snapshot converts a queued crawl
+func snapshot(queued *jobqueue.Job[*job], includeDetails bool) *types.ExtCrawlJob {
+	j := queued.Work
+	result := &types.ExtCrawlJob{
+		ID:         queued.ID,
+		Status:     queued.Status,
+		URL:        j.options.SeedURL,
+		MaxDepth:   j.options.MaxDepth,
+		MaxPages:   j.options.MaxPages,
+		CreatedAt:  queued.CreatedAt,
+		StartedAt:  queued.StartedAt,
+		FinishedAt: queued.FinishedAt,
+	}
+	result.Progress, result.Pages, result.Links = j.crawler.Snapshot(includeDetails)
+	return result
+}
@@ -0,0 +1,145 @@
+package crawl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/jobqueue"
+	"github.com/user/jsbug/internal/types"
+)
+
+func newTestManager(config ManagerConfig) *Manager {
+	m := NewManager(config, zap.NewNop())
+	m.Start(time.Hour)
+	return m
+}
+
+// waitForStatus polls until the job reaches status
+func waitForStatus(t *testing.T, m *Manager, id, owner, status string) *types.ExtCrawlJob {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		job, ok := m.Get(id, owner)
+		if !ok {
+			t.Fatalf("Get(%q) not found", id)
+		}
+		if job.Status == status {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job status = %q, want %q", job.Status, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func testOptions(page PageFunc) Options {
+	return Options{SeedURL: "https://example.com/", MaxDepth: 1, MaxPages: 10, Page: page}
+}
+
+func TestManager_CompletesCrawl(t *testing.T) {
+	m := newTestManager(ManagerConfig{})
+	defer m.Shutdown()
+
+	site := newFakeSite()
+	site.add("https://example.com/", "https://example.com/a")
+	site.add("https://example.com/a")
+
+	submitted, err := m.Submit("key", testOptions(site.page))
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if submitted.Pages != nil || submitted.URL != "https://example.com/" {
+		t.Errorf("Submit() snapshot = %+v, want seed URL without pages", submitted)
+	}
+
+	job := waitForStatus(t, m, submitted.ID, "key", types.JobStatusCompleted)
+	if job.Progress.Crawled != 2 || len(job.Pages) != 2 || len(job.Links) != 1 {
+		t.Errorf("job = %+v, want 2 crawled pages and 1 link", job)
+	}
+	if job.StartedAt == nil || job.FinishedAt == nil {
+		t.Error("StartedAt and FinishedAt should be set")
+	}
+
+	if _, ok := m.Get(submitted.ID, "other-key"); ok {
+		t.Error("Get() with another key should not find the job")
+	}
+}
+
+func TestManager_CancelRunning(t *testing.T) {
+	m := newTestManager(ManagerConfig{})
+	defer m.Shutdown()
+
+	started := make(chan struct{})
+	page := func(ctx context.Context, url string) (*types.RenderData, *types.RenderError) {
+		close(started)
+		<-ctx.Done()
+		return nil, &types.RenderError{Code: types.ErrRenderFailed, Message: "canceled"}
+	}
+
+	submitted, err := m.Submit("key", testOptions(page))
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-started
+
+	if _, ok := m.Cancel(submitted.ID, "key"); !ok {
+		t.Fatal("Cancel() returned false")
+	}
+	job := waitForStatus(t, m, submitted.ID, "key", types.JobStatusCanceled)
+	if job.Progress.Canceled != 1 {
+		t.Errorf("Progress = %+v, want the seed canceled", job.Progress)
+	}
+}
+
+func TestManager_QueueFull(t *testing.T) {
+	m := NewManager(ManagerConfig{MaxQueuedJobs: 1}, zap.NewNop())
+	defer m.Shutdown()
+	// Not started: jobs stay queued
+
+	site := newFakeSite()
+	first, err := m.Submit("key", testOptions(site.page))
+	if err != nil {
+		t.Fatalf("first Submit() error = %v", err)
+	}
+	if _, err := m.Submit("key", testOptions(site.page)); err != jobqueue.ErrQueueFull {
+		t.Errorf("second Submit() error = %v, want ErrQueueFull", err)
+	}
+
+	job, ok := m.Cancel(first.ID, "key")
+	if !ok || job.Status != types.JobStatusCanceled {
+		t.Errorf("Cancel() = %+v, %v, want canceled job", job, ok)
+	}
+}
+
+func TestManager_Cleanup(t *testing.T) {
+	m := newTestManager(ManagerConfig{ResultTTL: time.Millisecond})
+	defer m.Shutdown()
+
+	site := newFakeSite()
+	site.add("https://example.com/")
+	job, err := m.Submit("key", testOptions(site.page))
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	waitForStatus(t, m, job.ID, "key", types.JobStatusCompleted)
+
+	time.Sleep(5 * time.Millisecond)
+	m.jobs.Cleanup()
+
+	if _, ok := m.Get(job.ID, "key"); ok {
+		t.Error("expired job should be removed by cleanup")
+	}
+}
+
+func TestManager_SubmitAfterShutdown(t *testing.T) {
+	m := newTestManager(ManagerConfig{})
+	m.Shutdown()
+
+	if _, err := m.Submit("key", testOptions(newFakeSite().page)); err != jobqueue.ErrShuttingDown {
+		t.Errorf("Submit() error = %v, want ErrShuttingDown", err)
+	}
+}
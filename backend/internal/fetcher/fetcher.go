@@ -5,18 +5,22 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptrace"
 	"strings"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/user/jsbug/internal/security"
+	"github.com/user/jsbug/internal/types"
 )
 
 // Default timeouts
 const (
 	defaultDialTimeout    = 10 * time.Second
 	defaultRequestTimeout = 30 * time.Second
+	defaultAcceptLanguage = "en-US,en;q=0.5"
 )
 
 // FetchOptions contains options for fetching a URL
@@ -24,7 +28,10 @@ type FetchOptions struct {
 	URL             string
 	UserAgent       string
 	Timeout         time.Duration
-	FollowRedirects bool // default should be true
+	FollowRedirects bool              // default should be true
+	AcceptLanguage  string            // Empty = en-US
+	Headers         map[string]string // Custom headers, override the defaults below
+	Cookies         []*http.Cookie    // Scoped to their Domain/Path via a per-request cookie jar
 }
 
 // FetchResult contains the results of fetching a URL
@@ -36,6 +43,10 @@ type FetchResult struct {
 	PageSizeBytes int
 	FetchTime     float64 // seconds
 	Headers       http.Header
+	RedirectChain []types.RedirectHop  // One hop per 3xx response, including a stopped redirect
+	Timing        *types.RequestTiming // Phases of the final hop
+	Protocol      string               // h1, h2 or h3
+	RemoteIP      string
 }
 
 // Fetcher performs HTTP requests for non-JS rendering
@@ -98,20 +109,22 @@ var ErrRedirectStopped = fmt.Errorf("redirect stopped")
 func (f *Fetcher) Fetch(ctx context.Context, opts FetchOptions) (*FetchResult, error) {
 	startTime := time.Now()
 
+	// Trace connection phases of each hop; the last hop is reported
+	trace := &requestTrace{}
+	traceCtx := httptrace.WithClientTrace(ctx, trace.clientTrace())
+
 	// Create request with context
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, opts.URL, nil)
+	req, err := http.NewRequestWithContext(traceCtx, http.MethodGet, opts.URL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Set User-Agent
-	if opts.UserAgent != "" {
-		req.Header.Set("User-Agent", opts.UserAgent)
-	}
+	setDefaultHeaders(req.Header, opts)
 
-	// Set Accept header for HTML
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
+	// Apply custom headers (after defaults so callers can override them)
+	for name, value := range opts.Headers {
+		req.Header.Set(name, value)
+	}
 
 	// Create client with appropriate redirect handling
 	var redirectURL string
@@ -120,16 +133,52 @@ func (f *Fetcher) Fetch(ctx context.Context, opts FetchOptions) (*FetchResult, e
 		timeout = defaultRequestTimeout
 	}
 
+	// Record every hop; req.Response is the 3xx response that triggered req
+	var chain []types.RedirectHop
+	hopStart := startTime
+	recordHop := func(req *http.Request) {
+		now := time.Now()
+		chain = append(chain, types.RedirectHop{
+			URL:        req.Response.Request.URL.String(),
+			StatusCode: req.Response.StatusCode,
+			Location:   req.URL.String(),
+			Time:       now.Sub(hopStart).Seconds(),
+		})
+		hopStart = now
+	}
+
+	// Without a cookie jar nothing changes between visits, so the first
+	// revisit is a loop; with one, a hop may set a cookie and come back
+	maxRevisits := 0
+	if len(opts.Cookies) > 0 {
+		maxRevisits = types.MaxRedirectRevisits
+	}
+
 	var checkRedirect func(req *http.Request, via []*http.Request) error
 	if opts.FollowRedirects {
 		checkRedirect = func(req *http.Request, via []*http.Request) error {
-			if len(via) >= 10 {
+			recordHop(req)
+			// Custom headers are only sent to the target origin, as in JS mode;
+			// the client copies them from the first request to every hop
+			if len(opts.Headers) > 0 && !types.CustomHeadersAllowed(opts.URL, req.URL.String()) {
+				for name := range opts.Headers {
+					req.Header.Del(name)
+				}
+				setDefaultHeaders(req.Header, opts)
+			}
+			// Stop at a loop and return the 3xx response instead of failing on too many redirects
+			if types.RedirectVisits(chain, req.URL.String()) > maxRevisits {
+				redirectURL = req.URL.String()
+				return http.ErrUseLastResponse
+			}
+			if len(via) >= types.MaxRedirectHops {
 				return fmt.Errorf("too many redirects")
 			}
 			return nil
 		}
 	} else {
 		checkRedirect = func(req *http.Request, via []*http.Request) error {
+			recordHop(req)
 			redirectURL = req.URL.String()
 			return http.ErrUseLastResponse
 		}
@@ -141,6 +190,16 @@ func (f *Fetcher) Fetch(ctx context.Context, opts FetchOptions) (*FetchResult, e
 		CheckRedirect: checkRedirect,
 	}
 
+	// Cookies go through a jar so they follow redirects only to matching domains/paths
+	if len(opts.Cookies) > 0 {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cookie jar: %w", err)
+		}
+		jar.SetCookies(req.URL, opts.Cookies)
+		client.Jar = jar
+	}
+
 	f.logger.Debug("Fetching URL",
 		zap.String("url", opts.URL),
 		zap.String("user_agent", opts.UserAgent),
@@ -160,7 +219,8 @@ func (f *Fetcher) Fetch(ctx context.Context, opts FetchOptions) (*FetchResult, e
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	fetchTime := time.Since(startTime).Seconds()
+	bodyRead := time.Now()
+	fetchTime := bodyRead.Sub(startTime).Seconds()
 
 	result := &FetchResult{
 		HTML:          string(body),
@@ -170,20 +230,42 @@ func (f *Fetcher) Fetch(ctx context.Context, opts FetchOptions) (*FetchResult, e
 		PageSizeBytes: len(body),
 		FetchTime:     fetchTime,
 		Headers:       resp.Header,
+		RedirectChain: chain,
+		Timing:        trace.timing(bodyRead),
+		Protocol:      types.NormalizeProtocol(resp.Proto),
+		RemoteIP:      trace.remoteIP(),
 	}
 
 	f.logger.Debug("Fetch completed",
 		zap.String("url", opts.URL),
 		zap.String("final_url", result.FinalURL),
 		zap.String("redirect_url", result.RedirectURL),
+		zap.Int("redirect_hops", len(result.RedirectChain)),
 		zap.Int("status_code", result.StatusCode),
 		zap.Int("size_bytes", result.PageSizeBytes),
 		zap.Float64("fetch_time", fetchTime),
+		zap.Float64("ttfb", result.Timing.TTFB),
 	)
 
 	return result, nil
 }
 
+// DocumentRequest describes the final hop of the fetch as a network request
+func (r *FetchResult) DocumentRequest() *types.NetworkRequest {
+	return &types.NetworkRequest{
+		URL:        r.FinalURL,
+		Method:     http.MethodGet,
+		Status:     r.StatusCode,
+		Type:       "Document",
+		Size:       r.PageSizeBytes,
+		Time:       r.FetchTime,
+		IsInternal: true,
+		Timing:     r.Timing,
+		Protocol:   r.Protocol,
+		RemoteIP:   r.RemoteIP,
+	}
+}
+
 // GetContentType extracts Content-Type from headers
 func (r *FetchResult) GetContentType() string {
 	return r.Headers.Get("Content-Type")
@@ -201,7 +283,21 @@ func (r *FetchResult) GetLinkHeader() string {
 
 // GetCanonicalFromHeader extracts canonical URL from Link header
 func (r *FetchResult) GetCanonicalFromHeader() string {
-	link := r.Headers.Get("Link")
+	return CanonicalFromLinkHeader(r.Headers.Get("Link"))
+}
+
+// HeaderMap returns the response headers as a flat map, joining repeated
+// headers with types.JoinHeaderValues
+func (r *FetchResult) HeaderMap() map[string]string {
+	headers := make(map[string]string, len(r.Headers))
+	for name, values := range r.Headers {
+		headers[name] = types.JoinHeaderValues(name, values)
+	}
+	return headers
+}
+
+// CanonicalFromLinkHeader extracts the rel="canonical" URL from a Link header value
+func CanonicalFromLinkHeader(link string) string {
 	if link == "" {
 		return ""
 	}
@@ -276,3 +372,18 @@ func extractURL(part string) string {
 
 	return ""
 }
+
+// setDefaultHeaders sets the User-Agent, Accept and Accept-Language headers
+func setDefaultHeaders(header http.Header, opts FetchOptions) {
+	if opts.UserAgent != "" {
+		header.Set("User-Agent", opts.UserAgent)
+	}
+
+	// Accept HTML
+	header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	acceptLanguage := defaultAcceptLanguage
+	if opts.AcceptLanguage != "" {
+		acceptLanguage = opts.AcceptLanguage
+	}
+	header.Set("Accept-Language", acceptLanguage)
+}
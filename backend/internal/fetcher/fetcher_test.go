@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/types"
 )
 
 func TestNewFetcher(t *testing.T) {
@@ -211,6 +213,39 @@ func TestFetcher_Fetch_UserAgent(t *testing.T) {
 	}
 }
 
+func TestFetcher_Fetch_AcceptLanguage(t *testing.T) {
+	var receivedLang string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedLang = r.Header.Get("Accept-Language")
+		fmt.Fprint(w, "OK")
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	f := NewUnsafeFetcher(logger)
+
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"", "en-US,en;q=0.5"},
+		{"fr-FR,fr;q=0.9", "fr-FR,fr;q=0.9"},
+	}
+	for _, tt := range tests {
+		_, err := f.Fetch(context.Background(), FetchOptions{
+			URL:            server.URL,
+			Timeout:        10 * time.Second,
+			AcceptLanguage: tt.acceptLanguage,
+		})
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if receivedLang != tt.want {
+			t.Errorf("Accept-Language = %q, want %q", receivedLang, tt.want)
+		}
+	}
+}
+
 func TestFetcher_Fetch_Headers(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -245,6 +280,100 @@ func TestFetcher_Fetch_Headers(t *testing.T) {
 	}
 }
 
+func TestFetcher_Fetch_CustomHeadersAndCookies(t *testing.T) {
+	var receivedAuth, receivedLang, receivedCookie string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/start" {
+			http.Redirect(w, r, "/final", http.StatusFound)
+			return
+		}
+		receivedAuth = r.Header.Get("Authorization")
+		receivedLang = r.Header.Get("Accept-Language")
+		if c, err := r.Cookie("session"); err == nil {
+			receivedCookie = c.Value
+		}
+		fmt.Fprint(w, "OK")
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	f := NewUnsafeFetcher(logger)
+
+	_, err := f.Fetch(context.Background(), FetchOptions{
+		URL:             server.URL + "/start",
+		Timeout:         10 * time.Second,
+		FollowRedirects: true,
+		Headers: map[string]string{
+			"Authorization":   "Bearer token123",
+			"Accept-Language": "de-DE",
+		},
+		Cookies: []*http.Cookie{
+			{Name: "session", Value: "abc", Path: "/"},
+			{Name: "other", Value: "x", Domain: "other.example", Path: "/"},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	if receivedAuth != "Bearer token123" {
+		t.Errorf("Authorization = %q, want %q", receivedAuth, "Bearer token123")
+	}
+	if receivedLang != "de-DE" {
+		t.Errorf("Accept-Language = %q, want %q (custom header should override default)", receivedLang, "de-DE")
+	}
+	if receivedCookie != "abc" {
+		t.Errorf("session cookie = %q, want %q (cookie should survive same-host redirect)", receivedCookie, "abc")
+	}
+}
+
+func TestFetcher_Fetch_CustomHeadersTargetOriginOnly(t *testing.T) {
+	var otherToken, otherLang string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherToken = r.Header.Get("X-Staging-Token")
+		otherLang = r.Header.Get("Accept-Language")
+		fmt.Fprint(w, "OK")
+	}))
+	defer other.Close()
+
+	var targetToken string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/start" {
+			http.Redirect(w, r, "/moved", http.StatusFound)
+			return
+		}
+		targetToken = r.Header.Get("X-Staging-Token")
+		http.Redirect(w, r, other.URL+"/final", http.StatusFound)
+	}))
+	defer target.Close()
+
+	f := NewUnsafeFetcher(zap.NewNop())
+
+	_, err := f.Fetch(context.Background(), FetchOptions{
+		URL:             target.URL + "/start",
+		Timeout:         10 * time.Second,
+		FollowRedirects: true,
+		Headers: map[string]string{
+			"X-Staging-Token": "secret",
+			"Accept-Language": "de-DE",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	if targetToken != "secret" {
+		t.Errorf("target X-Staging-Token = %q, want it kept on a same-origin redirect", targetToken)
+	}
+	if otherToken != "" {
+		t.Errorf("cross-origin X-Staging-Token = %q, want it stripped", otherToken)
+	}
+	if otherLang != defaultAcceptLanguage {
+		t.Errorf("cross-origin Accept-Language = %q, want default %q", otherLang, defaultAcceptLanguage)
+	}
+}
+
 func TestFetcher_Fetch_ContextCancellation(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(5 * time.Second)
@@ -361,8 +490,8 @@ func TestFetcher_TooManyRedirects(t *testing.T) {
 	redirectCount := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		redirectCount++
-		// Always redirect to create infinite loop
-		http.Redirect(w, r, "/redirect", http.StatusFound)
+		// Always redirect to a new URL so the chain never loops but never ends
+		http.Redirect(w, r, fmt.Sprintf("/redirect?n=%d", redirectCount), http.StatusFound)
 	}))
 	defer server.Close()
 
@@ -383,3 +512,217 @@ func TestFetcher_TooManyRedirects(t *testing.T) {
 		t.Logf("Redirect count: %d", redirectCount)
 	}
 }
+
+func TestFetcher_Fetch_RedirectChain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/moved", http.StatusMovedPermanently)
+		case "/moved":
+			http.Redirect(w, r, "/final", http.StatusFound)
+		default:
+			w.Write([]byte("<html><body>Final</body></html>"))
+		}
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	f := NewUnsafeFetcher(logger)
+
+	result, err := f.Fetch(context.Background(), FetchOptions{
+		URL:             server.URL + "/old",
+		Timeout:         10 * time.Second,
+		FollowRedirects: true,
+	})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	if len(result.RedirectChain) != 2 {
+		t.Fatalf("len(RedirectChain) = %d, want 2", len(result.RedirectChain))
+	}
+
+	first := result.RedirectChain[0]
+	if first.URL != server.URL+"/old" || first.StatusCode != 301 || first.Location != server.URL+"/moved" {
+		t.Errorf("hop 0 = %+v, want /old -> 301 -> /moved", first)
+	}
+	second := result.RedirectChain[1]
+	if second.URL != server.URL+"/moved" || second.StatusCode != 302 || second.Location != server.URL+"/final" {
+		t.Errorf("hop 1 = %+v, want /moved -> 302 -> /final", second)
+	}
+	if result.StatusCode != 200 {
+		t.Errorf("StatusCode = %d, want 200", result.StatusCode)
+	}
+}
+
+func TestFetcher_Fetch_RedirectLoop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusFound)
+		default:
+			http.Redirect(w, r, "/a", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	f := NewUnsafeFetcher(logger)
+
+	result, err := f.Fetch(context.Background(), FetchOptions{
+		URL:             server.URL + "/a",
+		Timeout:         10 * time.Second,
+		FollowRedirects: true,
+	})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v, want loop to stop without error", err)
+	}
+
+	// Stops at the hop pointing back to /a and returns that 3xx response
+	if len(result.RedirectChain) != 2 {
+		t.Fatalf("len(RedirectChain) = %d, want 2", len(result.RedirectChain))
+	}
+	if result.StatusCode != 302 {
+		t.Errorf("StatusCode = %d, want 302", result.StatusCode)
+	}
+	if result.RedirectURL != server.URL+"/a" {
+		t.Errorf("RedirectURL = %q, want %q", result.RedirectURL, server.URL+"/a")
+	}
+}
+
+func TestFetcher_Fetch_RedirectBackWithCookieJar(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			if _, err := r.Cookie("session"); err != nil {
+				http.Redirect(w, r, "/b", http.StatusFound)
+				return
+			}
+			fmt.Fprint(w, "<html><body>Logged in</body></html>")
+		default:
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "1", Path: "/"})
+			http.Redirect(w, r, "/a", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	f := NewUnsafeFetcher(zap.NewNop())
+
+	result, err := f.Fetch(context.Background(), FetchOptions{
+		URL:             server.URL + "/a",
+		Timeout:         10 * time.Second,
+		FollowRedirects: true,
+		Cookies:         []*http.Cookie{{Name: "consent", Value: "1"}},
+	})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	// With a cookie jar, /b sets the cookie and the hop back to /a is followed
+	if result.StatusCode != 200 {
+		t.Errorf("StatusCode = %d, want 200", result.StatusCode)
+	}
+	if len(result.RedirectChain) != 2 {
+		t.Fatalf("len(RedirectChain) = %d, want 2", len(result.RedirectChain))
+	}
+	if flags := types.AnnotateRedirectChain(result.RedirectChain); !flags.Loop {
+		t.Error("RedirectFlags.Loop = false, want true for the hop back to /a")
+	}
+	if result.RedirectURL != "" {
+		t.Errorf("RedirectURL = %q, want empty", result.RedirectURL)
+	}
+}
+
+func TestFetcher_Fetch_NoFollowRedirects_Chain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/final", http.StatusMovedPermanently)
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	f := NewUnsafeFetcher(logger)
+
+	result, err := f.Fetch(context.Background(), FetchOptions{
+		URL:             server.URL + "/start",
+		Timeout:         10 * time.Second,
+		FollowRedirects: false,
+	})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	if len(result.RedirectChain) != 1 {
+		t.Fatalf("len(RedirectChain) = %d, want 1", len(result.RedirectChain))
+	}
+	if result.RedirectChain[0].StatusCode != 301 {
+		t.Errorf("hop status = %d, want 301", result.RedirectChain[0].StatusCode)
+	}
+}
+
+func TestFetcher_Fetch_Timing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(w, "<html><body>timed</body></html>")
+	}))
+	defer server.Close()
+
+	f := NewUnsafeFetcher(zap.NewNop())
+	result, err := f.Fetch(context.Background(), FetchOptions{
+		URL:     server.URL,
+		Timeout: 10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	if result.Timing == nil {
+		t.Fatal("Timing is nil")
+	}
+	if result.Timing.TTFB < 0.02 {
+		t.Errorf("TTFB = %v, want >= 0.02", result.Timing.TTFB)
+	}
+	if result.Timing.Connect <= 0 {
+		t.Errorf("Connect = %v, want > 0 for a new connection", result.Timing.Connect)
+	}
+	if result.Timing.TLS != 0 {
+		t.Errorf("TLS = %v, want 0 for plain HTTP", result.Timing.TLS)
+	}
+	if result.Protocol != types.ProtocolHTTP1 {
+		t.Errorf("Protocol = %q, want %q", result.Protocol, types.ProtocolHTTP1)
+	}
+	if result.RemoteIP != "127.0.0.1" {
+		t.Errorf("RemoteIP = %q, want 127.0.0.1", result.RemoteIP)
+	}
+
+	doc := result.DocumentRequest()
+	if doc.Timing != result.Timing || doc.Status != 200 || doc.URL != server.URL {
+		t.Errorf("DocumentRequest() = %+v", doc)
+	}
+}
+
+func TestFetcher_Fetch_TimingLastHop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/start" {
+			time.Sleep(50 * time.Millisecond)
+			http.Redirect(w, r, "/final", http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, "final")
+	}))
+	defer server.Close()
+
+	f := NewUnsafeFetcher(zap.NewNop())
+	result, err := f.Fetch(context.Background(), FetchOptions{
+		URL:             server.URL + "/start",
+		Timeout:         10 * time.Second,
+		FollowRedirects: true,
+	})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	// The slow redirect hop is not part of the final hop's TTFB
+	if result.Timing.TTFB >= 0.05 {
+		t.Errorf("TTFB = %v, want the final hop only", result.Timing.TTFB)
+	}
+}
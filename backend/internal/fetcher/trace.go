@@ -0,0 +1,132 @@
+package fetcher
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+// requestTrace records the phase timestamps of the last request hop.
+// Every redirect hop starts a new connection lookup, which resets the trace.
+type requestTrace struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	remoteAddr   net.Addr
+}
+
+// clientTrace returns the httptrace hooks feeding this trace
+func (t *requestTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			t.reset()
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.set(&t.dnsStart)
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.set(&t.dnsDone)
+		},
+		ConnectStart: func(string, string) {
+			// Dual-stack dialing may start several attempts, keep the first
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				t.set(&t.connectDone)
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.set(&t.tlsStart)
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.set(&t.tlsDone)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.remoteAddr = info.Conn.RemoteAddr()
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			t.set(&t.wroteRequest)
+		},
+		GotFirstResponseByte: func() {
+			t.set(&t.firstByte)
+		},
+	}
+}
+
+// reset clears the timestamps of the previous hop
+func (t *requestTrace) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.start = time.Now()
+	t.dnsStart, t.dnsDone = time.Time{}, time.Time{}
+	t.connectStart, t.connectDone = time.Time{}, time.Time{}
+	t.tlsStart, t.tlsDone = time.Time{}, time.Time{}
+	t.wroteRequest, t.firstByte = time.Time{}, time.Time{}
+	t.remoteAddr = nil
+}
+
+func (t *requestTrace) set(field *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*field = time.Now()
+}
+
+// timing returns the phase breakdown, with the download ending at end
+func (t *requestTrace) timing(end time.Time) *types.RequestTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sent := t.wroteRequest
+	if sent.IsZero() {
+		sent = t.start
+	}
+
+	return &types.RequestTiming{
+		DNS:      span(t.dnsStart, t.dnsDone),
+		Connect:  span(t.connectStart, t.connectDone),
+		TLS:      span(t.tlsStart, t.tlsDone),
+		TTFB:     span(sent, t.firstByte),
+		Download: span(t.firstByte, end),
+	}
+}
+
+// remoteIP returns the IP address of the connection used by the last hop
+func (t *requestTrace) remoteIP() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.remoteAddr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(t.remoteAddr.String())
+	if err != nil {
+		return t.remoteAddr.String()
+	}
+	return host
+}
+
+// span returns end-start in seconds, 0 when either timestamp is missing
+func span(start, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start).Seconds()
+}
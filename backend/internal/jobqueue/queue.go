@@ -0,0 +1,293 @@
+// Package jobqueue runs asynchronous API jobs (batch renders, crawls) on a
+// bounded queue. Jobs belong to the API key that submitted them and are kept
+// for a TTL after they finish.
+package jobqueue
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+var (
+	// ErrQueueFull is returned by Submit when MaxQueuedJobs jobs are waiting
+	ErrQueueFull = errors.New("job queue is full")
+	// ErrShuttingDown is returned by Submit after Shutdown
+	ErrShuttingDown = errors.New("job queue is shutting down")
+)
+
+// Work is the payload of a job. It guards its own state, which may be read
+// while Run is in progress.
+type Work interface {
+	// Run does the work until it is done or ctx ends
+	Run(ctx context.Context)
+}
+
+// Config contains queue settings. All values must be positive.
+type Config struct {
+	Name           string        // Job kind in log messages, e.g. "Batch"
+	MaxQueuedJobs  int           // Jobs waiting for a runner
+	MaxRunningJobs int           // Jobs running at the same time
+	ResultTTL      time.Duration // How long finished jobs are kept
+}
+
+// Job is a snapshot of a job
+type Job[T Work] struct {
+	ID         string
+	Status     string // types.JobStatus*
+	Work       T
+	CreatedAt  time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// entry is a queued, running or finished job. status and the timestamps are
+// guarded by Queue.mu.
+type entry[T Work] struct {
+	id     string
+	owner  string
+	work   T
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	status     string
+	createdAt  time.Time
+	startedAt  time.Time
+	finishedAt time.Time
+}
+
+// Queue runs jobs in submission order, at most MaxRunningJobs at a time
+type Queue[T Work] struct {
+	config Config
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	entries map[string]*entry[T]
+	queue   chan *entry[T]
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New creates a Queue. Call Start to begin running jobs.
+func New[T Work](config Config, logger *zap.Logger) *Queue[T] {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Queue[T]{
+		config:  config,
+		logger:  logger,
+		entries: make(map[string]*entry[T]),
+		queue:   make(chan *entry[T], config.MaxQueuedJobs),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start launches the job runners and the cleanup of expired jobs.
+// They stop when Shutdown is called.
+func (q *Queue[T]) Start(cleanupInterval time.Duration) {
+	for i := 0; i < q.config.MaxRunningJobs; i++ {
+		go q.runner()
+	}
+
+	go func() {
+		ticker := time.NewTicker(cleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-q.ctx.Done():
+				return
+			case <-ticker.C:
+				q.Cleanup()
+			}
+		}
+	}()
+}
+
+// Shutdown cancels all queued and running jobs
+func (q *Queue[T]) Shutdown() {
+	q.cancel()
+}
+
+// Submit queues work. owner is the API key that may read and cancel the job.
+func (q *Queue[T]) Submit(owner string, work T) (*Job[T], error) {
+	if q.ctx.Err() != nil {
+		return nil, ErrShuttingDown
+	}
+
+	ctx, cancel := context.WithCancel(q.ctx)
+	e := &entry[T]{
+		id:        uuid.New().String(),
+		owner:     owner,
+		work:      work,
+		ctx:       ctx,
+		cancel:    cancel,
+		status:    types.JobStatusQueued,
+		createdAt: time.Now(),
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	select {
+	case q.queue <- e:
+	default:
+		cancel()
+		return nil, ErrQueueFull
+	}
+	q.entries[e.id] = e
+
+	return e.snapshotLocked(), nil
+}
+
+// Get returns a snapshot of the job.
+// Returns false if the job does not exist or belongs to another owner.
+func (q *Queue[T]) Get(id, owner string) (*Job[T], bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	e, ok := q.lookupLocked(id, owner)
+	if !ok {
+		return nil, false
+	}
+	return e.snapshotLocked(), true
+}
+
+// Cancel stops a queued or running job. A running job's Run sees its context
+// canceled. Returns false if the job does not exist or belongs to another owner.
+func (q *Queue[T]) Cancel(id, owner string) (*Job[T], bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	e, ok := q.lookupLocked(id, owner)
+	if !ok {
+		return nil, false
+	}
+
+	if e.status == types.JobStatusQueued || e.status == types.JobStatusRunning {
+		e.cancel()
+		// A queued job is finished here; a running job is finished by its runner
+		if e.status == types.JobStatusQueued {
+			e.finishLocked()
+		}
+		q.logger.Info(q.config.Name+" job canceled", zap.String("job_id", e.id))
+	}
+
+	return e.snapshotLocked(), true
+}
+
+// lookupLocked finds a job by ID and owner. Must be called with mu held.
+func (q *Queue[T]) lookupLocked(id, owner string) (*entry[T], bool) {
+	e, ok := q.entries[id]
+	if !ok || subtle.ConstantTimeCompare([]byte(e.owner), []byte(owner)) != 1 {
+		return nil, false
+	}
+	return e, true
+}
+
+// runner processes queued jobs one at a time until shutdown
+func (q *Queue[T]) runner() {
+	for {
+		select {
+		case <-q.ctx.Done():
+			q.drainQueue()
+			return
+		case e := <-q.queue:
+			q.runJob(e)
+		}
+	}
+}
+
+// drainQueue finishes jobs still queued at shutdown
+func (q *Queue[T]) drainQueue() {
+	for {
+		select {
+		case e := <-q.queue:
+			q.mu.Lock()
+			if e.status == types.JobStatusQueued {
+				e.finishLocked()
+			}
+			q.mu.Unlock()
+		default:
+			return
+		}
+	}
+}
+
+// runJob runs the work and records the final status
+func (q *Queue[T]) runJob(e *entry[T]) {
+	q.mu.Lock()
+	if e.status != types.JobStatusQueued {
+		// Canceled while queued
+		q.mu.Unlock()
+		return
+	}
+	e.status = types.JobStatusRunning
+	e.startedAt = time.Now()
+	q.mu.Unlock()
+
+	e.work.Run(e.ctx)
+
+	q.mu.Lock()
+	e.finishLocked()
+	status := e.status
+	q.mu.Unlock()
+	e.cancel()
+
+	q.logger.Info(q.config.Name+" job finished",
+		zap.String("job_id", e.id),
+		zap.String("status", status),
+		zap.Float64("total_time", time.Since(e.startedAt).Seconds()),
+	)
+}
+
+// Cleanup removes jobs finished longer than ResultTTL ago
+func (q *Queue[T]) Cleanup() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for id, e := range q.entries {
+		if !e.finishedAt.IsZero() && time.Since(e.finishedAt) > q.config.ResultTTL {
+			delete(q.entries, id)
+		}
+	}
+}
+
+// finishLocked sets the final status. Must be called with Queue.mu held.
+func (e *entry[T]) finishLocked() {
+	if !e.finishedAt.IsZero() {
+		return
+	}
+	e.status = types.JobStatusCompleted
+	if e.ctx.Err() != nil {
+		e.status = types.JobStatusCanceled
+	}
+	e.finishedAt = time.Now()
+}
+
+// snapshotLocked copies the job state. Must be called with Queue.mu held.
+func (e *entry[T]) snapshotLocked() *Job[T] {
+	job := &Job[T]{
+		ID:        e.id,
+		Status:    e.status,
+		Work:      e.work,
+		CreatedAt: e.createdAt,
+	}
+	if !e.startedAt.IsZero() {
+		startedAt := e.startedAt
+		job.StartedAt = &startedAt
+	}
+	if !e.finishedAt.IsZero() {
+		finishedAt := e.finishedAt
+		job.FinishedAt = &finishedAt
+	}
+	return job
+}
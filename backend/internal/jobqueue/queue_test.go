@@ -0,0 +1,192 @@
+package jobqueue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/types"
+)
+
+// blockingWork runs until release is closed or its context ends
+type blockingWork struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingWork() *blockingWork {
+	return &blockingWork{started: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (w *blockingWork) Run(ctx context.Context) {
+	close(w.started)
+	select {
+	case <-w.release:
+	case <-ctx.Done():
+	}
+}
+
+func newTestQueue(config Config) *Queue[*blockingWork] {
+	if config.MaxQueuedJobs == 0 {
+		config.MaxQueuedJobs = 4
+	}
+	if config.MaxRunningJobs == 0 {
+		config.MaxRunningJobs = 1
+	}
+	if config.ResultTTL == 0 {
+		config.ResultTTL = time.Hour
+	}
+	config.Name = "Test"
+	return New[*blockingWork](config, zap.NewNop())
+}
+
+// waitForStatus polls until the job reaches status
+func waitForStatus(t *testing.T, q *Queue[*blockingWork], id, status string) *Job[*blockingWork] {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		job, ok := q.Get(id, "key")
+		if !ok {
+			t.Fatalf("Get(%q) not found", id)
+		}
+		if job.Status == status {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job status = %q, want %q", job.Status, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestQueue_RunsJob(t *testing.T) {
+	q := newTestQueue(Config{})
+	q.Start(time.Hour)
+	defer q.Shutdown()
+
+	work := newBlockingWork()
+	submitted, err := q.Submit("key", work)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if submitted.Status != types.JobStatusQueued || submitted.Work != work {
+		t.Errorf("Submit() = %+v, want the queued work", submitted)
+	}
+
+	<-work.started
+	running := waitForStatus(t, q, submitted.ID, types.JobStatusRunning)
+	if running.StartedAt == nil || running.FinishedAt != nil {
+		t.Errorf("running job = %+v, want StartedAt only", running)
+	}
+
+	close(work.release)
+	done := waitForStatus(t, q, submitted.ID, types.JobStatusCompleted)
+	if done.FinishedAt == nil {
+		t.Error("FinishedAt should be set")
+	}
+
+	if _, ok := q.Get(submitted.ID, "other-key"); ok {
+		t.Error("Get() with another key should not find the job")
+	}
+	if _, ok := q.Cancel(submitted.ID, "other-key"); ok {
+		t.Error("Cancel() with another key should not find the job")
+	}
+}
+
+func TestQueue_CancelRunning(t *testing.T) {
+	q := newTestQueue(Config{})
+	q.Start(time.Hour)
+	defer q.Shutdown()
+
+	work := newBlockingWork()
+	submitted, err := q.Submit("key", work)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-work.started
+
+	if _, ok := q.Cancel(submitted.ID, "key"); !ok {
+		t.Fatal("Cancel() returned false")
+	}
+	waitForStatus(t, q, submitted.ID, types.JobStatusCanceled)
+}
+
+func TestQueue_QueueFull(t *testing.T) {
+	q := newTestQueue(Config{MaxQueuedJobs: 1})
+	defer q.Shutdown()
+	// Not started: jobs stay queued
+
+	first, err := q.Submit("key", newBlockingWork())
+	if err != nil {
+		t.Fatalf("first Submit() error = %v", err)
+	}
+	if _, err := q.Submit("key", newBlockingWork()); err != ErrQueueFull {
+		t.Errorf("second Submit() error = %v, want ErrQueueFull", err)
+	}
+
+	job, ok := q.Cancel(first.ID, "key")
+	if !ok || job.Status != types.JobStatusCanceled || job.FinishedAt == nil {
+		t.Errorf("Cancel() = %+v, %v, want finished canceled job", job, ok)
+	}
+}
+
+func TestQueue_CanceledWhileQueuedNeverRuns(t *testing.T) {
+	q := newTestQueue(Config{})
+	defer q.Shutdown()
+
+	work := newBlockingWork()
+	submitted, err := q.Submit("key", work)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	q.Cancel(submitted.ID, "key")
+	q.Start(time.Hour)
+
+	select {
+	case <-work.started:
+		t.Error("canceled job should not run")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestQueue_Cleanup(t *testing.T) {
+	q := newTestQueue(Config{ResultTTL: time.Millisecond})
+	q.Start(time.Hour)
+	defer q.Shutdown()
+
+	work := newBlockingWork()
+	close(work.release)
+	submitted, err := q.Submit("key", work)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	waitForStatus(t, q, submitted.ID, types.JobStatusCompleted)
+
+	time.Sleep(5 * time.Millisecond)
+	q.Cleanup()
+
+	if _, ok := q.Get(submitted.ID, "key"); ok {
+		t.Error("expired job should be removed by Cleanup")
+	}
+}
+
+func TestQueue_Shutdown(t *testing.T) {
+	q := newTestQueue(Config{})
+	q.Start(time.Hour)
+
+	work := newBlockingWork()
+	submitted, err := q.Submit("key", work)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-work.started
+
+	q.Shutdown()
+	waitForStatus(t, q, submitted.ID, types.JobStatusCanceled)
+
+	if _, err := q.Submit("key", newBlockingWork()); err != ErrShuttingDown {
+		t.Errorf("Submit() error = %v, want ErrShuttingDown", err)
+	}
+}
@@ -0,0 +1,249 @@
+// Package metrics collects service metrics and renders them in the
+// Prometheus text exposition format (version 0.0.4).
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the Content-Type of the Prometheus text format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Metric types
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// Render outcomes
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// DefaultBuckets are the render duration histogram upper bounds in seconds
+var DefaultBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60}
+
+// Label is a single metric label
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a single metric value with its labels
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+// histogram holds cumulative bucket counts for one label set
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// Metrics collects request counters and latency histograms.
+// All methods are safe for concurrent use.
+type Metrics struct {
+	mu             sync.Mutex
+	buckets        []float64
+	renderDuration map[[2]string]*histogram // mode, outcome
+	errors         map[string]uint64        // error code
+	apiKeyRequests map[[2]string]uint64     // key index, endpoint
+}
+
+// New creates a Metrics with DefaultBuckets
+func New() *Metrics {
+	return &Metrics{
+		buckets:        DefaultBuckets,
+		renderDuration: make(map[[2]string]*histogram),
+		errors:         make(map[string]uint64),
+		apiKeyRequests: make(map[[2]string]uint64),
+	}
+}
+
+// ObserveRender records the duration of a render or fetch
+func (m *Metrics) ObserveRender(mode, outcome string, seconds float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := [2]string{mode, outcome}
+	h, ok := m.renderDuration[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.renderDuration[key] = h
+	}
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// IncError counts an error response by its error code
+func (m *Metrics) IncError(code string) {
+	m.mu.Lock()
+	m.errors[code]++
+	m.mu.Unlock()
+}
+
+// IncAPIKeyRequest counts an authenticated external API request by the
+// index of its key in the configured key list, so no key material is exposed
+func (m *Metrics) IncAPIKeyRequest(keyIndex int, endpoint string) {
+	m.mu.Lock()
+	m.apiKeyRequests[[2]string{strconv.Itoa(keyIndex), endpoint}]++
+	m.mu.Unlock()
+}
+
+// WritePrometheus writes all collected counters and histograms
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.writeRenderDuration(w); err != nil {
+		return err
+	}
+
+	errorSamples := make([]Sample, 0, len(m.errors))
+	for code, count := range m.errors {
+		errorSamples = append(errorSamples, Sample{
+			Labels: []Label{{"code", code}},
+			Value:  float64(count),
+		})
+	}
+	if err := WriteMetric(w, "jsbug_errors_total", TypeCounter,
+		"Error responses by error code.", errorSamples...); err != nil {
+		return err
+	}
+
+	keySamples := make([]Sample, 0, len(m.apiKeyRequests))
+	for key, count := range m.apiKeyRequests {
+		keySamples = append(keySamples, Sample{
+			Labels: []Label{{"api_key_index", key[0]}, {"endpoint", key[1]}},
+			Value:  float64(count),
+		})
+	}
+	return WriteMetric(w, "jsbug_api_requests_total", TypeCounter,
+		"External API requests by API key index and endpoint.", keySamples...)
+}
+
+// writeRenderDuration writes the render duration histogram. Must be called with mu held.
+func (m *Metrics) writeRenderDuration(w io.Writer) error {
+	const name = "jsbug_render_duration_seconds"
+
+	keys := make([][2]string, 0, len(m.renderDuration))
+	for key := range m.renderDuration {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+
+	if _, err := fmt.Fprintf(w, "# HELP %s Render and fetch duration by mode and outcome.\n# TYPE %s %s\n",
+		name, name, TypeHistogram); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		h := m.renderDuration[key]
+		labels := []Label{{"mode", key[0]}, {"outcome", key[1]}}
+
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += h.counts[i]
+			if err := writeSample(w, name+"_bucket",
+				append(labels, Label{"le", formatValue(bound)}), float64(cumulative)); err != nil {
+				return err
+			}
+		}
+		if err := writeSample(w, name+"_bucket",
+			append(labels, Label{"le", "+Inf"}), float64(h.count)); err != nil {
+			return err
+		}
+		if err := writeSample(w, name+"_sum", labels, h.sum); err != nil {
+			return err
+		}
+		if err := writeSample(w, name+"_count", labels, float64(h.count)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteMetric writes a metric family: HELP and TYPE lines followed by its
+// samples sorted by label values
+func WriteMetric(w io.Writer, name, metricType, help string, samples ...Sample) error {
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType); err != nil {
+		return err
+	}
+
+	sort.SliceStable(samples, func(i, j int) bool {
+		return labelString(samples[i].Labels) < labelString(samples[j].Labels)
+	})
+	for _, sample := range samples {
+		if err := writeSample(w, name, sample.Labels, sample.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSample writes a single "name{labels} value" line
+func writeSample(w io.Writer, name string, labels []Label, value float64) error {
+	_, err := fmt.Fprintf(w, "%s%s %s\n", name, labelString(labels), formatValue(value))
+	return err
+}
+
+// labelString formats labels as {name="value",...}, or "" without labels
+func labelString(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, label := range labels {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(label.Name)
+		sb.WriteString(`="`)
+		sb.WriteString(escapeLabelValue(label.Value))
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// escapeLabelValue escapes backslash, double quote and newline
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// formatValue formats a sample value the way Prometheus expects
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMetrics_ObserveRender(t *testing.T) {
+	m := New()
+	m.ObserveRender("js", OutcomeSuccess, 0.3)
+	m.ObserveRender("js", OutcomeSuccess, 4)
+	m.ObserveRender("js", OutcomeSuccess, 120)
+	m.ObserveRender("http", OutcomeError, 0.05)
+
+	var buf bytes.Buffer
+	if err := m.WritePrometheus(&buf); err != nil {
+		t.Fatalf("WritePrometheus() error = %v", err)
+	}
+	out := buf.String()
+
+	want := []string{
+		"# TYPE jsbug_render_duration_seconds histogram",
+		`jsbug_render_duration_seconds_bucket{mode="http",outcome="error",le="0.1"} 1`,
+		`jsbug_render_duration_seconds_bucket{mode="js",outcome="success",le="0.25"} 0`,
+		`jsbug_render_duration_seconds_bucket{mode="js",outcome="success",le="0.5"} 1`,
+		`jsbug_render_duration_seconds_bucket{mode="js",outcome="success",le="5"} 2`,
+		`jsbug_render_duration_seconds_bucket{mode="js",outcome="success",le="60"} 2`,
+		`jsbug_render_duration_seconds_bucket{mode="js",outcome="success",le="+Inf"} 3`,
+		`jsbug_render_duration_seconds_sum{mode="js",outcome="success"} 124.3`,
+		`jsbug_render_duration_seconds_count{mode="js",outcome="success"} 3`,
+	}
+	for _, line := range want {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("output missing %q\n%s", line, out)
+		}
+	}
+
+	// http sorts before js
+	if strings.Index(out, `mode="http"`) > strings.Index(out, `mode="js"`) {
+		t.Error("histogram series should be sorted by label values")
+	}
+}
+
+func TestMetrics_Counters(t *testing.T) {
+	m := New()
+	m.IncError("RENDER_TIMEOUT")
+	m.IncError("RENDER_TIMEOUT")
+	m.IncError("INVALID_URL")
+	m.IncAPIKeyRequest(1, "render")
+
+	var buf bytes.Buffer
+	if err := m.WritePrometheus(&buf); err != nil {
+		t.Fatalf("WritePrometheus() error = %v", err)
+	}
+	out := buf.String()
+
+	want := []string{
+		"# TYPE jsbug_errors_total counter",
+		`jsbug_errors_total{code="INVALID_URL"} 1`,
+		`jsbug_errors_total{code="RENDER_TIMEOUT"} 2`,
+		`jsbug_api_requests_total{api_key_index="1",endpoint="render"} 1`,
+	}
+	for _, line := range want {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("output missing %q\n%s", line, out)
+		}
+	}
+}
+
+func TestWriteMetric(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteMetric(&buf, "test_gauge", TypeGauge, "A test gauge.",
+		Sample{Labels: []Label{{"name", "b"}}, Value: 2},
+		Sample{Labels: []Label{{"name", `a"\` + "\n"}}, Value: 1.5},
+	)
+	if err != nil {
+		t.Fatalf("WriteMetric() error = %v", err)
+	}
+
+	want := "# HELP test_gauge A test gauge.\n" +
+		"# TYPE test_gauge gauge\n" +
+		`test_gauge{name="a\"\\\n"} 1.5` + "\n" +
+		`test_gauge{name="b"} 2` + "\n"
+	if buf.String() != want {
+		t.Errorf("WriteMetric() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
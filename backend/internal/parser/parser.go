@@ -8,6 +8,7 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/user/jsbug/internal/types"
+	"golang.org/x/net/html"
 )
 
 // ParseResult contains extracted content from HTML
@@ -40,6 +41,10 @@ type ParseOptions struct {
 	PageURL    string
 	XRobotsTag string
 	LinkHeader string
+
+	// ScriptingDisabled parses <noscript> content as page content, as a
+	// browser without JavaScript shows it (chrome_nojs render mode)
+	ScriptingDisabled bool
 }
 
 // Parser extracts SEO-relevant content from HTML
@@ -55,9 +60,27 @@ func (p *Parser) Parse(htmlContent string, pageURL string) (*ParseResult, error)
 	return p.ParseWithOptions(htmlContent, ParseOptions{PageURL: pageURL})
 }
 
+// NewDocument parses HTML into a goquery document. With scriptingDisabled the
+// <noscript> elements are parsed as markup and unwrapped, so their content is
+// extracted like the rest of the page.
+func NewDocument(htmlContent string, scriptingDisabled bool) (*goquery.Document, error) {
+	if !scriptingDisabled {
+		return goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	}
+	root, err := html.ParseWithOptions(strings.NewReader(htmlContent), html.ParseOptionEnableScripting(false))
+	if err != nil {
+		return nil, err
+	}
+	doc := goquery.NewDocumentFromNode(root)
+	doc.Find("noscript").Each(func(_ int, s *goquery.Selection) {
+		s.ReplaceWithSelection(s.Contents())
+	})
+	return doc, nil
+}
+
 // ParseWithOptions extracts content from HTML with additional options
 func (p *Parser) ParseWithOptions(htmlContent string, opts ParseOptions) (*ParseResult, error) {
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	doc, err := NewDocument(htmlContent, opts.ScriptingDisabled)
 	if err != nil {
 		return nil, err
 	}
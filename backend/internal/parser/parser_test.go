@@ -552,6 +552,46 @@ func TestParseWithOptions_BodyMarkdown(t *testing.T) {
 	}
 }
 
+func TestParseWithOptions_ScriptingDisabled(t *testing.T) {
+	html := `<html>
+<head><title>Test</title></head>
+<body>
+    <div id="app"></div>
+    <noscript>
+        <h1>Please enable JavaScript</h1>
+        <a href="/sitemap">Browse the sitemap</a>
+    </noscript>
+</body>
+</html>`
+
+	parser := NewParser()
+
+	result, err := parser.ParseWithOptions(html, ParseOptions{PageURL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("ParseWithOptions() error = %v", err)
+	}
+	if len(result.H1) != 0 || len(result.Links) != 0 {
+		t.Errorf("noscript content parsed with scripting enabled: H1 = %v, Links = %d", result.H1, len(result.Links))
+	}
+
+	result, err = parser.ParseWithOptions(html, ParseOptions{
+		PageURL:           "https://example.com",
+		ScriptingDisabled: true,
+	})
+	if err != nil {
+		t.Fatalf("ParseWithOptions() error = %v", err)
+	}
+	if len(result.H1) != 1 || result.H1[0] != "Please enable JavaScript" {
+		t.Errorf("H1 = %v, want [Please enable JavaScript]", result.H1)
+	}
+	if len(result.Links) != 1 || result.Links[0].Href != "https://example.com/sitemap" {
+		t.Errorf("Links = %+v, want the sitemap link", result.Links)
+	}
+	if result.WordCount == 0 {
+		t.Error("WordCount should include the noscript text")
+	}
+}
+
 func TestFullMarkdownExtraction(t *testing.T) {
 	html := `<!DOCTYPE html>
 <html>
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/temoto/robotstxt"
@@ -43,12 +44,32 @@ func (c *Checker) Check(ctx context.Context, targetURL string) (bool, error) {
 		return true, fmt.Errorf("failed to parse URL: %w", err)
 	}
 
+	group := c.findGroup(ctx, parsed)
+	if group == nil {
+		return true, nil // Fail open
+	}
+
+	// Check if the URL is allowed for Googlebot
+	allowed := group.Test(parsed.Path)
+
+	c.logger.Debug("Robots.txt check completed",
+		zap.String("target_url", targetURL),
+		zap.String("path", parsed.Path),
+		zap.Bool("is_allowed", allowed),
+	)
+
+	return allowed, nil
+}
+
+// findGroup fetches robots.txt for the URL's host and returns the Googlebot group.
+// Returns nil if robots.txt could not be fetched or parsed.
+func (c *Checker) findGroup(ctx context.Context, parsed *url.URL) *robotstxt.Group {
 	// Construct robots.txt URL
 	robotsURL := fmt.Sprintf("%s://%s/robots.txt", parsed.Scheme, parsed.Host)
 
 	c.logger.Debug("Fetching robots.txt",
 		zap.String("robots_url", robotsURL),
-		zap.String("target_url", targetURL),
+		zap.String("target_url", parsed.String()),
 	)
 
 	// Fetch and parse robots.txt using FromResponse
@@ -59,20 +80,50 @@ func (c *Checker) Check(ctx context.Context, targetURL string) (bool, error) {
 			zap.String("robots_url", robotsURL),
 			zap.Error(err),
 		)
-		return true, nil // Fail open
+		return nil
 	}
 
-	// Check if the URL is allowed for Googlebot
-	group := robots.FindGroup(botName)
-	allowed := group.Test(parsed.Path)
+	return robots.FindGroup(botName)
+}
 
-	c.logger.Debug("Robots.txt check completed",
-		zap.String("target_url", targetURL),
-		zap.String("path", parsed.Path),
-		zap.Bool("is_allowed", allowed),
-	)
+// CachedChecker checks many URLs while fetching robots.txt once per host.
+// It is meant for a single crawl and never expires entries.
+type CachedChecker struct {
+	checker *Checker
+	mu      sync.Mutex
+	groups  map[string]*robotstxt.Group // scheme://host -> group, nil = allow all
+}
 
-	return allowed, nil
+// NewCachedChecker creates a CachedChecker backed by checker
+func NewCachedChecker(checker *Checker) *CachedChecker {
+	return &CachedChecker{
+		checker: checker,
+		groups:  make(map[string]*robotstxt.Group),
+	}
+}
+
+// Check is like Checker.Check but reuses robots.txt fetched for the same host
+func (cc *CachedChecker) Check(ctx context.Context, targetURL string) (bool, error) {
+	parsed, err := url.Parse(targetURL)
+	if err != nil {
+		return true, fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	key := parsed.Scheme + "://" + parsed.Host
+
+	// Held during the fetch so concurrent checks for a host fetch robots.txt once
+	cc.mu.Lock()
+	group, ok := cc.groups[key]
+	if !ok {
+		group = cc.checker.findGroup(ctx, parsed)
+		cc.groups[key] = group
+	}
+	cc.mu.Unlock()
+
+	if group == nil {
+		return true, nil
+	}
+	return group.Test(parsed.Path), nil
 }
 
 // fetchAndParseRobotsTxt fetches and parses robots.txt using FromResponse
@@ -321,3 +321,40 @@ Allow: /
 		t.Error("Check() returned false, expected true for Allow: /")
 	}
 }
+
+func TestCachedChecker_FetchesOncePerHost(t *testing.T) {
+	var robotsFetches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			robotsFetches++
+			fmt.Fprint(w, "User-agent: Googlebot\nDisallow: /private/\n")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cc := NewCachedChecker(NewChecker(zap.NewNop()))
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/public/page", true},
+		{"/private/page", false},
+		{"/", true},
+	}
+	for _, tt := range tests {
+		allowed, err := cc.Check(context.Background(), server.URL+tt.path)
+		if err != nil {
+			t.Fatalf("Check(%s) error = %v", tt.path, err)
+		}
+		if allowed != tt.want {
+			t.Errorf("Check(%s) = %v, want %v", tt.path, allowed, tt.want)
+		}
+	}
+
+	if robotsFetches != 1 {
+		t.Errorf("robots.txt fetched %d times, want 1", robotsFetches)
+	}
+}
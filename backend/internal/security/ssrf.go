@@ -79,6 +79,17 @@ func ValidateURL(rawURL string) error {
 		return fmt.Errorf("URL has no hostname")
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return ValidateHost(ctx, hostname)
+}
+
+// ValidateHost checks that a bare hostname (no port, no brackets) does not
+// target private/internal network resources. Domain names are resolved and
+// every resolved IP is checked. DNS resolution failures are not treated as
+// SSRF issues; the subsequent fetch or render fails on its own.
+func ValidateHost(ctx context.Context, hostname string) error {
 	// Block known dangerous hostnames
 	if blockedHostnames[strings.ToLower(hostname)] {
 		return fmt.Errorf("hostname %q is not allowed", hostname)
@@ -93,9 +104,6 @@ func ValidateURL(rawURL string) error {
 	}
 
 	// Hostname is a domain name: resolve and check all IPs
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
 	ips, err := net.DefaultResolver.LookupIPAddr(ctx, hostname)
 	if err != nil {
 		// DNS resolution failure is not an SSRF issue; let it fail later at fetch/render
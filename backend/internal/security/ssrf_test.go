@@ -1,6 +1,7 @@
 package security
 
 import (
+	"context"
 	"net"
 	"testing"
 
@@ -131,3 +132,30 @@ func TestValidateURL(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateHost(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      string
+		wantError bool
+	}{
+		{"blocks loopback literal", "127.0.0.1", true},
+		{"blocks rfc1918 literal", "10.1.2.3", true},
+		{"blocks IPv6 loopback literal", "::1", true},
+		{"blocks localhost", "localhost", true},
+		{"blocks mixed-case localhost", "LocalHost", true},
+		{"allows public literal", "8.8.8.8", false},
+		{"allows unresolvable domain", "does-not-exist.invalid", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHost(context.Background(), tt.host)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/user/jsbug/internal/blobstore"
+)
+
+// BlobOptions configures the route and response headers of a BlobHandler
+type BlobOptions struct {
+	Prefix      string // Route prefix ending in "/", e.g. "/api/pdf/"
+	Name        string // Blob kind in error messages, e.g. "PDF"
+	ContentType string // Empty = detected from the data
+	Disposition string // "inline", or "attachment" named {id}{Extension}
+	Extension   string // File name extension of attachments, e.g. ".har"
+}
+
+// BlobHandler serves screenshots, PDFs and HARs from a blob store by ID
+type BlobHandler struct {
+	store   *blobstore.Store
+	options BlobOptions
+}
+
+// NewBlobHandler creates a new BlobHandler
+func NewBlobHandler(store *blobstore.Store, options BlobOptions) *BlobHandler {
+	return &BlobHandler{
+		store:   store,
+		options: options,
+	}
+}
+
+// blobErrorResponse represents an error response for blob endpoints
+type blobErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// HandleGet serves a blob by ID
+func (h *BlobHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// Expected path: {prefix}{id}
+	id := strings.TrimSpace(strings.TrimPrefix(r.URL.Path, h.options.Prefix))
+	if id == "" {
+		h.writeError(w, http.StatusBadRequest, "missing "+h.options.Name+" ID")
+		return
+	}
+
+	// Validate UUID format
+	if _, err := uuid.Parse(id); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid "+h.options.Name+" ID")
+		return
+	}
+
+	data, found := h.store.Get(id)
+	if !found {
+		h.writeError(w, http.StatusNotFound, h.options.Name+" not found or expired")
+		return
+	}
+
+	contentType := h.options.ContentType
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	disposition := h.options.Disposition
+	if disposition == "attachment" {
+		disposition += `; filename="` + id + h.options.Extension + `"`
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", disposition)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// writeError writes a JSON error response
+func (h *BlobHandler) writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(blobErrorResponse{Error: message})
+}
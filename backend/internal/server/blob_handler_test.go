@@ -0,0 +1,78 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/user/jsbug/internal/blobstore"
+)
+
+func TestBlobHandler_HandleGet(t *testing.T) {
+	store := blobstore.NewStore(time.Minute)
+	id := store.Store([]byte("%PDF-1.4 test"))
+	handler := NewBlobHandler(store, BlobOptions{
+		Prefix:      "/api/pdf/",
+		Name:        "PDF",
+		ContentType: "application/pdf",
+		Disposition: "inline",
+	})
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{name: "found", method: http.MethodGet, path: "/api/pdf/" + id, wantStatus: http.StatusOK},
+		{name: "missing id", method: http.MethodGet, path: "/api/pdf/", wantStatus: http.StatusBadRequest},
+		{name: "invalid id", method: http.MethodGet, path: "/api/pdf/not-a-uuid", wantStatus: http.StatusBadRequest},
+		{name: "unknown id", method: http.MethodGet, path: "/api/pdf/00000000-0000-0000-0000-000000000000", wantStatus: http.StatusNotFound},
+		{name: "wrong method", method: http.MethodPost, path: "/api/pdf/" + id, wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleGet(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK {
+				if ct := w.Header().Get("Content-Type"); ct != "application/pdf" {
+					t.Errorf("Content-Type = %q, want application/pdf", ct)
+				}
+				if cd := w.Header().Get("Content-Disposition"); cd != "inline" {
+					t.Errorf("Content-Disposition = %q, want inline", cd)
+				}
+				if w.Body.String() != "%PDF-1.4 test" {
+					t.Errorf("body = %q", w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestBlobHandler_AttachmentAndDetectedType(t *testing.T) {
+	store := blobstore.NewStore(time.Minute)
+	harID := store.Store([]byte(`{"log":{"version":"1.2"}}`))
+	pngID := store.Store([]byte("\x89PNG\r\n\x1a\n"))
+
+	har := NewBlobHandler(store, BlobOptions{Prefix: "/api/har/", Name: "HAR", ContentType: "application/json", Disposition: "attachment", Extension: ".har"})
+	w := httptest.NewRecorder()
+	har.HandleGet(w, httptest.NewRequest(http.MethodGet, "/api/har/"+harID, nil))
+	if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename="`+harID+`.har"` {
+		t.Errorf("Content-Disposition = %q, want attachment named %s.har", cd, harID)
+	}
+
+	screenshot := NewBlobHandler(store, BlobOptions{Prefix: "/api/screenshot/", Name: "screenshot", Disposition: "inline"})
+	w = httptest.NewRecorder()
+	screenshot.HandleGet(w, httptest.NewRequest(http.MethodGet, "/api/screenshot/"+pngID, nil))
+	if ct := w.Header().Get("Content-Type"); ct != "image/png" {
+		t.Errorf("Content-Type = %q, want detected image/png", ct)
+	}
+}
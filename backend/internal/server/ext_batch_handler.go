@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/batch"
+	"github.com/user/jsbug/internal/config"
+	"github.com/user/jsbug/internal/jobqueue"
+	"github.com/user/jsbug/internal/types"
+)
+
+// maxBatchBodyBytes limits batch request bodies (up to 1000 URLs plus options)
+const maxBatchBodyBytes = 4 << 20 // 4 MB
+
+// ExtBatchHandler handles external API batch render requests
+type ExtBatchHandler struct {
+	*extJobHandler[types.ExtBatchJob]
+	manager *batch.Manager
+}
+
+// NewExtBatchHandler creates a new ExtBatchHandler. It serves
+// POST /api/ext/batch, GET /api/ext/batch/{id} and DELETE /api/ext/batch/{id}.
+func NewExtBatchHandler(renderHandler *RenderHandler, manager *batch.Manager, cfg *config.Config, logger *zap.Logger) *ExtBatchHandler {
+	h := &ExtBatchHandler{manager: manager}
+	h.extJobHandler = &extJobHandler[types.ExtBatchJob]{
+		renderHandler:   renderHandler,
+		apiKeys:         newAPIKeySet(cfg),
+		logger:          logger,
+		prefix:          "/api/ext/batch",
+		feature:         "batch",
+		notFoundCode:    types.ErrBatchNotFound,
+		notFoundMessage: "Batch job not found",
+		submit:          h.handleSubmit,
+		get:             manager.Get,
+		cancel:          manager.Cancel,
+	}
+	return h
+}
+
+// handleSubmit validates a batch request and queues it as a job
+func (h *ExtBatchHandler) handleSubmit(w http.ResponseWriter, r *http.Request, apiKey string) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBatchBodyBytes)
+
+	var batchReq types.ExtBatchRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&batchReq); err != nil {
+		h.logger.Warn("Invalid request body", zap.Error(err))
+		h.writeError(w, http.StatusBadRequest, types.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	batchReq.ApplyDefaults()
+	if err := batchReq.Validate(); err != nil {
+		h.writeError(w, http.StatusBadRequest, types.ErrInvalidBatch, err.Error())
+		return
+	}
+	if err := batchReq.Options.ValidateDiagnostics(); err != nil {
+		h.writeError(w, http.StatusBadRequest, types.ErrInvalidFilter, err.Error())
+		return
+	}
+
+	// Validate every URL with the shared options before queueing anything.
+	// The SSRF check resolves hosts, so it runs per item when the job runs.
+	for i, url := range batchReq.URLs {
+		extReq := batchReq.Options
+		extReq.URL = url
+		req := extReq.ToRenderRequest()
+		req.ApplyDefaults()
+		if renderErr := h.renderHandler.validateRequestSyntax(req); renderErr != nil {
+			h.writeError(w, types.ErrorCodeToHTTPStatus(renderErr.Code), renderErr.Code,
+				fmt.Sprintf("urls[%d]: %s", i, renderErr.Message))
+			return
+		}
+	}
+
+	options := batchReq.Options
+	render := func(ctx context.Context, url string) (*types.ExtRenderData, *types.RenderError) {
+		if renderErr := h.renderHandler.checkSSRF(url); renderErr != nil {
+			return nil, renderErr
+		}
+		extReq := options
+		extReq.URL = url
+		req := extReq.ToRenderRequest()
+		req.ApplyDefaults()
+		return renderExt(ctx, h.renderHandler, req, &extReq)
+	}
+
+	job, err := h.manager.Submit(apiKey, batchReq.URLs, batchReq.Concurrency, render)
+	if err != nil {
+		if errors.Is(err, jobqueue.ErrShuttingDown) {
+			h.writeError(w, http.StatusServiceUnavailable, types.ErrPoolShuttingDown, "Service shutting down")
+			return
+		}
+		h.writeError(w, http.StatusServiceUnavailable, types.ErrBatchQueueFull, "Batch queue is full, try again later")
+		return
+	}
+
+	h.logger.Info("Ext batch request",
+		zap.String("job_id", job.ID),
+		zap.String("api_key", maskAPIKey(apiKey)),
+		zap.Int("urls", len(batchReq.URLs)),
+		zap.Int("concurrency", batchReq.Concurrency),
+		zap.Bool("js_enabled", batchReq.Options.JSEnabled),
+	)
+
+	h.writeJSON(w, http.StatusAccepted, &types.ExtBatchResponse{Success: true, Data: job})
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/batch"
+	"github.com/user/jsbug/internal/types"
+)
+
+func newTestBatchHandler(t *testing.T) *ExtBatchHandler {
+	t.Helper()
+	extHandler := newTestExtHandler()
+	manager := batch.NewManager(batch.Config{}, zap.NewNop())
+	manager.Start(time.Hour)
+	t.Cleanup(manager.Shutdown)
+	return NewExtBatchHandler(extHandler.renderHandler, manager, testAPIConfig(), zap.NewNop())
+}
+
+func doBatchRequest(handler *ExtBatchHandler, method, path, body string) (*httptest.ResponseRecorder, types.ExtBatchResponse) {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("X-API-Key", "test-key-abc123")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var resp types.ExtBatchResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	return w, resp
+}
+
+func TestExtBatchHandler_SubmitAndGet(t *testing.T) {
+	handler := newTestBatchHandler(t)
+
+	body := `{"urls":["https://example.com/a","https://example.com/b"],"options":{"js_enabled":false,"include_text":true}}`
+	w, resp := doBatchRequest(handler, http.MethodPost, "/api/ext/batch", body)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusAccepted)
+	}
+	if !resp.Success || resp.Data == nil || resp.Data.ID == "" {
+		t.Fatalf("submit response = %+v, want job with ID", resp)
+	}
+	if resp.Data.Concurrency != types.DefaultBatchConcurrency {
+		t.Errorf("Concurrency = %d, want default %d", resp.Data.Concurrency, types.DefaultBatchConcurrency)
+	}
+
+	id := resp.Data.ID
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		w, resp = doBatchRequest(handler, http.MethodGet, "/api/ext/batch/"+id, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET status = %d, want %d", w.Code, http.StatusOK)
+		}
+		if resp.Data.Status == types.JobStatusCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job status = %q, want completed", resp.Data.Status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if resp.Data.Progress.Succeeded != 2 {
+		t.Errorf("Progress.Succeeded = %d, want 2", resp.Data.Progress.Succeeded)
+	}
+	item := resp.Data.Items[1]
+	if item.URL != "https://example.com/b" || item.Data == nil || item.Data.BodyText == nil {
+		t.Errorf("Items[1] = %+v, want rendered data with body_text", item)
+	}
+}
+
+func TestExtBatchHandler_SSRFCheckedPerItem(t *testing.T) {
+	handler := newTestBatchHandler(t)
+
+	// Blocked URLs do not reject the batch; their items fail when they run
+	body := `{"urls":["http://127.0.0.1/admin"],"options":{"js_enabled":false}}`
+	w, resp := doBatchRequest(handler, http.MethodPost, "/api/ext/batch", body)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusAccepted)
+	}
+
+	id := resp.Data.ID
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		_, resp = doBatchRequest(handler, http.MethodGet, "/api/ext/batch/"+id, "")
+		if resp.Data.Status == types.JobStatusCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job status = %q, want completed", resp.Data.Status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	item := resp.Data.Items[0]
+	if item.Status != types.BatchItemError || item.Error == nil || item.Error.Code != types.ErrSSRFBlocked {
+		t.Errorf("Items[0] = %+v, want SSRF_BLOCKED error", item)
+	}
+}
+
+func TestExtBatchHandler_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode string
+	}{
+		{"no_urls", `{"urls":[]}`, types.ErrInvalidBatch},
+		{"bad_concurrency", `{"urls":["https://example.com"],"concurrency":99}`, types.ErrInvalidBatch},
+		{"bad_url", `{"urls":["https://example.com","ftp://example.com"]}`, types.ErrInvalidURL},
+		{"bad_option", `{"urls":["https://example.com"],"options":{"timeout":120}}`, types.ErrInvalidTimeout},
+		{"unknown_field", `{"urls":["https://example.com"],"url":"x"}`, types.ErrInvalidRequestBody},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestBatchHandler(t)
+			w, resp := doBatchRequest(handler, http.MethodPost, "/api/ext/batch", tt.body)
+			if w.Code != types.ErrorCodeToHTTPStatus(tt.wantCode) {
+				t.Errorf("status = %d, want %d", w.Code, types.ErrorCodeToHTTPStatus(tt.wantCode))
+			}
+			if resp.Error == nil || resp.Error.Code != tt.wantCode {
+				t.Errorf("error = %+v, want code %s", resp.Error, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestExtBatchHandler_NotFoundAndMethods(t *testing.T) {
+	handler := newTestBatchHandler(t)
+
+	w, resp := doBatchRequest(handler, http.MethodGet, "/api/ext/batch/unknown-id", "")
+	if w.Code != http.StatusNotFound || resp.Error == nil || resp.Error.Code != types.ErrBatchNotFound {
+		t.Errorf("GET unknown = %d %+v, want 404 BATCH_NOT_FOUND", w.Code, resp.Error)
+	}
+
+	w, _ = doBatchRequest(handler, http.MethodDelete, "/api/ext/batch/unknown-id", "")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("DELETE unknown status = %d, want 404", w.Code)
+	}
+
+	w, _ = doBatchRequest(handler, http.MethodGet, "/api/ext/batch", "")
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET list status = %d, want 405", w.Code)
+	}
+}
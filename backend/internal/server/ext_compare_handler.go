@@ -64,6 +64,7 @@ func (h *ExtCompareHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, http.StatusForbidden, types.ErrAPIKeyInvalid, "Invalid API key")
 		return
 	}
+	h.renderHandler.recordAPIKeyRequest(apiKey, "compare")
 
 	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
 
@@ -79,13 +80,17 @@ func (h *ExtCompareHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	jsReq := extReq.ToJSRenderRequest()
 	jsReq.ApplyDefaults()
 
-	httpReq := extReq.ToHTTPRenderRequest()
-	httpReq.ApplyDefaults()
+	nonJSReq := extReq.ToNonJSRenderRequest()
+	nonJSReq.ApplyDefaults()
 
 	if renderErr := h.renderHandler.validateRequest(jsReq); renderErr != nil {
 		h.writeError(w, types.ErrorCodeToHTTPStatus(renderErr.Code), renderErr.Code, renderErr.Message)
 		return
 	}
+	if err := extReq.ValidateNonJSRenderMode(); err != nil {
+		h.writeError(w, http.StatusBadRequest, types.ErrInvalidRenderMode, err.Error())
+		return
+	}
 
 	// Run both fetches in parallel
 	var jsResponse, httpResponse *types.RenderResponse
@@ -97,7 +102,11 @@ func (h *ExtCompareHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}()
 	go func() {
 		defer wg.Done()
-		httpResponse = h.renderHandler.handleFetch(r.Context(), httpReq)
+		if nonJSReq.UsesChrome() {
+			httpResponse = h.renderHandler.handleJSRender(r.Context(), nonJSReq)
+		} else {
+			httpResponse = h.renderHandler.handleFetch(r.Context(), nonJSReq)
+		}
 	}()
 	wg.Wait()
 
@@ -145,6 +154,9 @@ func (h *ExtCompareHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			IncludeLinks:          extReq.IncludeLinks,
 			IncludeImages:         extReq.IncludeImages,
 			IncludeStructuredData: extReq.IncludeStructuredData,
+			IncludeScreenshot:     extReq.IncludeScreenshot,
+			Screenshot:            extReq.Screenshot,
+			IncludeNetwork:        extReq.IncludeNetwork,
 		}
 		extData = buildExtResponse(jsResponse.Data, tmpExtReq)
 
@@ -158,6 +170,19 @@ func (h *ExtCompareHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Build the screenshot and network of the JS-less Chrome render, so the
+	// two sides can be compared visually
+	var nonJSData *types.ExtRenderData
+	if nonJSReq.UsesChrome() && httpResponse.Success {
+		nonJSData = buildExtResponse(httpResponse.Data, &types.ExtRenderRequest{
+			URL:               extReq.URL,
+			RenderMode:        types.RenderModeChromeNoJS,
+			IncludeScreenshot: extReq.IncludeScreenshot,
+			Screenshot:        extReq.Screenshot,
+			IncludeNetwork:    extReq.IncludeNetwork,
+		})
+	}
+
 	// Build diff and rendering impact (only when BOTH fetches succeed)
 	var diff *types.CompareDiff
 	var impact *types.RenderingImpact
@@ -180,7 +205,7 @@ func (h *ExtCompareHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			if err == nil {
 				jsSections = parser.ExtractSections(jsDoc)
 			}
-			nonJSDoc, err := goquery.NewDocumentFromReader(strings.NewReader(httpResponse.Data.HTML))
+			nonJSDoc, err := parser.NewDocument(httpResponse.Data.HTML, nonJSReq.UsesChrome())
 			if err == nil {
 				nonJSSections = parser.ExtractSections(nonJSDoc)
 			}
@@ -229,6 +254,7 @@ func (h *ExtCompareHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			JSStatus:        jsStatus,
 			HTTPStatus:      httpStatus,
 			JS:              extData,
+			NonJS:           nonJSData,
 			Diff:            diff,
 			RenderingImpact: impact,
 		},
@@ -247,6 +273,7 @@ func (h *ExtCompareHandler) writeJSON(w http.ResponseWriter, statusCode int, res
 }
 
 func (h *ExtCompareHandler) writeError(w http.ResponseWriter, statusCode int, code, message string) {
+	h.renderHandler.recordError(code)
 	resp := &types.ExtCompareResponse{
 		Success: false,
 		Error:   &types.RenderError{Code: code, Message: message},
@@ -255,10 +282,7 @@ func (h *ExtCompareHandler) writeError(w http.ResponseWriter, statusCode int, co
 }
 
 func (h *ExtCompareHandler) logRequest(req types.ExtCompareRequest, apiKey string, totalTime float64, status int) {
-	maskedKey := apiKey
-	if len(apiKey) > 4 {
-		maskedKey = "***" + apiKey[len(apiKey)-4:]
-	}
+	maskedKey := maskAPIKey(apiKey)
 	h.logger.Info("Ext compare request",
 		zap.String("url", req.URL),
 		zap.String("api_key", maskedKey),
@@ -269,6 +293,7 @@ func (h *ExtCompareHandler) logRequest(req types.ExtCompareRequest, apiKey strin
 		zap.Bool("include_links", req.IncludeLinks),
 		zap.Bool("include_images", req.IncludeImages),
 		zap.Bool("include_structured_data", req.IncludeStructuredData),
+		zap.String("non_js_render_mode", req.NonJSRenderMode),
 		zap.Float64("total_time", totalTime),
 		zap.Int("status", status),
 	)
@@ -193,6 +193,74 @@ func TestExtCompareHandler_MissingURL(t *testing.T) {
 	}
 }
 
+func TestExtCompareHandler_InvalidNonJSRenderMode(t *testing.T) {
+	handler := newTestExtCompareHandler()
+
+	body := `{"url":"https://example.com","non_js_render_mode":"js"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/ext/compare", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "test-key-abc123")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	var resp map[string]any
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	errObj, ok := resp["error"].(map[string]any)
+	if !ok {
+		t.Fatal("expected error object in response")
+	}
+	if errObj["code"] != "INVALID_RENDER_MODE" {
+		t.Errorf("error.code = %v, want INVALID_RENDER_MODE", errObj["code"])
+	}
+}
+
+func TestExtCompareHandler_ChromeNoJSSide(t *testing.T) {
+	handler := newTestExtCompareHandler()
+
+	body := `{"url":"https://example.com","non_js_render_mode":"chrome_nojs","include_screenshot":true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/ext/compare", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "test-key-abc123")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var resp map[string]any
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	data, ok := resp["data"].(map[string]any)
+	if !ok {
+		t.Fatal("expected data object in response")
+	}
+
+	// The non-JS side renders in Chrome, not with the mock fetcher, so it
+	// fails without a Chrome pool
+	httpStatus, ok := data["http_status"].(map[string]any)
+	if !ok {
+		t.Fatal("expected http_status object in data")
+	}
+	if httpStatus["success"] != false {
+		t.Errorf("http_status.success = %v, want false", httpStatus["success"])
+	}
+	errObj, _ := httpStatus["error"].(map[string]any)
+	if errObj["code"] != "CHROME_UNAVAILABLE" {
+		t.Errorf("http_status.error.code = %v, want CHROME_UNAVAILABLE", errObj["code"])
+	}
+	if data["non_js"] != nil {
+		t.Errorf("non_js = %v, want nil (render failed)", data["non_js"])
+	}
+}
+
 func TestExtCompareHandler_ResponseStructure(t *testing.T) {
 	handler := newTestExtCompareHandler()
 
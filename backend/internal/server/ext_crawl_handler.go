@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/config"
+	"github.com/user/jsbug/internal/crawl"
+	"github.com/user/jsbug/internal/jobqueue"
+	"github.com/user/jsbug/internal/robots"
+	"github.com/user/jsbug/internal/security"
+	"github.com/user/jsbug/internal/types"
+)
+
+// ExtCrawlHandler handles external API crawl requests
+type ExtCrawlHandler struct {
+	*extJobHandler[types.ExtCrawlJob]
+	manager       *crawl.Manager
+	robotsChecker *robots.Checker
+}
+
+// NewExtCrawlHandler creates a new ExtCrawlHandler. It serves
+// POST /api/ext/crawl, GET /api/ext/crawl/{id} and DELETE /api/ext/crawl/{id}.
+func NewExtCrawlHandler(renderHandler *RenderHandler, manager *crawl.Manager, robotsChecker *robots.Checker, cfg *config.Config, logger *zap.Logger) *ExtCrawlHandler {
+	h := &ExtCrawlHandler{manager: manager, robotsChecker: robotsChecker}
+	h.extJobHandler = &extJobHandler[types.ExtCrawlJob]{
+		renderHandler:   renderHandler,
+		apiKeys:         newAPIKeySet(cfg),
+		logger:          logger,
+		prefix:          "/api/ext/crawl",
+		feature:         "crawl",
+		notFoundCode:    types.ErrCrawlNotFound,
+		notFoundMessage: "Crawl job not found",
+		submit:          h.handleSubmit,
+		get:             manager.Get,
+		cancel:          manager.Cancel,
+	}
+	return h
+}
+
+// handleSubmit validates a crawl request and queues it as a job
+func (h *ExtCrawlHandler) handleSubmit(w http.ResponseWriter, r *http.Request, apiKey string) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1 MB
+
+	var crawlReq types.ExtCrawlRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&crawlReq); err != nil {
+		h.logger.Warn("Invalid request body", zap.Error(err))
+		h.writeError(w, http.StatusBadRequest, types.ErrInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	crawlReq.ApplyDefaults()
+	if err := crawlReq.Validate(); err != nil {
+		h.writeError(w, http.StatusBadRequest, types.ErrInvalidCrawl, err.Error())
+		return
+	}
+
+	// Validate the seed URL with the shared page options
+	seedReq := crawlReq.Options
+	seedReq.URL = crawlReq.URL
+	req := seedReq.ToRenderRequest()
+	req.ApplyDefaults()
+	if renderErr := h.renderHandler.validateRequest(req); renderErr != nil {
+		h.writeError(w, types.ErrorCodeToHTTPStatus(renderErr.Code), renderErr.Code, renderErr.Message)
+		return
+	}
+
+	options := crawlReq.Options
+	page := func(ctx context.Context, url string) (*types.RenderData, *types.RenderError) {
+		extReq := options
+		extReq.URL = url
+		req := extReq.ToRenderRequest()
+		req.ApplyDefaults()
+
+		var response *types.RenderResponse
+		if req.UsesChrome() {
+			response = h.renderHandler.handleJSRender(ctx, req)
+		} else {
+			response = h.renderHandler.handleFetch(ctx, req)
+		}
+		if !response.Success {
+			return nil, response.Error
+		}
+		return response.Data, nil
+	}
+
+	var robotsAllowed crawl.AllowFunc
+	if !crawlReq.IgnoreRobots {
+		cached := robots.NewCachedChecker(h.robotsChecker)
+		robotsAllowed = func(ctx context.Context, url string) bool {
+			// Fails open like the robots API: fetch errors allow the page
+			allowed, _ := cached.Check(ctx, url)
+			return allowed
+		}
+	}
+
+	job, err := h.manager.Submit(apiKey, crawl.Options{
+		SeedURL:     crawlReq.URL,
+		MaxDepth:    *crawlReq.MaxDepth,
+		MaxPages:    crawlReq.MaxPages,
+		Concurrency: crawlReq.Concurrency,
+		Page:        page,
+		Robots:      robotsAllowed,
+		// Discovered links get the same SSRF protection as the seed, resolved
+		// once per host
+		Allowed: crawl.PerHost(func(ctx context.Context, host string) bool {
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			return security.ValidateHost(ctx, host) == nil
+		}),
+	})
+	if err != nil {
+		if errors.Is(err, jobqueue.ErrShuttingDown) {
+			h.writeError(w, http.StatusServiceUnavailable, types.ErrPoolShuttingDown, "Service shutting down")
+			return
+		}
+		h.writeError(w, http.StatusServiceUnavailable, types.ErrCrawlQueueFull, "Crawl queue is full, try again later")
+		return
+	}
+
+	h.logger.Info("Ext crawl request",
+		zap.String("job_id", job.ID),
+		zap.String("api_key", maskAPIKey(apiKey)),
+		zap.String("url", crawlReq.URL),
+		zap.Int("max_depth", *crawlReq.MaxDepth),
+		zap.Int("max_pages", crawlReq.MaxPages),
+		zap.Bool("js_enabled", crawlReq.Options.JSEnabled),
+	)
+
+	h.writeJSON(w, http.StatusAccepted, &types.ExtCrawlResponse{Success: true, Data: job})
+}
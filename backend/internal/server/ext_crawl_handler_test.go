@@ -0,0 +1,122 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/crawl"
+	"github.com/user/jsbug/internal/robots"
+	"github.com/user/jsbug/internal/types"
+)
+
+func newTestCrawlHandler(t *testing.T) *ExtCrawlHandler {
+	t.Helper()
+	extHandler := newTestExtHandler()
+	manager := crawl.NewManager(crawl.ManagerConfig{}, zap.NewNop())
+	manager.Start(time.Hour)
+	t.Cleanup(manager.Shutdown)
+	return NewExtCrawlHandler(extHandler.renderHandler, manager, robots.NewChecker(zap.NewNop()), testAPIConfig(), zap.NewNop())
+}
+
+func doCrawlRequest(handler *ExtCrawlHandler, method, path, body string) (*httptest.ResponseRecorder, types.ExtCrawlResponse) {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("X-API-Key", "test-key-abc123")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var resp types.ExtCrawlResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	return w, resp
+}
+
+func TestExtCrawlHandler_SubmitAndGet(t *testing.T) {
+	handler := newTestCrawlHandler(t)
+
+	body := `{"url":"https://example.com/","ignore_robots":true,"options":{"js_enabled":false}}`
+	w, resp := doCrawlRequest(handler, http.MethodPost, "/api/ext/crawl", body)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusAccepted)
+	}
+	if !resp.Success || resp.Data == nil || resp.Data.ID == "" {
+		t.Fatalf("submit response = %+v, want job with ID", resp)
+	}
+	if resp.Data.MaxDepth != types.DefaultCrawlMaxDepth || resp.Data.MaxPages != types.DefaultCrawlMaxPages {
+		t.Errorf("limits = %d/%d, want defaults", resp.Data.MaxDepth, resp.Data.MaxPages)
+	}
+
+	id := resp.Data.ID
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		w, resp = doCrawlRequest(handler, http.MethodGet, "/api/ext/crawl/"+id, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET status = %d, want %d", w.Code, http.StatusOK)
+		}
+		if resp.Data.Status == types.JobStatusCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job status = %q, want completed", resp.Data.Status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if len(resp.Data.Pages) != 1 {
+		t.Fatalf("len(Pages) = %d, want 1", len(resp.Data.Pages))
+	}
+	page := resp.Data.Pages[0]
+	if page.Status != types.CrawlPageCrawled || page.StatusCode != 200 || page.WordCount == 0 {
+		t.Errorf("Pages[0] = %+v, want crawled page with word count", page)
+	}
+}
+
+func TestExtCrawlHandler_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode string
+	}{
+		{"bad_depth", `{"url":"https://example.com","max_depth":99}`, types.ErrInvalidCrawl},
+		{"bad_pages", `{"url":"https://example.com","max_pages":5000}`, types.ErrInvalidCrawl},
+		{"bad_url", `{"url":"ftp://example.com"}`, types.ErrInvalidURL},
+		{"bad_option", `{"url":"https://example.com","options":{"timeout":120}}`, types.ErrInvalidTimeout},
+		{"unknown_field", `{"url":"https://example.com","urls":[]}`, types.ErrInvalidRequestBody},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestCrawlHandler(t)
+			w, resp := doCrawlRequest(handler, http.MethodPost, "/api/ext/crawl", tt.body)
+			if w.Code != types.ErrorCodeToHTTPStatus(tt.wantCode) {
+				t.Errorf("status = %d, want %d", w.Code, types.ErrorCodeToHTTPStatus(tt.wantCode))
+			}
+			if resp.Error == nil || resp.Error.Code != tt.wantCode {
+				t.Errorf("error = %+v, want code %s", resp.Error, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestExtCrawlHandler_NotFoundAndMethods(t *testing.T) {
+	handler := newTestCrawlHandler(t)
+
+	w, resp := doCrawlRequest(handler, http.MethodGet, "/api/ext/crawl/unknown-id", "")
+	if w.Code != http.StatusNotFound || resp.Error == nil || resp.Error.Code != types.ErrCrawlNotFound {
+		t.Errorf("GET unknown = %d %+v, want 404 CRAWL_NOT_FOUND", w.Code, resp.Error)
+	}
+
+	w, _ = doCrawlRequest(handler, http.MethodDelete, "/api/ext/crawl/unknown-id", "")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("DELETE unknown status = %d, want 404", w.Code)
+	}
+
+	w, _ = doCrawlRequest(handler, http.MethodGet, "/api/ext/crawl", "")
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET list status = %d, want 405", w.Code)
+	}
+}
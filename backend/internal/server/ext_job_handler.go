@@ -0,0 +1,104 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/config"
+	"github.com/user/jsbug/internal/types"
+)
+
+// apiKeySet holds the configured external API keys
+type apiKeySet map[string]bool
+
+func newAPIKeySet(cfg *config.Config) apiKeySet {
+	keys := make(apiKeySet)
+	for _, k := range cfg.API.Keys {
+		keys[k] = true
+	}
+	return keys
+}
+
+func (s apiKeySet) valid(provided string) bool {
+	for key := range s {
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(key)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// extJobHandler authenticates async external API job requests and routes
+// POST {prefix}, GET {prefix}/{id} and DELETE {prefix}/{id}. J is the job
+// snapshot type returned in the response data.
+type extJobHandler[J any] struct {
+	renderHandler *RenderHandler
+	apiKeys       apiKeySet
+	logger        *zap.Logger
+
+	prefix          string // e.g. "/api/ext/batch"
+	feature         string // API key usage label, e.g. "batch"
+	notFoundCode    string
+	notFoundMessage string
+
+	submit func(w http.ResponseWriter, r *http.Request, apiKey string)
+	get    func(id, owner string) (*J, bool)
+	cancel func(id, owner string) (*J, bool)
+}
+
+func (h *extJobHandler[J]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	apiKey := r.Header.Get("X-API-Key")
+	if apiKey == "" {
+		h.writeError(w, http.StatusUnauthorized, types.ErrAPIKeyRequired, "API key required")
+		return
+	}
+	if !h.apiKeys.valid(apiKey) {
+		h.writeError(w, http.StatusForbidden, types.ErrAPIKeyInvalid, "Invalid API key")
+		return
+	}
+	h.renderHandler.recordAPIKeyRequest(apiKey, h.feature)
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, h.prefix), "/")
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		h.submit(w, r, apiKey)
+	case id != "" && r.Method == http.MethodGet:
+		h.writeJob(w, h.get, id, apiKey)
+	case id != "" && r.Method == http.MethodDelete:
+		h.writeJob(w, h.cancel, id, apiKey)
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, types.ErrMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// writeJob writes the job returned by lookup, or a not found error
+func (h *extJobHandler[J]) writeJob(w http.ResponseWriter, lookup func(id, owner string) (*J, bool), id, apiKey string) {
+	job, ok := lookup(id, apiKey)
+	if !ok {
+		h.writeError(w, http.StatusNotFound, h.notFoundCode, h.notFoundMessage)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, &types.ExtJobResponse[J]{Success: true, Data: job})
+}
+
+func (h *extJobHandler[J]) writeJSON(w http.ResponseWriter, statusCode int, resp *types.ExtJobResponse[J]) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error("Failed to write response", zap.Error(err))
+	}
+}
+
+func (h *extJobHandler[J]) writeError(w http.ResponseWriter, statusCode int, code, message string) {
+	h.renderHandler.recordError(code)
+	resp := &types.ExtJobResponse[J]{
+		Success: false,
+		Error:   &types.RenderError{Code: code, Message: message},
+	}
+	h.writeJSON(w, statusCode, resp)
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
@@ -10,7 +11,6 @@ import (
 
 	"go.uber.org/zap"
 
-	"github.com/PuerkitoBio/goquery"
 	"github.com/user/jsbug/internal/config"
 	"github.com/user/jsbug/internal/parser"
 	"github.com/user/jsbug/internal/types"
@@ -63,6 +63,7 @@ func (h *ExtRenderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, http.StatusForbidden, types.ErrAPIKeyInvalid, "Invalid API key")
 		return
 	}
+	h.renderHandler.recordAPIKeyRequest(apiKey, "render")
 
 	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
 
@@ -82,26 +83,46 @@ func (h *ExtRenderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, types.ErrorCodeToHTTPStatus(renderErr.Code), renderErr.Code, renderErr.Message)
 		return
 	}
-
-	var response *types.RenderResponse
-	if req.JSEnabled {
-		response = h.renderHandler.handleJSRender(r.Context(), req)
-	} else {
-		response = h.renderHandler.handleFetch(r.Context(), req)
+	if err := extReq.ValidateDiagnostics(); err != nil {
+		h.writeError(w, http.StatusBadRequest, types.ErrInvalidFilter, err.Error())
+		return
 	}
 
-	if !response.Success {
+	extData, renderErr := renderExt(r.Context(), h.renderHandler, req, &extReq)
+	if renderErr != nil {
 		extResp := &types.ExtRenderResponse{
 			Success: false,
-			Error:   response.Error,
+			Error:   renderErr,
 		}
-		statusCode := h.errorToStatus(response.Error)
+		statusCode := h.errorToStatus(renderErr)
 		h.writeJSON(w, statusCode, extResp)
 		h.logRequest(extReq, apiKey, time.Since(startTime).Seconds(), statusCode)
 		return
 	}
 
-	extData := buildExtResponse(response.Data, &extReq)
+	extResp := &types.ExtRenderResponse{
+		Success: true,
+		Data:    extData,
+	}
+
+	h.writeJSON(w, http.StatusOK, extResp)
+	h.logRequest(extReq, apiKey, time.Since(startTime).Seconds(), http.StatusOK)
+}
+
+// renderExt renders a validated request and builds the external API response data
+func renderExt(ctx context.Context, renderHandler *RenderHandler, req *types.RenderRequest, extReq *types.ExtRenderRequest) (*types.ExtRenderData, *types.RenderError) {
+	var response *types.RenderResponse
+	if req.UsesChrome() {
+		response = renderHandler.handleJSRender(ctx, req)
+	} else {
+		response = renderHandler.handleFetch(ctx, req)
+	}
+
+	if !response.Success {
+		return nil, response.Error
+	}
+
+	extData := buildExtResponse(response.Data, extReq)
 
 	if extReq.MaxContentLength > 0 {
 		truncateContent(extData, extReq.MaxContentLength)
@@ -112,20 +133,17 @@ func (h *ExtRenderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		extData.BodyTextTokensCount = &tokens
 	}
 
-	extResp := &types.ExtRenderResponse{
-		Success: true,
-		Data:    extData,
-	}
-
-	h.writeJSON(w, http.StatusOK, extResp)
-	h.logRequest(extReq, apiKey, time.Since(startTime).Seconds(), http.StatusOK)
+	return extData, nil
 }
 
 func buildExtResponse(data *types.RenderData, extReq *types.ExtRenderRequest) *types.ExtRenderData {
+	usesChrome := extReq.UsesChrome()
 	ext := &types.ExtRenderData{
 		StatusCode:      data.StatusCode,
 		FinalURL:        data.FinalURL,
 		RedirectURL:     data.RedirectURL,
+		RedirectChain:   data.RedirectChain,
+		RedirectFlags:   data.RedirectFlags,
 		CanonicalURL:    data.CanonicalURL,
 		PageSizeBytes:   data.PageSizeBytes,
 		RenderTime:      data.RenderTime,
@@ -139,6 +157,8 @@ func buildExtResponse(data *types.RenderData, extReq *types.ExtRenderRequest) *t
 		TextHtmlRatio:   data.TextHtmlRatio,
 		OpenGraph:       data.OpenGraph,
 		HrefLangs:       data.HrefLangs,
+		ResponseHeaders: data.ResponseHeaders,
+		Document:        data.Document,
 	}
 
 	if data.H1 != nil {
@@ -162,6 +182,12 @@ func buildExtResponse(data *types.RenderData, extReq *types.ExtRenderRequest) *t
 	if ext.HrefLangs == nil {
 		ext.HrefLangs = []types.HrefLang{}
 	}
+	if ext.ResponseHeaders == nil {
+		ext.ResponseHeaders = map[string]string{}
+	}
+	if ext.RedirectChain == nil {
+		ext.RedirectChain = []types.RedirectHop{}
+	}
 
 	if extReq.IncludeHTML {
 		html := data.HTML
@@ -197,7 +223,7 @@ func buildExtResponse(data *types.RenderData, extReq *types.ExtRenderRequest) *t
 		}
 	}
 	if extReq.IncludeSections {
-		doc, err := goquery.NewDocumentFromReader(strings.NewReader(data.HTML))
+		doc, err := parser.NewDocument(data.HTML, extReq.RenderMode == types.RenderModeChromeNoJS)
 		if err == nil {
 			ext.Sections = parser.ExtractSections(doc)
 		}
@@ -205,14 +231,102 @@ func buildExtResponse(data *types.RenderData, extReq *types.ExtRenderRequest) *t
 			ext.Sections = []types.Section{}
 		}
 	}
-	if extReq.IncludeScreenshot && extReq.JSEnabled && len(data.ScreenshotData) > 0 {
+	if extReq.IncludeScreenshot && usesChrome && len(data.ScreenshotData) > 0 {
 		encoded := base64.StdEncoding.EncodeToString(data.ScreenshotData)
 		ext.Screenshot = &encoded
+		ext.ScreenshotFormat = types.ScreenshotFormatPNG
+		if extReq.Screenshot != nil && extReq.Screenshot.Format != "" {
+			ext.ScreenshotFormat = extReq.Screenshot.Format
+		}
+	}
+	if extReq.IncludePDF && usesChrome && len(data.PDFData) > 0 {
+		encoded := base64.StdEncoding.EncodeToString(data.PDFData)
+		ext.PDF = &encoded
+		ext.PDFID = data.PDFID
+	}
+	if extReq.IncludeHAR && usesChrome && data.HARData != nil {
+		ext.HAR = data.HARData
+		ext.HARID = data.HARID
+	}
+
+	// Diagnostics are only collected by Chrome
+	if usesChrome {
+		if extReq.IncludeNetwork {
+			network := filterNetworkRequests(data.Requests, extReq.NetworkTypes, extReq.NetworkFailedOnly)
+			ext.Network = &network
+		}
+		if extReq.IncludeConsole {
+			console := filterConsoleMessages(data.Console, extReq.ConsoleMinLevel)
+			ext.Console = &console
+		}
+		if extReq.IncludeJSErrors {
+			jsErrors := data.JSErrors
+			if jsErrors == nil {
+				jsErrors = []types.JSError{}
+			}
+			ext.JSErrors = &jsErrors
+		}
+		if extReq.IncludeLifecycle {
+			lifecycle := data.Lifecycle
+			if lifecycle == nil {
+				lifecycle = []types.LifecycleEvent{}
+			}
+			ext.Lifecycle = &lifecycle
+		}
+		ext.Actions = data.Actions
+		ext.Scroll = data.Scroll
+		if extReq.IncludeWebVitals {
+			ext.WebVitals = data.WebVitals
+		}
+		ext.Throttling = data.Throttling
+		ext.Device = data.Device
+		ext.Dialogs = data.Dialogs
+		ext.Snapshots = data.Snapshots
 	}
 
 	return ext
 }
 
+// filterNetworkRequests keeps requests matching one of resourceTypes (all when
+// empty) and, when failedOnly is set, only failed, blocked or 4xx/5xx requests
+func filterNetworkRequests(requests []types.NetworkRequest, resourceTypes []string, failedOnly bool) []types.NetworkRequest {
+	result := []types.NetworkRequest{}
+	for _, req := range requests {
+		if len(resourceTypes) > 0 && !containsFold(resourceTypes, req.Type) {
+			continue
+		}
+		if failedOnly && !req.Failed && !req.Blocked && req.Status < 400 {
+			continue
+		}
+		result = append(result, req)
+	}
+	return result
+}
+
+// filterConsoleMessages keeps messages at or above minLevel (all when empty)
+func filterConsoleMessages(messages []types.ConsoleMessage, minLevel string) []types.ConsoleMessage {
+	result := []types.ConsoleMessage{}
+	minRank := 0
+	if minLevel != "" {
+		minRank = types.ConsoleLevelRank(minLevel)
+	}
+	for _, msg := range messages {
+		if types.ConsoleLevelRank(msg.Level) >= minRank {
+			result = append(result, msg)
+		}
+	}
+	return result
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
 func truncateContent(data *types.ExtRenderData, maxLen int) {
 	if data.HTML != nil {
 		*data.HTML = truncateAtWordBoundary(*data.HTML, maxLen)
@@ -273,6 +387,7 @@ func (h *ExtRenderHandler) writeJSON(w http.ResponseWriter, statusCode int, resp
 }
 
 func (h *ExtRenderHandler) writeError(w http.ResponseWriter, statusCode int, code, message string) {
+	h.renderHandler.recordError(code)
 	resp := &types.ExtRenderResponse{
 		Success: false,
 		Error:   &types.RenderError{Code: code, Message: message},
@@ -281,14 +396,12 @@ func (h *ExtRenderHandler) writeError(w http.ResponseWriter, statusCode int, cod
 }
 
 func (h *ExtRenderHandler) logRequest(req types.ExtRenderRequest, apiKey string, totalTime float64, status int) {
-	maskedKey := apiKey
-	if len(apiKey) > 4 {
-		maskedKey = "***" + apiKey[len(apiKey)-4:]
-	}
+	maskedKey := maskAPIKey(apiKey)
 	h.logger.Info("Ext render request",
 		zap.String("url", req.URL),
 		zap.String("api_key", maskedKey),
 		zap.Bool("js_enabled", req.JSEnabled),
+		zap.String("render_mode", req.RenderMode),
 		zap.Bool("include_html", req.IncludeHTML),
 		zap.Bool("include_text", req.IncludeText),
 		zap.Bool("include_markdown", req.IncludeMarkdown),
@@ -297,7 +410,21 @@ func (h *ExtRenderHandler) logRequest(req types.ExtRenderRequest, apiKey string,
 		zap.Bool("include_images", req.IncludeImages),
 		zap.Bool("include_structured_data", req.IncludeStructuredData),
 		zap.Bool("include_screenshot", req.IncludeScreenshot),
+		zap.Bool("include_network", req.IncludeNetwork),
+		zap.Bool("include_console", req.IncludeConsole),
+		zap.Bool("include_js_errors", req.IncludeJSErrors),
+		zap.Bool("include_lifecycle", req.IncludeLifecycle),
+		zap.Bool("include_web_vitals", req.IncludeWebVitals),
 		zap.Float64("total_time", totalTime),
 		zap.Int("status", status),
 	)
 }
+
+// maskAPIKey keeps only the last 4 characters of an API key for logs. Keys
+// of 8 characters or fewer are masked completely.
+func maskAPIKey(apiKey string) string {
+	if len(apiKey) > 8 {
+		return "***" + apiKey[len(apiKey)-4:]
+	}
+	return "***"
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"go.uber.org/zap"
@@ -12,6 +13,7 @@ import (
 	"github.com/user/jsbug/internal/config"
 	"github.com/user/jsbug/internal/fetcher"
 	"github.com/user/jsbug/internal/parser"
+	"github.com/user/jsbug/internal/types"
 )
 
 func testAPIConfig() *config.Config {
@@ -283,6 +285,350 @@ func TestExtRenderHandler_InvalidWaitEvent(t *testing.T) {
 	}
 }
 
+func TestExtRenderHandler_InvalidHeaders(t *testing.T) {
+	handler := newTestExtHandler()
+
+	body := `{"url":"https://example.com","headers":{"Host":"evil.example"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/ext/render", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "test-key-abc123")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	errObj, ok := resp["error"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected error object in response")
+	}
+	if errObj["code"] != "INVALID_HEADERS" {
+		t.Errorf("error.code = %v, want INVALID_HEADERS", errObj["code"])
+	}
+}
+
+func TestExtRenderHandler_InvalidActions(t *testing.T) {
+	handler := newTestExtHandler()
+
+	body := `{"url":"https://example.com","js_enabled":true,"actions":[{"type":"click","selector":"#more"},{"type":"hover"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/ext/render", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "test-key-abc123")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	errObj, ok := resp["error"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected error object in response")
+	}
+	if errObj["code"] != "INVALID_ACTIONS" {
+		t.Errorf("error.code = %v, want INVALID_ACTIONS", errObj["code"])
+	}
+	if msg, _ := errObj["message"].(string); !strings.HasPrefix(msg, "actions[1]:") {
+		t.Errorf("error.message = %q, want actions[1] prefix", msg)
+	}
+}
+
+func TestExtRenderHandler_InvalidScroll(t *testing.T) {
+	handler := newTestExtHandler()
+
+	body := `{"url":"https://example.com","js_enabled":true,"scroll_to_bottom":{"max_steps":500}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/ext/render", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "test-key-abc123")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	errObj, ok := resp["error"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected error object in response")
+	}
+	if errObj["code"] != "INVALID_SCROLL" {
+		t.Errorf("error.code = %v, want INVALID_SCROLL", errObj["code"])
+	}
+}
+
+func TestExtRenderHandler_InvalidScreenshot(t *testing.T) {
+	handler := newTestExtHandler()
+
+	body := `{"url":"https://example.com","js_enabled":true,"include_screenshot":true,"screenshot":{"format":"gif"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/ext/render", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "test-key-abc123")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	errObj, ok := resp["error"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected error object in response")
+	}
+	if errObj["code"] != "INVALID_SCREENSHOT" {
+		t.Errorf("error.code = %v, want INVALID_SCREENSHOT", errObj["code"])
+	}
+}
+
+func TestExtRenderHandler_InvalidPDF(t *testing.T) {
+	handler := newTestExtHandler()
+
+	body := `{"url":"https://example.com","js_enabled":true,"include_pdf":true,"pdf":{"paper":"b5"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/ext/render", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "test-key-abc123")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	errObj, ok := resp["error"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected error object in response")
+	}
+	if errObj["code"] != "INVALID_PDF" {
+		t.Errorf("error.code = %v, want INVALID_PDF", errObj["code"])
+	}
+}
+
+func TestExtRenderHandler_InvalidSnapshots(t *testing.T) {
+	handler := newTestExtHandler()
+
+	body := `{"url":"https://example.com","js_enabled":true,"include_snapshots":true,"snapshots":{"events":["domReady"]}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/ext/render", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "test-key-abc123")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	errObj, ok := resp["error"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected error object in response")
+	}
+	if errObj["code"] != "INVALID_SNAPSHOTS" {
+		t.Errorf("error.code = %v, want INVALID_SNAPSHOTS", errObj["code"])
+	}
+}
+
+func TestExtRenderHandler_InvalidThrottling(t *testing.T) {
+	handler := newTestExtHandler()
+
+	body := `{"url":"https://example.com","js_enabled":true,"network_profile":"5g"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/ext/render", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "test-key-abc123")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	errObj, ok := resp["error"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected error object in response")
+	}
+	if errObj["code"] != "INVALID_THROTTLING" {
+		t.Errorf("error.code = %v, want INVALID_THROTTLING", errObj["code"])
+	}
+}
+
+func TestExtRenderHandler_InvalidDevice(t *testing.T) {
+	handler := newTestExtHandler()
+
+	body := `{"url":"https://example.com","js_enabled":true,"device":"iphone-15","viewport_width":50}`
+	req := httptest.NewRequest(http.MethodPost, "/api/ext/render", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "test-key-abc123")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	errObj, ok := resp["error"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected error object in response")
+	}
+	if errObj["code"] != "INVALID_DEVICE" {
+		t.Errorf("error.code = %v, want INVALID_DEVICE", errObj["code"])
+	}
+}
+
+func TestExtRenderHandler_InvalidLocale(t *testing.T) {
+	handler := newTestExtHandler()
+
+	tests := []struct {
+		body string
+		code string
+	}{
+		{`{"url":"https://example.com","locale":"de_DE"}`, "INVALID_LOCALE"},
+		{`{"url":"https://example.com","js_enabled":true,"timezone":"Mars/Olympus"}`, "INVALID_LOCALE"},
+		{`{"url":"https://example.com","js_enabled":true,"geolocation":{"latitude":91,"longitude":0}}`, "INVALID_GEOLOCATION"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/api/ext/render", bytes.NewBufferString(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", "test-key-abc123")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", tt.body, w.Code, http.StatusBadRequest)
+		}
+
+		var resp map[string]interface{}
+		json.NewDecoder(w.Body).Decode(&resp)
+
+		errObj, ok := resp["error"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("%s: expected error object in response", tt.body)
+		}
+		if errObj["code"] != tt.code {
+			t.Errorf("%s: error.code = %v, want %s", tt.body, errObj["code"], tt.code)
+		}
+	}
+}
+
+func TestExtRenderHandler_InvalidBodyCapture(t *testing.T) {
+	handler := newTestExtHandler()
+
+	body := `{"url":"https://example.com","js_enabled":true,"capture_response_bodies":{"mime_types":["json"]}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/ext/render", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "test-key-abc123")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	errObj, ok := resp["error"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected error object in response")
+	}
+	if errObj["code"] != "INVALID_BODY_CAPTURE" {
+		t.Errorf("error.code = %v, want INVALID_BODY_CAPTURE", errObj["code"])
+	}
+}
+
+func TestExtRenderHandler_InvalidDialogsAndPermissions(t *testing.T) {
+	handler := newTestExtHandler()
+
+	tests := []struct {
+		body string
+		code string
+	}{
+		{`{"url":"https://example.com","js_enabled":true,"dialog_action":"ignore"}`, "INVALID_DIALOGS"},
+		{`{"url":"https://example.com","js_enabled":true,"permissions":["telepathy"]}`, "INVALID_PERMISSIONS"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/api/ext/render", bytes.NewBufferString(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", "test-key-abc123")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", tt.body, w.Code, http.StatusBadRequest)
+		}
+
+		var resp map[string]interface{}
+		json.NewDecoder(w.Body).Decode(&resp)
+
+		errObj, ok := resp["error"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("%s: expected error object in response", tt.body)
+		}
+		if errObj["code"] != tt.code {
+			t.Errorf("%s: error.code = %v, want %s", tt.body, errObj["code"], tt.code)
+		}
+	}
+}
+
+func TestExtRenderHandler_InvalidNetworkType(t *testing.T) {
+	handler := newTestExtHandler()
+
+	body := `{"url":"https://example.com","js_enabled":true,"include_network":true,"network_types":["video"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/ext/render", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "test-key-abc123")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	errObj, ok := resp["error"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected error object in response")
+	}
+	if errObj["code"] != "INVALID_FILTER" {
+		t.Errorf("error.code = %v, want INVALID_FILTER", errObj["code"])
+	}
+}
+
 func TestExtRenderHandler_MetadataOnlyResponse(t *testing.T) {
 	handler := newTestExtHandler()
 
@@ -469,3 +815,127 @@ func TestExtRenderHandler_ValidAPIKey(t *testing.T) {
 		t.Errorf("success = %v, want true", resp["success"])
 	}
 }
+
+func TestBuildExtResponse_Diagnostics(t *testing.T) {
+	data := &types.RenderData{
+		StatusCode: 200,
+		Requests: []types.NetworkRequest{
+			{ID: "1", URL: "https://example.com/app.js", Type: "Script", Status: 200},
+			{ID: "2", URL: "https://example.com/api", Type: "XHR", Status: 500},
+			{ID: "3", URL: "https://ads.example.net/ad.js", Type: "Script", Blocked: true},
+			{ID: "4", URL: "https://example.com/logo.png", Type: "Image", Status: 404},
+		},
+		Console: []types.ConsoleMessage{
+			{ID: "c1", Level: "log", Message: "hello"},
+			{ID: "c2", Level: "warning", Message: "deprecated"},
+			{ID: "c3", Level: "error", Message: "boom"},
+		},
+		JSErrors:  []types.JSError{{Message: "TypeError: x is undefined"}},
+		Lifecycle: []types.LifecycleEvent{{Event: "load", Time: 0.5}},
+	}
+
+	t.Run("filters applied", func(t *testing.T) {
+		ext := buildExtResponse(data, &types.ExtRenderRequest{
+			JSEnabled:         true,
+			IncludeNetwork:    true,
+			NetworkTypes:      []string{"script", "xhr"},
+			NetworkFailedOnly: true,
+			IncludeConsole:    true,
+			ConsoleMinLevel:   "warning",
+			IncludeJSErrors:   true,
+			IncludeLifecycle:  true,
+		})
+
+		if ext.Network == nil || len(*ext.Network) != 2 || (*ext.Network)[0].ID != "2" || (*ext.Network)[1].ID != "3" {
+			t.Errorf("Network = %+v, want requests 2 and 3", ext.Network)
+		}
+		if ext.Console == nil || len(*ext.Console) != 2 || (*ext.Console)[0].ID != "c2" {
+			t.Errorf("Console = %+v, want warning and error messages", ext.Console)
+		}
+		if ext.JSErrors == nil || len(*ext.JSErrors) != 1 {
+			t.Errorf("JSErrors = %+v, want 1 error", ext.JSErrors)
+		}
+		if ext.Lifecycle == nil || len(*ext.Lifecycle) != 1 {
+			t.Errorf("Lifecycle = %+v, want 1 event", ext.Lifecycle)
+		}
+	})
+
+	t.Run("requested but empty", func(t *testing.T) {
+		empty := &types.RenderData{StatusCode: 200}
+		ext := buildExtResponse(empty, &types.ExtRenderRequest{
+			JSEnabled:        true,
+			IncludeNetwork:   true,
+			IncludeConsole:   true,
+			IncludeJSErrors:  true,
+			IncludeLifecycle: true,
+		})
+
+		encoded, err := json.Marshal(ext)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(encoded, &fields); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		for _, key := range []string{"network", "console", "js_errors", "lifecycle"} {
+			if string(fields[key]) != "[]" {
+				t.Errorf("%s = %s, want []", key, fields[key])
+			}
+		}
+	})
+
+	t.Run("not requested", func(t *testing.T) {
+		ext := buildExtResponse(data, &types.ExtRenderRequest{JSEnabled: true})
+		if ext.Network != nil || ext.Console != nil || ext.JSErrors != nil || ext.Lifecycle != nil {
+			t.Error("diagnostics should be nil when include flags are not set")
+		}
+	})
+
+	t.Run("ignored in HTTP mode", func(t *testing.T) {
+		ext := buildExtResponse(data, &types.ExtRenderRequest{
+			IncludeNetwork:   true,
+			IncludeConsole:   true,
+			IncludeJSErrors:  true,
+			IncludeLifecycle: true,
+		})
+		if ext.Network != nil || ext.Console != nil || ext.JSErrors != nil || ext.Lifecycle != nil {
+			t.Error("diagnostics should be nil in HTTP mode")
+		}
+	})
+}
+
+func TestBuildExtResponse_WebVitals(t *testing.T) {
+	data := &types.RenderData{
+		StatusCode: 200,
+		WebVitals:  &types.WebVitals{LCPMs: 1200, LCPElement: "main > img", CLS: 0.05},
+	}
+
+	ext := buildExtResponse(data, &types.ExtRenderRequest{JSEnabled: true, IncludeWebVitals: true})
+	if ext.WebVitals == nil || ext.WebVitals.LCPElement != "main > img" {
+		t.Errorf("WebVitals = %+v, want the collected vitals", ext.WebVitals)
+	}
+
+	ext = buildExtResponse(data, &types.ExtRenderRequest{JSEnabled: true})
+	if ext.WebVitals != nil {
+		t.Error("WebVitals should be nil when include_web_vitals is not set")
+	}
+
+	ext = buildExtResponse(data, &types.ExtRenderRequest{IncludeWebVitals: true})
+	if ext.WebVitals != nil {
+		t.Error("WebVitals should be nil in HTTP mode")
+	}
+}
+
+func TestMaskAPIKey(t *testing.T) {
+	tests := map[string]string{
+		"test-key-abc123": "***c123",
+		"shortkey":        "***",
+		"abc":             "***",
+	}
+	for key, want := range tests {
+		if got := maskAPIKey(key); got != want {
+			t.Errorf("maskAPIKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/blobstore"
+	"github.com/user/jsbug/internal/chrome"
+	"github.com/user/jsbug/internal/metrics"
+)
+
+// MetricsHandler serves GET /metrics in the Prometheus text format
+type MetricsHandler struct {
+	metrics         *metrics.Metrics
+	pool            *chrome.ChromePool
+	sseManager      *SSEManager
+	screenshotStore *blobstore.Store
+	logger          *zap.Logger
+}
+
+// NewMetricsHandler creates a new MetricsHandler.
+// pool, sseManager and screenshotStore are optional - their gauges are omitted when nil.
+func NewMetricsHandler(m *metrics.Metrics, pool *chrome.ChromePool, sseManager *SSEManager, screenshotStore *blobstore.Store, logger *zap.Logger) *MetricsHandler {
+	return &MetricsHandler{
+		metrics:         m,
+		pool:            pool,
+		sseManager:      sseManager,
+		screenshotStore: screenshotStore,
+		logger:          logger,
+	}
+}
+
+// ServeHTTP handles GET /metrics requests
+func (h *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Render into a buffer so a write error never leaves a partial scrape
+	var buf bytes.Buffer
+	if err := h.write(&buf); err != nil {
+		h.logger.Error("Failed to render metrics", zap.Error(err))
+		http.Error(w, "Failed to render metrics", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", metrics.ContentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		h.logger.Error("Failed to write metrics response", zap.Error(err))
+	}
+}
+
+// write renders collected metrics followed by gauges read at scrape time
+func (h *MetricsHandler) write(buf *bytes.Buffer) error {
+	if h.metrics != nil {
+		if err := h.metrics.WritePrometheus(buf); err != nil {
+			return err
+		}
+	}
+
+	if h.pool != nil {
+		if err := writePoolMetrics(buf, h.pool.Stats()); err != nil {
+			return err
+		}
+	}
+
+	if h.sseManager != nil {
+		if err := metrics.WriteMetric(buf, "jsbug_sse_subscribers", metrics.TypeGauge,
+			"Active SSE progress subscriptions.",
+			metrics.Sample{Value: float64(h.sseManager.SubscriberCount())}); err != nil {
+			return err
+		}
+	}
+
+	if h.screenshotStore != nil {
+		count, size := h.screenshotStore.Stats()
+		if err := metrics.WriteMetric(buf, "jsbug_screenshot_store_entries", metrics.TypeGauge,
+			"Screenshots held in the in-memory store.",
+			metrics.Sample{Value: float64(count)}); err != nil {
+			return err
+		}
+		if err := metrics.WriteMetric(buf, "jsbug_screenshot_store_bytes", metrics.TypeGauge,
+			"Total size of screenshots held in the in-memory store.",
+			metrics.Sample{Value: float64(size)}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writePoolMetrics writes Chrome pool gauges and counters from stats
+func writePoolMetrics(buf *bytes.Buffer, stats chrome.PoolStats) error {
+	gauges := []struct {
+		name  string
+		help  string
+		value int
+	}{
+		{"jsbug_pool_instances", "Started Chrome instances.", stats.TotalInstances},
+		{"jsbug_pool_instances_available", "Idle Chrome instances ready to render.", stats.AvailableInstances},
+		{"jsbug_pool_instances_active", "Chrome instances currently rendering.", int(stats.ActiveInstances)},
+		{"jsbug_pool_instances_spawning", "Chrome instances currently starting on demand.", stats.SpawningInstances},
+		{"jsbug_pool_instances_min", "Configured minimum Chrome instances.", stats.MinInstances},
+		{"jsbug_pool_instances_max", "Configured maximum Chrome instances.", stats.MaxInstances},
+		{"jsbug_pool_queued_requests", "Renders waiting for a free Chrome instance.", stats.QueuedRequests},
+	}
+	for _, g := range gauges {
+		if err := metrics.WriteMetric(buf, g.name, metrics.TypeGauge, g.help,
+			metrics.Sample{Value: float64(g.value)}); err != nil {
+			return err
+		}
+	}
+
+	if err := metrics.WriteMetric(buf, "jsbug_pool_spawned_total", metrics.TypeCounter,
+		"Chrome instances spawned on demand.", metrics.Sample{Value: float64(stats.SpawnedTotal)}); err != nil {
+		return err
+	}
+	if err := metrics.WriteMetric(buf, "jsbug_pool_reaped_total", metrics.TypeCounter,
+		"Idle Chrome instances reaped.", metrics.Sample{Value: float64(stats.ReapedTotal)}); err != nil {
+		return err
+	}
+
+	return metrics.WriteMetric(buf, "jsbug_pool_restarts_total", metrics.TypeCounter,
+		"Chrome instance restarts by reason.",
+		metrics.Sample{Labels: []metrics.Label{{Name: "reason", Value: "dead"}}, Value: float64(stats.DeadRestarts)},
+		metrics.Sample{Labels: []metrics.Label{{Name: "reason", Value: "policy"}}, Value: float64(stats.PolicyRestarts)},
+	)
+}
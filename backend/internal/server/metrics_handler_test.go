@@ -0,0 +1,74 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/user/jsbug/internal/blobstore"
+	"github.com/user/jsbug/internal/metrics"
+)
+
+func TestMetricsHandler_MethodNotAllowed(t *testing.T) {
+	handler := NewMetricsHandler(metrics.New(), nil, nil, nil, zap.NewNop())
+
+	req := httptest.NewRequest(http.MethodPost, "/metrics", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestMetricsHandler_ExtRequests(t *testing.T) {
+	m := metrics.New()
+	extHandler := newTestExtHandler()
+	extHandler.renderHandler.SetMetrics(m)
+
+	sseManager := NewSSEManager(zap.NewNop())
+	sseManager.Subscribe("req-1")
+	store := blobstore.NewStore(0)
+	store.Store([]byte("png-bytes"))
+
+	// One successful HTTP-mode render and one rejected API key
+	for _, apiKey := range []string{"test-key-abc123", "wrong-key"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/ext/render",
+			bytes.NewBufferString(`{"url":"https://example.com","js_enabled":false}`))
+		req.Header.Set("X-API-Key", apiKey)
+		extHandler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	handler := NewMetricsHandler(m, nil, sseManager, store, zap.NewNop())
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != metrics.ContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, metrics.ContentType)
+	}
+
+	out := w.Body.String()
+	want := []string{
+		`jsbug_render_duration_seconds_count{mode="http",outcome="success"} 1`,
+		`jsbug_errors_total{code="API_KEY_INVALID"} 1`,
+		`jsbug_api_requests_total{api_key_index="0",endpoint="render"} 1`,
+		`jsbug_sse_subscribers 1`,
+		`jsbug_screenshot_store_entries 1`,
+		`jsbug_screenshot_store_bytes 9`,
+	}
+	for _, line := range want {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("metrics output missing %q\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, "c123") {
+		t.Error("metrics output must not contain any part of the API key")
+	}
+}
@@ -2,7 +2,9 @@ package server
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"net"
 	"net/http"
 	"net/url"
@@ -11,11 +13,13 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/user/jsbug/internal/blobstore"
 	"github.com/user/jsbug/internal/chrome"
+	"github.com/user/jsbug/internal/compare"
 	"github.com/user/jsbug/internal/config"
 	"github.com/user/jsbug/internal/fetcher"
+	"github.com/user/jsbug/internal/metrics"
 	"github.com/user/jsbug/internal/parser"
-	"github.com/user/jsbug/internal/screenshot"
 	"github.com/user/jsbug/internal/security"
 	"github.com/user/jsbug/internal/session"
 	"github.com/user/jsbug/internal/types"
@@ -35,13 +39,23 @@ type RenderHandler struct {
 	logger          *zap.Logger
 	sseManager      *SSEManager
 	tokenManager    *session.TokenManager
-	screenshotStore *screenshot.ScreenshotStore
+	screenshotStore *blobstore.Store
+	pdfStore        *blobstore.Store
+	harStore        *blobstore.Store
+	metrics         *metrics.Metrics
 }
 
+// Render modes used as metrics labels
+const (
+	renderModeJS         = "js"
+	renderModeHTTP       = "http"
+	renderModeChromeNoJS = "chrome_nojs"
+)
+
 // NewRenderHandler creates a new RenderHandler
 // tokenManager is optional - pass nil when captcha/session tokens are disabled
 // screenshotStore is optional - pass nil to disable screenshot storage
-func NewRenderHandler(pool *chrome.ChromePool, fetcher Fetcher, parser *parser.Parser, cfg *config.Config, logger *zap.Logger, tokenManager *session.TokenManager, screenshotStore *screenshot.ScreenshotStore) *RenderHandler {
+func NewRenderHandler(pool *chrome.ChromePool, fetcher Fetcher, parser *parser.Parser, cfg *config.Config, logger *zap.Logger, tokenManager *session.TokenManager, screenshotStore *blobstore.Store) *RenderHandler {
 	h := &RenderHandler{
 		pool:            pool,
 		fetcher:         fetcher,
@@ -64,6 +78,23 @@ func (h *RenderHandler) SetSSEManager(manager *SSEManager) {
 	h.sseManager = manager
 }
 
+// SetPDFStore sets the store that serves PDFs by ID. Without it PDFs are
+// only returned inline by the external API.
+func (h *RenderHandler) SetPDFStore(store *blobstore.Store) {
+	h.pdfStore = store
+}
+
+// SetHARStore sets the store that serves HAR documents by ID. Without it
+// HARs are only returned inline by the external API.
+func (h *RenderHandler) SetHARStore(store *blobstore.Store) {
+	h.harStore = store
+}
+
+// SetMetrics sets the metrics collector for render latency and error counts
+func (h *RenderHandler) SetMetrics(m *metrics.Metrics) {
+	h.metrics = m
+}
+
 // ServeHTTP handles POST /api/render requests
 func (h *RenderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
@@ -122,9 +153,9 @@ func (h *RenderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Process request based on JS enabled flag
+	// Process request based on the render mode
 	var response *types.RenderResponse
-	if req.JSEnabled {
+	if req.UsesChrome() {
 		response = h.handleJSRender(r.Context(), &req)
 	} else {
 		response = h.handleFetch(r.Context(), &req)
@@ -134,7 +165,7 @@ func (h *RenderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	logFields := []zap.Field{
 		zap.String("url", req.URL),
-		zap.Bool("js_enabled", req.JSEnabled),
+		zap.String("render_mode", req.Mode()),
 		zap.Bool("success", response.Success),
 		zap.Float64("total_time", time.Since(startTime).Seconds()),
 	}
@@ -146,6 +177,24 @@ func (h *RenderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 // validateRequest validates the render request
 func (h *RenderHandler) validateRequest(req *types.RenderRequest) *types.RenderError {
+	if renderErr := h.validateRequestSyntax(req); renderErr != nil {
+		return renderErr
+	}
+	return h.checkSSRF(req.URL)
+}
+
+// checkSSRF blocks URLs targeting private/internal IP ranges. It resolves
+// the host, so it may block on DNS.
+func (h *RenderHandler) checkSSRF(rawURL string) *types.RenderError {
+	if err := security.ValidateURL(rawURL); err != nil {
+		h.logger.Warn("SSRF blocked", zap.String("url", rawURL), zap.Error(err))
+		return &types.RenderError{Code: types.ErrSSRFBlocked, Message: "URL not allowed"}
+	}
+	return nil
+}
+
+// validateRequestSyntax checks the request without network lookups
+func (h *RenderHandler) validateRequestSyntax(req *types.RenderRequest) *types.RenderError {
 	// Validate URL
 	if req.URL == "" {
 		return &types.RenderError{Code: types.ErrInvalidURL, Message: "URL is required"}
@@ -164,10 +213,9 @@ func (h *RenderHandler) validateRequest(req *types.RenderRequest) *types.RenderE
 		return &types.RenderError{Code: types.ErrInvalidURL, Message: "URL must have a host"}
 	}
 
-	// SSRF protection: block private/internal IP ranges
-	if err := security.ValidateURL(req.URL); err != nil {
-		h.logger.Warn("SSRF blocked", zap.String("url", req.URL), zap.Error(err))
-		return &types.RenderError{Code: types.ErrSSRFBlocked, Message: "URL not allowed"}
+	// Validate render mode
+	if err := types.ValidateRenderMode(req.RenderMode); err != nil {
+		return &types.RenderError{Code: types.ErrInvalidRenderMode, Message: err.Error()}
 	}
 
 	// Validate timeout
@@ -186,12 +234,98 @@ func (h *RenderHandler) validateRequest(req *types.RenderRequest) *types.RenderE
 		}
 	}
 
+	// Validate custom headers and cookies
+	if err := req.ValidateHeaders(); err != nil {
+		return &types.RenderError{Code: types.ErrInvalidHeaders, Message: err.Error()}
+	}
+	if err := req.ValidateCookies(); err != nil {
+		return &types.RenderError{Code: types.ErrInvalidCookies, Message: err.Error()}
+	}
+
+	// Validate page actions
+	if err := types.ValidateActions(req.Actions); err != nil {
+		return &types.RenderError{Code: types.ErrInvalidActions, Message: err.Error()}
+	}
+
+	// Validate scroll options
+	if req.ScrollToBottom != nil {
+		if err := req.ScrollToBottom.Validate(); err != nil {
+			return &types.RenderError{Code: types.ErrInvalidScroll, Message: err.Error()}
+		}
+	}
+
+	// Validate screenshot options
+	if req.Screenshot != nil {
+		if err := req.Screenshot.Validate(); err != nil {
+			return &types.RenderError{Code: types.ErrInvalidScreenshot, Message: err.Error()}
+		}
+	}
+
+	// Validate PDF options
+	if req.PDF != nil {
+		if err := req.PDF.Validate(); err != nil {
+			return &types.RenderError{Code: types.ErrInvalidPDF, Message: err.Error()}
+		}
+	}
+
+	// Validate DOM snapshot events
+	if req.Snapshots != nil {
+		if err := req.Snapshots.Validate(); err != nil {
+			return &types.RenderError{Code: types.ErrInvalidSnapshots, Message: err.Error()}
+		}
+	}
+
+	// Validate dialog handling and the permission allowlist
+	if err := types.ValidateDialogOptions(req.DialogAction, req.DialogPromptText); err != nil {
+		return &types.RenderError{Code: types.ErrInvalidDialogs, Message: err.Error()}
+	}
+	if err := types.ValidatePermissions(req.Permissions); err != nil {
+		return &types.RenderError{Code: types.ErrInvalidPermissions, Message: err.Error()}
+	}
+
+	// Validate response body capture
+	if req.ResponseBodies != nil {
+		if err := req.ResponseBodies.Validate(); err != nil {
+			return &types.RenderError{Code: types.ErrInvalidBodyCapture, Message: err.Error()}
+		}
+	}
+
+	// Validate network profile and CPU throttling
+	if _, err := req.Throttling(); err != nil {
+		return &types.RenderError{Code: types.ErrInvalidThrottling, Message: err.Error()}
+	}
+
+	// Validate device profile and viewport overrides
+	if _, err := req.DeviceEmulation(false); err != nil {
+		return &types.RenderError{Code: types.ErrInvalidDevice, Message: err.Error()}
+	}
+
+	// Validate locale emulation
+	if err := types.ValidateLocale(req.Locale); err != nil {
+		return &types.RenderError{Code: types.ErrInvalidLocale, Message: err.Error()}
+	}
+	if err := types.ValidateTimezone(req.Timezone); err != nil {
+		return &types.RenderError{Code: types.ErrInvalidLocale, Message: err.Error()}
+	}
+	if req.Geolocation != nil {
+		if err := req.Geolocation.Validate(); err != nil {
+			return &types.RenderError{Code: types.ErrInvalidGeolocation, Message: err.Error()}
+		}
+	}
+
 	return nil
 }
 
-// handleJSRender processes a request with JavaScript rendering
-func (h *RenderHandler) handleJSRender(ctx context.Context, req *types.RenderRequest) *types.RenderResponse {
+// handleJSRender processes a request in Chrome, with JavaScript unless the
+// render mode is chrome_nojs
+func (h *RenderHandler) handleJSRender(ctx context.Context, req *types.RenderRequest) (response *types.RenderResponse) {
 	requestID := req.RequestID
+	scriptsDisabled := req.Mode() == types.RenderModeChromeNoJS
+	metricsMode := renderModeJS
+	if scriptsDisabled {
+		metricsMode = renderModeChromeNoJS
+	}
+	defer h.recordRender(metricsMode, time.Now(), &response)
 
 	// Check if pool is available
 	if h.pool == nil {
@@ -205,10 +339,14 @@ func (h *RenderHandler) handleJSRender(ctx context.Context, req *types.RenderReq
 		}
 	}
 
-	// Acquire instance from pool
-	instance, err := h.pool.Acquire()
+	// Acquire instance from pool, waiting in the queue if all instances are busy
+	instance, err := h.pool.AcquireWithPosition(ctx, func(position int) {
+		h.publishQueued(requestID, position)
+	})
 	if err != nil {
-		if err == chrome.ErrNoInstanceAvailable {
+		// Queue full, queue wait expired, or the request ended while queued
+		if errors.Is(err, chrome.ErrNoInstanceAvailable) ||
+			errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
 			h.publishError(requestID, types.ErrPoolExhausted, "Service unavailable, try again")
 			return &types.RenderResponse{
 				Success: false,
@@ -218,7 +356,7 @@ func (h *RenderHandler) handleJSRender(ctx context.Context, req *types.RenderReq
 				},
 			}
 		}
-		if err == chrome.ErrPoolShuttingDown {
+		if errors.Is(err, chrome.ErrPoolShuttingDown) {
 			h.publishError(requestID, types.ErrPoolShuttingDown, "Service shutting down")
 			return &types.RenderResponse{
 				Success: false,
@@ -250,16 +388,38 @@ func (h *RenderHandler) handleJSRender(ctx context.Context, req *types.RenderReq
 	// Create blocklist
 	blocklist := chrome.NewBlocklist(req.BlockAnalytics, req.BlockAds, req.BlockSocial, req.BlockedTypes)
 
-	// Build render options
-	userAgent := types.ResolveUserAgent(req.UserAgent)
+	// Build render options (throttling and device were validated with the request)
+	userAgent := req.ResolvedUserAgent()
+	throttling, _ := req.Throttling()
+	device, _ := req.DeviceEmulation(isMobileUserAgent(userAgent))
 	opts := chrome.RenderOptions{
 		URL:               req.URL,
 		UserAgent:         userAgent,
 		Timeout:           time.Duration(req.Timeout) * time.Second,
 		WaitEvent:         req.WaitEvent,
 		Blocklist:         blocklist,
-		IsMobile:          isMobileUserAgent(userAgent),
+		IsMobile:          device.Mobile,
+		Device:            device,
+		Locale:            req.Locale,
+		Timezone:          req.Timezone,
+		Geolocation:       req.Geolocation,
 		CaptureScreenshot: req.CaptureScreenshot,
+		FollowRedirects:   req.ShouldFollowRedirects(),
+		Headers:           req.Headers,
+		Cookies:           req.Cookies,
+		Actions:           req.Actions,
+		ScrollToBottom:    req.ScrollToBottom,
+		Screenshot:        req.Screenshot,
+		PDF:               req.PDF,
+		HAR:               req.HAR,
+		Snapshots:         req.Snapshots,
+		ResponseBodies:    req.ResponseBodies,
+		DialogAction:      req.DialogAction,
+		DialogPromptText:  req.DialogPromptText,
+		Permissions:       req.Permissions,
+		WebVitals:         req.WebVitals,
+		Throttling:        throttling,
+		ScriptsDisabled:   scriptsDisabled,
 	}
 
 	// Publish navigating event
@@ -281,20 +441,30 @@ func (h *RenderHandler) handleJSRender(ctx context.Context, req *types.RenderReq
 	// Publish parsing event
 	h.publishParsing(requestID)
 
-	// Parse HTML content (JS mode doesn't capture HTTP headers)
+	// Parse HTML content with main document headers
 	parseResult, _ := h.parser.ParseWithOptions(result.HTML, parser.ParseOptions{
-		PageURL: result.FinalURL,
+		PageURL:           result.FinalURL,
+		XRobotsTag:        result.GetXRobotsTag(),
+		LinkHeader:        result.GetLinkHeader(),
+		ScriptingDisabled: scriptsDisabled,
 	})
 
 	// Publish complete event
 	h.publishComplete(requestID, result.RenderTime)
 
-	return h.buildJSResponse(result, parseResult)
+	response = h.buildJSResponse(result, parseResult, req.Headers)
+	response.Data.Throttling = throttling
+	response.Data.Device = device
+	if req.Snapshots != nil {
+		response.Data.Snapshots = h.buildSnapshots(result, scriptsDisabled)
+	}
+	return response
 }
 
 // handleFetch processes a request without JavaScript rendering
-func (h *RenderHandler) handleFetch(ctx context.Context, req *types.RenderRequest) *types.RenderResponse {
+func (h *RenderHandler) handleFetch(ctx context.Context, req *types.RenderRequest) (response *types.RenderResponse) {
 	requestID := req.RequestID
+	defer h.recordRender(renderModeHTTP, time.Now(), &response)
 
 	if h.fetcher == nil {
 		h.publishError(requestID, types.ErrFetchFailed, "HTTP fetcher is not available")
@@ -313,9 +483,12 @@ func (h *RenderHandler) handleFetch(ctx context.Context, req *types.RenderReques
 	// Build fetch options
 	opts := fetcher.FetchOptions{
 		URL:             req.URL,
-		UserAgent:       types.ResolveUserAgent(req.UserAgent),
+		UserAgent:       req.ResolvedUserAgent(),
+		AcceptLanguage:  types.AcceptLanguage(req.Locale),
 		Timeout:         time.Duration(req.Timeout) * time.Second,
 		FollowRedirects: req.ShouldFollowRedirects(),
+		Headers:         req.Headers,
+		Cookies:         toHTTPCookies(req.Cookies),
 	}
 
 	// Publish navigating event
@@ -348,18 +521,31 @@ func (h *RenderHandler) handleFetch(ctx context.Context, req *types.RenderReques
 }
 
 // buildJSResponse builds response from JS render result
-func (h *RenderHandler) buildJSResponse(result *chrome.RenderResult, parseResult *parser.ParseResult) *types.RenderResponse {
+func (h *RenderHandler) buildJSResponse(result *chrome.RenderResult, parseResult *parser.ParseResult, customHeaders map[string]string) *types.RenderResponse {
 	data := &types.RenderData{
-		StatusCode:    result.StatusCode,
-		FinalURL:      result.FinalURL,
-		RedirectURL:   result.RedirectURL,
-		PageSizeBytes: result.PageSizeBytes,
-		RenderTime:    result.RenderTime,
-		HTML:          result.HTML,
-		Requests:      result.Network,
-		Console:       result.Console,
-		JSErrors:      result.JSErrors,
-		Lifecycle:     result.Lifecycle,
+		StatusCode:      result.StatusCode,
+		FinalURL:        result.FinalURL,
+		RedirectURL:     result.RedirectURL,
+		PageSizeBytes:   result.PageSizeBytes,
+		RenderTime:      result.RenderTime,
+		HTML:            result.HTML,
+		Requests:        result.Network,
+		Document:        result.Document,
+		Console:         result.Console,
+		JSErrors:        result.JSErrors,
+		Lifecycle:       result.Lifecycle,
+		Actions:         result.Actions,
+		WebVitals:       result.WebVitals,
+		Dialogs:         result.Dialogs,
+		RedirectChain:   result.RedirectChain,
+		XRobotsTag:      result.GetXRobotsTag(),
+		ResponseHeaders: result.Headers,
+	}
+	data.RedirectFlags = types.AnnotateRedirectChain(data.RedirectChain)
+
+	// Check for canonical in Link header
+	if canonical := fetcher.CanonicalFromLinkHeader(result.GetLinkHeader()); canonical != "" {
+		data.CanonicalURL = canonical
 	}
 
 	// Store screenshot and set ID if available
@@ -370,11 +556,34 @@ func (h *RenderHandler) buildJSResponse(result *chrome.RenderResult, parseResult
 	// Store raw screenshot data for ext API access
 	data.ScreenshotData = result.Screenshot
 
+	// Store PDF and set ID if available
+	if h.pdfStore != nil && len(result.PDF) > 0 {
+		data.PDFID = h.pdfStore.Store(result.PDF)
+	}
+	data.PDFData = result.PDF
+
+	// Store HAR and set ID if available. The stored copy is served without
+	// authentication, so credential and custom headers are redacted.
+	if result.HAR != nil {
+		data.HARData = result.HAR
+		if h.harStore != nil {
+			if encoded, err := json.Marshal(result.HAR.Redacted(customHeaders)); err == nil {
+				data.HARID = h.harStore.Store(encoded)
+			}
+		}
+	}
+
 	// Add parsed content
 	if parseResult != nil {
 		h.applyParseResult(data, parseResult)
 	}
 
+	// Flag images and links that only appeared after scrolling
+	if result.Scroll != nil {
+		result.Scroll.MarkAfterScroll(result.FinalURL, data.Images, data.Links)
+		data.Scroll = result.Scroll
+	}
+
 	// Enrich images with sizes from network requests (JS mode only)
 	enrichImagesWithSizes(data.Images, data.Requests)
 
@@ -384,17 +593,46 @@ func (h *RenderHandler) buildJSResponse(result *chrome.RenderResult, parseResult
 	}
 }
 
+// buildSnapshots parses the HTML of each lifecycle milestone and builds the
+// timeline of when the key SEO elements appeared
+func (h *RenderHandler) buildSnapshots(result *chrome.RenderResult, scriptsDisabled bool) *types.Snapshots {
+	milestones := make([]types.DOMSnapshot, 0, len(result.Snapshots))
+	for _, s := range result.Snapshots {
+		milestone := types.DOMSnapshot{Event: s.Event, Time: s.Time, H1: []string{}}
+		parseResult, err := h.parser.ParseWithOptions(s.HTML, parser.ParseOptions{
+			PageURL:           result.FinalURL,
+			ScriptingDisabled: scriptsDisabled,
+		})
+		if err == nil {
+			milestone.Title = parseResult.Title
+			milestone.H1 = parseResult.H1
+			milestone.WordCount = parseResult.WordCount
+			milestone.LinkCount = len(parseResult.Links)
+			milestone.CanonicalURL = parseResult.CanonicalURL
+		}
+		milestones = append(milestones, milestone)
+	}
+	return &types.Snapshots{
+		Milestones: milestones,
+		Timeline:   compare.SnapshotTimeline(milestones),
+	}
+}
+
 // buildFetchResponse builds response from HTTP fetch result
 func (h *RenderHandler) buildFetchResponse(result *fetcher.FetchResult, parseResult *parser.ParseResult) *types.RenderResponse {
 	data := &types.RenderData{
-		StatusCode:    result.StatusCode,
-		FinalURL:      result.FinalURL,
-		RedirectURL:   result.RedirectURL,
-		PageSizeBytes: result.PageSizeBytes,
-		RenderTime:    result.FetchTime,
-		HTML:          result.HTML,
-		XRobotsTag:    result.GetXRobotsTag(),
-	}
+		StatusCode:      result.StatusCode,
+		FinalURL:        result.FinalURL,
+		RedirectURL:     result.RedirectURL,
+		PageSizeBytes:   result.PageSizeBytes,
+		RenderTime:      result.FetchTime,
+		HTML:            result.HTML,
+		XRobotsTag:      result.GetXRobotsTag(),
+		RedirectChain:   result.RedirectChain,
+		ResponseHeaders: result.HeaderMap(),
+		Document:        result.DocumentRequest(),
+	}
+	data.RedirectFlags = types.AnnotateRedirectChain(data.RedirectChain)
 
 	// Check for canonical in Link header
 	if canonical := result.GetCanonicalFromHeader(); canonical != "" {
@@ -443,6 +681,17 @@ func (h *RenderHandler) applyParseResult(data *types.RenderData, parseResult *pa
 
 // handleRenderError converts render errors to response
 func (h *RenderHandler) handleRenderError(err error) *types.RenderResponse {
+	if errors.Is(err, chrome.ErrSSRFBlocked) {
+		h.logger.Warn("SSRF blocked during render", zap.Error(err))
+		return &types.RenderResponse{
+			Success: false,
+			Error: &types.RenderError{
+				Code:    types.ErrSSRFBlocked,
+				Message: "URL not allowed",
+			},
+		}
+	}
+
 	errMsg := err.Error()
 
 	if strings.Contains(errMsg, "context deadline exceeded") {
@@ -539,6 +788,8 @@ func (h *RenderHandler) writeJSON(w http.ResponseWriter, response *types.RenderR
 
 // writeError writes an error response
 func (h *RenderHandler) writeError(w http.ResponseWriter, statusCode int, code, message string) {
+	h.recordError(code)
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 
@@ -562,6 +813,12 @@ func (h *RenderHandler) publishStarted(requestID, url string) {
 	}
 }
 
+func (h *RenderHandler) publishQueued(requestID string, position int) {
+	if h.sseManager != nil && requestID != "" {
+		h.sseManager.PublishQueued(requestID, position)
+	}
+}
+
 func (h *RenderHandler) publishNavigating(requestID, url string) {
 	if h.sseManager != nil && requestID != "" {
 		h.sseManager.PublishNavigating(requestID, url)
@@ -592,6 +849,40 @@ func (h *RenderHandler) publishError(requestID, code, message string) {
 	}
 }
 
+// Metrics helpers - only record if metrics are set
+func (h *RenderHandler) recordRender(mode string, startTime time.Time, response **types.RenderResponse) {
+	if h.metrics == nil {
+		return
+	}
+
+	outcome := metrics.OutcomeSuccess
+	if resp := *response; resp == nil || !resp.Success {
+		outcome = metrics.OutcomeError
+		if resp != nil && resp.Error != nil {
+			h.metrics.IncError(resp.Error.Code)
+		}
+	}
+	h.metrics.ObserveRender(mode, outcome, time.Since(startTime).Seconds())
+}
+
+func (h *RenderHandler) recordError(code string) {
+	if h.metrics != nil {
+		h.metrics.IncError(code)
+	}
+}
+
+func (h *RenderHandler) recordAPIKeyRequest(apiKey, endpoint string) {
+	if h.metrics == nil {
+		return
+	}
+	for i, key := range h.config.API.Keys {
+		if subtle.ConstantTimeCompare([]byte(apiKey), []byte(key)) == 1 {
+			h.metrics.IncAPIKeyRequest(i, endpoint)
+			return
+		}
+	}
+}
+
 // isMobileUserAgent checks if the User-Agent indicates a mobile device
 func isMobileUserAgent(ua string) bool {
 	ua = strings.ToLower(ua)
@@ -604,6 +895,27 @@ func isMobileUserAgent(ua string) bool {
 	return false
 }
 
+// toHTTPCookies converts request cookies to net/http cookies for the fetcher
+func toHTTPCookies(cookies []types.Cookie) []*http.Cookie {
+	if len(cookies) == 0 {
+		return nil
+	}
+	result := make([]*http.Cookie, 0, len(cookies))
+	for _, c := range cookies {
+		path := c.Path
+		if path == "" {
+			path = "/"
+		}
+		result = append(result, &http.Cookie{
+			Name:   c.Name,
+			Value:  c.Value,
+			Domain: c.Domain,
+			Path:   path,
+		})
+	}
+	return result
+}
+
 // getClientIP extracts the client IP address from the request
 // Checks common proxy headers before falling back to RemoteAddr
 func getClientIP(r *http.Request) string {
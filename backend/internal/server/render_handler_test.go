@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -233,6 +234,38 @@ func TestRenderHandler_JSRender_PoolUnavailable(t *testing.T) {
 	}
 }
 
+func TestRenderHandler_ChromeNoJS_UsesChrome(t *testing.T) {
+	logger := zap.NewNop()
+	cfg := testConfig()
+	p := parser.NewParser()
+
+	// Handler with nil pool: a chrome_nojs render must not fall back to the fetcher
+	handler := NewRenderHandler(nil, nil, p, cfg, logger, nil, nil)
+
+	body := map[string]interface{}{
+		"url":         "https://example.com",
+		"js_enabled":  false,
+		"render_mode": "chrome_nojs",
+	}
+	jsonBody, _ := json.Marshal(body)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/render", bytes.NewBuffer(jsonBody))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	var response types.RenderResponse
+	json.NewDecoder(w.Body).Decode(&response)
+
+	if response.Error == nil || response.Error.Code != types.ErrChromeUnavailable {
+		t.Errorf("expected error code %s, got %v", types.ErrChromeUnavailable, response.Error)
+	}
+}
+
 func TestRenderHandler_JSRender_PoolExhausted(t *testing.T) {
 	logger := zap.NewNop()
 	cfg := testConfig()
@@ -401,6 +434,77 @@ func TestRenderHandler_Fetch_WithHeaders(t *testing.T) {
 	if response.Data.CanonicalURL != "https://example.com/canonical" {
 		t.Errorf("CanonicalURL = %q, want %q", response.Data.CanonicalURL, "https://example.com/canonical")
 	}
+	if response.Data.ResponseHeaders["X-Robots-Tag"] != "noindex" {
+		t.Errorf("ResponseHeaders[X-Robots-Tag] = %q, want %q", response.Data.ResponseHeaders["X-Robots-Tag"], "noindex")
+	}
+}
+
+func TestRenderHandler_BuildJSResponse_Headers(t *testing.T) {
+	logger := zap.NewNop()
+	handler := NewRenderHandler(nil, nil, parser.NewParser(), testConfig(), logger, nil, nil)
+
+	result := &chrome.RenderResult{
+		HTML:       "<html><head><title>Test</title></head></html>",
+		FinalURL:   "https://example.com/",
+		StatusCode: 200,
+		Headers: map[string]string{
+			"X-Robots-Tag": "noindex, nofollow",
+			"Link":         `<https://example.com/canonical>; rel="canonical"`,
+		},
+	}
+
+	resp := handler.buildJSResponse(result, nil, nil)
+
+	if resp.Data.XRobotsTag != "noindex, nofollow" {
+		t.Errorf("XRobotsTag = %q, want %q", resp.Data.XRobotsTag, "noindex, nofollow")
+	}
+	if resp.Data.CanonicalURL != "https://example.com/canonical" {
+		t.Errorf("CanonicalURL = %q, want %q", resp.Data.CanonicalURL, "https://example.com/canonical")
+	}
+	if resp.Data.ResponseHeaders["Link"] == "" {
+		t.Error("ResponseHeaders[Link] is empty, want main document Link header")
+	}
+}
+
+func TestRenderHandler_BuildSnapshots(t *testing.T) {
+	logger := zap.NewNop()
+	handler := NewRenderHandler(nil, nil, parser.NewParser(), testConfig(), logger, nil, nil)
+
+	result := &chrome.RenderResult{
+		FinalURL: "https://example.com/",
+		Snapshots: []chrome.DOMSnapshot{
+			{Event: "DOMContentLoaded", Time: 0.1, HTML: `<html><head><title>Loading</title></head><body><div id="app"></div></body></html>`},
+			{Event: types.SnapshotFinal, Time: 0.9, HTML: `<html><head><title>Shop</title><link rel="canonical" href="https://example.com/shop"></head>` +
+				`<body><h1>Shoes</h1><p>Red running shoes</p><a href="/cart">Cart</a></body></html>`},
+		},
+	}
+
+	snapshots := handler.buildSnapshots(result, false)
+
+	if len(snapshots.Milestones) != 2 {
+		t.Fatalf("len(Milestones) = %d, want 2", len(snapshots.Milestones))
+	}
+	first, final := snapshots.Milestones[0], snapshots.Milestones[1]
+	if first.Title != "Loading" || len(first.H1) != 0 || first.LinkCount != 0 {
+		t.Errorf("Milestones[0] = %+v", first)
+	}
+	if final.Title != "Shop" || len(final.H1) != 1 || final.H1[0] != "Shoes" ||
+		final.LinkCount != 1 || final.CanonicalURL != "https://example.com/shop" || final.WordCount == 0 {
+		t.Errorf("Milestones[1] = %+v", final)
+	}
+
+	appeared := map[string]string{}
+	for _, c := range snapshots.Timeline {
+		if c.Change == types.SnapshotChangeAppeared {
+			appeared[c.Element] = c.Event
+		}
+	}
+	if appeared[types.SnapshotElementH1] != types.SnapshotFinal {
+		t.Errorf("h1 appeared at %q, want final", appeared[types.SnapshotElementH1])
+	}
+	if appeared[types.SnapshotElementTitle] != "DOMContentLoaded" {
+		t.Errorf("title appeared at %q, want DOMContentLoaded", appeared[types.SnapshotElementTitle])
+	}
 }
 
 func TestRenderHandler_Fetch_Timeout(t *testing.T) {
@@ -471,6 +575,20 @@ func TestRenderHandler_Fetch_Error(t *testing.T) {
 	}
 }
 
+func TestRenderHandler_HandleRenderError_SSRFBlocked(t *testing.T) {
+	handler := NewRenderHandler(nil, nil, parser.NewParser(), testConfig(), zap.NewNop(), nil, nil)
+
+	err := fmt.Errorf("%w: %s", chrome.ErrSSRFBlocked, "https://example.com")
+	response := handler.handleRenderError(err)
+
+	if response.Success {
+		t.Error("expected Success = false")
+	}
+	if response.Error == nil || response.Error.Code != types.ErrSSRFBlocked {
+		t.Errorf("expected error code %s, got %+v", types.ErrSSRFBlocked, response.Error)
+	}
+}
+
 func TestRenderHandler_ValidateRequest(t *testing.T) {
 	logger := zap.NewNop()
 	cfg := testConfig()
@@ -546,6 +664,25 @@ func TestRenderHandler_ValidateRequest(t *testing.T) {
 			expectError: true,
 			errorCode:   types.ErrInvalidWaitEvent,
 		},
+		{
+			name: "chrome_nojs render mode",
+			req: &types.RenderRequest{
+				URL:        "https://example.com",
+				RenderMode: types.RenderModeChromeNoJS,
+				Timeout:    15,
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid render mode",
+			req: &types.RenderRequest{
+				URL:        "https://example.com",
+				RenderMode: "nojs",
+				Timeout:    15,
+			},
+			expectError: true,
+			errorCode:   types.ErrInvalidRenderMode,
+		},
 	}
 
 	for _, tt := range tests {
@@ -89,10 +89,29 @@ func (s *Server) SetExtCompareHandler(handler *ExtCompareHandler) {
 	s.mux.Handle("/api/ext/compare", handler)
 }
 
-// SetScreenshotHandler sets the screenshot handler for serving screenshots
-func (s *Server) SetScreenshotHandler(handler *ScreenshotHandler) {
-	// Use a prefix pattern to match /api/screenshot/{id}
-	s.mux.HandleFunc("/api/screenshot/", handler.HandleGet)
+// SetExtBatchHandler sets the external API batch handler
+func (s *Server) SetExtBatchHandler(handler *ExtBatchHandler) {
+	// Use a prefix pattern to match /api/ext/batch/{id}
+	s.mux.Handle("/api/ext/batch", handler)
+	s.mux.Handle("/api/ext/batch/", handler)
+}
+
+// SetExtCrawlHandler sets the external API crawl handler
+func (s *Server) SetExtCrawlHandler(handler *ExtCrawlHandler) {
+	// Use a prefix pattern to match /api/ext/crawl/{id}
+	s.mux.Handle("/api/ext/crawl", handler)
+	s.mux.Handle("/api/ext/crawl/", handler)
+}
+
+// SetBlobHandler serves a blob handler (screenshots, PDFs, HARs) at its
+// prefix, matching {prefix}{id}
+func (s *Server) SetBlobHandler(handler *BlobHandler) {
+	s.mux.HandleFunc(handler.options.Prefix, handler.HandleGet)
+}
+
+// SetMetricsHandler sets the Prometheus metrics handler
+func (s *Server) SetMetricsHandler(handler *MetricsHandler) {
+	s.mux.Handle("/metrics", handler)
 }
 
 // SSEManager returns the server's SSE manager
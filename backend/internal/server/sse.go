@@ -11,6 +11,7 @@ import (
 
 // SSE event types
 const (
+	SSEEventQueued     = "queued"
 	SSEEventStarted    = "started"
 	SSEEventNavigating = "navigating"
 	SSEEventWaiting    = "waiting"
@@ -99,6 +100,13 @@ func (m *SSEManager) Publish(requestID string, event SSEEvent) {
 	}
 }
 
+// SubscriberCount returns the number of active subscriptions
+func (m *SSEManager) SubscriberCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.channels)
+}
+
 // HasSubscriber checks if there's an active subscriber for the request ID
 func (m *SSEManager) HasSubscriber(requestID string) bool {
 	m.mu.RLock()
@@ -205,6 +213,16 @@ func (m *SSEManager) PublishStarted(requestID, url string) {
 	})
 }
 
+// PublishQueued publishes a queued event with the 1-based position in the render queue
+func (m *SSEManager) PublishQueued(requestID string, position int) {
+	m.Publish(requestID, SSEEvent{
+		Type: SSEEventQueued,
+		Data: map[string]interface{}{
+			"position": position,
+		},
+	})
+}
+
 // PublishNavigating publishes a navigating event
 func (m *SSEManager) PublishNavigating(requestID, url string) {
 	m.Publish(requestID, SSEEvent{
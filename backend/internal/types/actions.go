@@ -0,0 +1,132 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Page action types
+const (
+	ActionClick           = "click"
+	ActionType            = "type"
+	ActionScroll          = "scroll"
+	ActionWaitForSelector = "wait_for_selector"
+	ActionWaitMs          = "wait_ms"
+	ActionPressKey        = "press_key"
+	ActionEvaluate        = "evaluate"
+)
+
+// Page action result statuses
+const (
+	ActionStatusSuccess = "success"
+	ActionStatusError   = "error"
+	ActionStatusSkipped = "skipped" // Not run because an earlier required action failed
+)
+
+// Page action limits
+const (
+	MaxActions             = 20
+	MaxActionWaitMs        = 10000
+	DefaultActionTimeoutMs = 5000
+	MaxActionTimeoutMs     = 30000
+	MaxActionTextLength    = 10000 // Typed text and evaluated scripts
+	MaxActionResultBytes   = 10240 // JSON-encoded evaluate result
+)
+
+// ActionKeys are the named keys accepted by press_key. Any single character
+// is accepted as well.
+var ActionKeys = map[string]bool{
+	"Enter": true, "Escape": true, "Tab": true, "Backspace": true, "Delete": true, "Space": true,
+	"ArrowUp": true, "ArrowDown": true, "ArrowLeft": true, "ArrowRight": true,
+	"Home": true, "End": true, "PageUp": true, "PageDown": true,
+}
+
+// Action is a scripted page interaction (JS mode only). Actions run in order
+// after the wait event and before the HTML is captured.
+type Action struct {
+	Type      string `json:"type"`
+	Selector  string `json:"selector,omitempty"`   // click, type, wait_for_selector; optional for scroll and press_key
+	Text      string `json:"text,omitempty"`       // type
+	Key       string `json:"key,omitempty"`        // press_key
+	Script    string `json:"script,omitempty"`     // evaluate
+	X         int    `json:"x,omitempty"`          // scroll: pixels to scroll right
+	Y         int    `json:"y,omitempty"`          // scroll: pixels to scroll down
+	Ms        int    `json:"ms,omitempty"`         // wait_ms
+	TimeoutMs int    `json:"timeout_ms,omitempty"` // Max time for the step, default 5000
+	Optional  bool   `json:"optional,omitempty"`   // A failure does not stop later actions
+}
+
+// ActionResult reports the outcome of a single action
+type ActionResult struct {
+	Index    int             `json:"index"`
+	Type     string          `json:"type"`
+	Status   string          `json:"status"`
+	Result   json.RawMessage `json:"result,omitempty"` // evaluate return value
+	Error    string          `json:"error,omitempty"`
+	Duration float64         `json:"duration"` // seconds
+}
+
+// Timeout returns the step timeout in milliseconds
+func (a *Action) Timeout() int {
+	if a.TimeoutMs == 0 {
+		return DefaultActionTimeoutMs
+	}
+	return a.TimeoutMs
+}
+
+// ValidateActions checks the action list
+func ValidateActions(actions []Action) error {
+	if len(actions) > MaxActions {
+		return fmt.Errorf("too many actions: %d (max %d)", len(actions), MaxActions)
+	}
+	for i, a := range actions {
+		if err := a.validate(); err != nil {
+			return fmt.Errorf("actions[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (a *Action) validate() error {
+	if a.TimeoutMs < 0 || a.TimeoutMs > MaxActionTimeoutMs {
+		return fmt.Errorf("invalid timeout_ms: %d (must be 0-%d)", a.TimeoutMs, MaxActionTimeoutMs)
+	}
+	if len(a.Selector) > MaxWaitConditionLength {
+		return fmt.Errorf("selector exceeds %d characters", MaxWaitConditionLength)
+	}
+
+	switch a.Type {
+	case ActionClick, ActionWaitForSelector:
+		if a.Selector == "" {
+			return fmt.Errorf("%s requires a selector", a.Type)
+		}
+	case ActionType:
+		if a.Selector == "" {
+			return fmt.Errorf("type requires a selector")
+		}
+		if len(a.Text) > MaxActionTextLength {
+			return fmt.Errorf("text exceeds %d bytes", MaxActionTextLength)
+		}
+	case ActionScroll:
+		// No selector and no offset scrolls one viewport down
+	case ActionWaitMs:
+		if a.Ms < 1 || a.Ms > MaxActionWaitMs {
+			return fmt.Errorf("invalid ms: %d (must be 1-%d)", a.Ms, MaxActionWaitMs)
+		}
+	case ActionPressKey:
+		if !ActionKeys[a.Key] && utf8.RuneCountInString(a.Key) != 1 {
+			return fmt.Errorf("invalid key: %q", a.Key)
+		}
+	case ActionEvaluate:
+		if a.Script == "" {
+			return fmt.Errorf("evaluate requires a script")
+		}
+		if len(a.Script) > MaxActionTextLength {
+			return fmt.Errorf("script exceeds %d bytes", MaxActionTextLength)
+		}
+	default:
+		return fmt.Errorf("unknown action type: %q", a.Type)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateActions(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []Action
+		wantErr string
+	}{
+		{
+			name: "valid sequence",
+			actions: []Action{
+				{Type: ActionClick, Selector: "#accept-cookies", Optional: true},
+				{Type: ActionType, Selector: "input[name=q]", Text: "shoes"},
+				{Type: ActionPressKey, Key: "Enter"},
+				{Type: ActionWaitForSelector, Selector: ".results", TimeoutMs: 10000},
+				{Type: ActionScroll},
+				{Type: ActionScroll, Y: 800},
+				{Type: ActionWaitMs, Ms: 500},
+				{Type: ActionPressKey, Key: "a"},
+				{Type: ActionEvaluate, Script: "document.querySelectorAll('.item').length"},
+			},
+		},
+		{name: "empty list", actions: nil},
+		{name: "unknown type", actions: []Action{{Type: "hover"}}, wantErr: "actions[0]: unknown action type"},
+		{name: "click without selector", actions: []Action{{Type: ActionClick}}, wantErr: "requires a selector"},
+		{name: "type without selector", actions: []Action{{Type: ActionType, Text: "x"}}, wantErr: "requires a selector"},
+		{name: "wait_ms out of range", actions: []Action{{Type: ActionWaitMs, Ms: MaxActionWaitMs + 1}}, wantErr: "invalid ms"},
+		{name: "wait_ms missing", actions: []Action{{Type: ActionWaitMs}}, wantErr: "invalid ms"},
+		{name: "unknown key", actions: []Action{{Type: ActionPressKey, Key: "F13"}}, wantErr: "invalid key"},
+		{name: "empty script", actions: []Action{{Type: ActionEvaluate}}, wantErr: "requires a script"},
+		{name: "timeout too long", actions: []Action{{Type: ActionClick, Selector: "a", TimeoutMs: MaxActionTimeoutMs + 1}}, wantErr: "invalid timeout_ms"},
+		{name: "too many actions", actions: make([]Action, MaxActions+1), wantErr: "too many actions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateActions(tt.actions)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateActions() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateActions() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAction_Timeout(t *testing.T) {
+	if got := (&Action{}).Timeout(); got != DefaultActionTimeoutMs {
+		t.Errorf("Timeout() = %d, want default %d", got, DefaultActionTimeoutMs)
+	}
+	if got := (&Action{TimeoutMs: 1200}).Timeout(); got != 1200 {
+		t.Errorf("Timeout() = %d, want 1200", got)
+	}
+}
@@ -0,0 +1,86 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// Batch limits
+const (
+	MaxBatchURLs            = 1000
+	DefaultBatchConcurrency = 2
+	MaxBatchConcurrency     = 8
+)
+
+// Batch item statuses
+const (
+	BatchItemPending  = "pending"
+	BatchItemRunning  = "running"
+	BatchItemSuccess  = "success"
+	BatchItemError    = "error"
+	BatchItemCanceled = "canceled"
+)
+
+// ExtBatchRequest represents an external API request to render a list of URLs
+// with shared options. Options.URL is ignored.
+type ExtBatchRequest struct {
+	URLs        []string         `json:"urls"`
+	Concurrency int              `json:"concurrency"` // Max URLs rendered at once for this job, 0 = default
+	Options     ExtRenderRequest `json:"options"`
+}
+
+// ApplyDefaults sets default values for unset fields
+func (b *ExtBatchRequest) ApplyDefaults() {
+	if b.Concurrency == 0 {
+		b.Concurrency = DefaultBatchConcurrency
+	}
+}
+
+// Validate checks the URL count and concurrency. Individual URLs are
+// validated like single render requests by the handler.
+func (b *ExtBatchRequest) Validate() error {
+	if len(b.URLs) == 0 {
+		return fmt.Errorf("urls is required")
+	}
+	if len(b.URLs) > MaxBatchURLs {
+		return fmt.Errorf("too many urls: %d (max %d)", len(b.URLs), MaxBatchURLs)
+	}
+	if b.Concurrency < 1 || b.Concurrency > MaxBatchConcurrency {
+		return fmt.Errorf("invalid concurrency: %d (must be 1-%d)", b.Concurrency, MaxBatchConcurrency)
+	}
+	return nil
+}
+
+// BatchItem is the state and result of a single URL in a batch job
+type BatchItem struct {
+	URL      string         `json:"url"`
+	Status   string         `json:"status"`
+	Attempts int            `json:"attempts"` // Includes retries after POOL_EXHAUSTED
+	Data     *ExtRenderData `json:"data,omitempty"`
+	Error    *RenderError   `json:"error,omitempty"`
+}
+
+// BatchProgress counts batch items by status
+type BatchProgress struct {
+	Total     int `json:"total"`
+	Pending   int `json:"pending"`
+	Running   int `json:"running"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Canceled  int `json:"canceled"`
+}
+
+// ExtBatchJob is a snapshot of a batch job
+type ExtBatchJob struct {
+	ID          string        `json:"id"`
+	Status      string        `json:"status"` // JobStatus*
+	Concurrency int           `json:"concurrency"`
+	CreatedAt   time.Time     `json:"created_at"`
+	StartedAt   *time.Time    `json:"started_at,omitempty"`
+	FinishedAt  *time.Time    `json:"finished_at,omitempty"`
+	Progress    BatchProgress `json:"progress"`
+	Items       []BatchItem   `json:"items,omitempty"`
+}
+
+// ExtBatchResponse represents the external API response for batch requests
+type ExtBatchResponse = ExtJobResponse[ExtBatchJob]
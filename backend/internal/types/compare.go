@@ -1,5 +1,7 @@
 package types
 
+import "fmt"
+
 // ExtCompareRequest represents an external API request to compare JS-rendered vs non-JS versions of a page.
 type ExtCompareRequest struct {
 	URL             string   `json:"url"`
@@ -12,6 +14,9 @@ type ExtCompareRequest struct {
 	BlockSocial     bool     `json:"block_social"`
 	BlockedTypes    []string `json:"blocked_types"`
 
+	Headers map[string]string `json:"headers"`
+	Cookies []Cookie          `json:"cookies"`
+
 	MaxContentLength int  `json:"max_content_length"`
 	MaxDiffLength    int  `json:"max_diff_length"`
 	IncludeHTML      bool `json:"include_html"`
@@ -22,6 +27,23 @@ type ExtCompareRequest struct {
 	IncludeImages    bool `json:"include_images"`
 
 	IncludeStructuredData bool `json:"include_structured_data"`
+
+	// Non-JS side: "http" (default) fetches the raw HTML, "chrome_nojs" renders
+	// the page in Chrome with JavaScript disabled
+	NonJSRenderMode string `json:"non_js_render_mode"`
+
+	IncludeScreenshot bool               `json:"include_screenshot"` // Both sides with chrome_nojs, else JS only
+	Screenshot        *ScreenshotOptions `json:"screenshot"`         // Used with include_screenshot, nil = viewport PNG
+	IncludeNetwork    bool               `json:"include_network"`    // Both sides with chrome_nojs, else JS only
+}
+
+// ValidateNonJSRenderMode checks the non-JS side render mode. Empty is valid.
+func (e *ExtCompareRequest) ValidateNonJSRenderMode() error {
+	switch e.NonJSRenderMode {
+	case "", RenderModeHTTP, RenderModeChromeNoJS:
+		return nil
+	}
+	return fmt.Errorf("invalid non_js_render_mode: %q (use http or chrome_nojs)", e.NonJSRenderMode)
 }
 
 // ToJSRenderRequest converts an ExtCompareRequest to a RenderRequest for JS-enabled rendering.
@@ -30,7 +52,7 @@ func (e *ExtCompareRequest) ToJSRenderRequest() *RenderRequest {
 	if e.FollowRedirects != nil {
 		followRedirects = *e.FollowRedirects
 	}
-	return &RenderRequest{
+	req := &RenderRequest{
 		URL:               e.URL,
 		JSEnabled:         true,
 		FollowRedirects:   &followRedirects,
@@ -41,8 +63,27 @@ func (e *ExtCompareRequest) ToJSRenderRequest() *RenderRequest {
 		BlockAds:          e.BlockAds,
 		BlockSocial:       e.BlockSocial,
 		BlockedTypes:      e.BlockedTypes,
-		CaptureScreenshot: false,
+		Headers:           e.Headers,
+		Cookies:           e.Cookies,
+		CaptureScreenshot: e.IncludeScreenshot,
+	}
+	if e.Screenshot != nil {
+		screenshot := *e.Screenshot
+		req.Screenshot = &screenshot
+	}
+	return req
+}
+
+// ToNonJSRenderRequest converts an ExtCompareRequest to a RenderRequest for the non-JS side:
+// an HTTP fetch, or with NonJSRenderMode chrome_nojs a Chrome render with JavaScript disabled.
+func (e *ExtCompareRequest) ToNonJSRenderRequest() *RenderRequest {
+	if e.NonJSRenderMode != RenderModeChromeNoJS {
+		return e.ToHTTPRenderRequest()
 	}
+	req := e.ToJSRenderRequest()
+	req.JSEnabled = false
+	req.RenderMode = RenderModeChromeNoJS
+	return req
 }
 
 // ToHTTPRenderRequest converts an ExtCompareRequest to a RenderRequest for non-JS HTTP fetching.
@@ -59,6 +100,8 @@ func (e *ExtCompareRequest) ToHTTPRenderRequest() *RenderRequest {
 		UserAgent:         e.UserAgent,
 		Timeout:           e.Timeout,
 		WaitEvent:         e.WaitEvent,
+		Headers:           e.Headers,
+		Cookies:           e.Cookies,
 		CaptureScreenshot: false,
 	}
 }
@@ -73,8 +116,9 @@ type ExtCompareResponse struct {
 // ExtCompareData contains the comparison results between JS-rendered and non-JS versions.
 type ExtCompareData struct {
 	JSStatus        *FetchStatus     `json:"js_status"`
-	HTTPStatus      *FetchStatus     `json:"http_status"`
+	HTTPStatus      *FetchStatus     `json:"http_status"` // Non-JS side, HTTP fetch or chrome_nojs render
 	JS              *ExtRenderData   `json:"js"`
+	NonJS           *ExtRenderData   `json:"non_js,omitempty"` // chrome_nojs render, only with non_js_render_mode chrome_nojs
 	Diff            *CompareDiff     `json:"diff"`
 	RenderingImpact *RenderingImpact `json:"rendering_impact"`
 }
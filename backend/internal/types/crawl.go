@@ -0,0 +1,126 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// Crawl limits
+const (
+	DefaultCrawlMaxDepth    = 3
+	MaxCrawlDepth           = 10
+	DefaultCrawlMaxPages    = 100
+	MaxCrawlPages           = 1000
+	DefaultCrawlConcurrency = 2
+	MaxCrawlConcurrency     = 8
+)
+
+// Crawl page statuses
+const (
+	CrawlPagePending       = "pending"
+	CrawlPageCrawled       = "crawled"
+	CrawlPageError         = "error"
+	CrawlPageRobotsBlocked = "robots_blocked"
+	CrawlPageCanceled      = "canceled"
+)
+
+// Reasons a crawled page is not indexable
+const (
+	NonIndexableStatusCode    = "status_code"
+	NonIndexableNoindex       = "noindex"
+	NonIndexableCanonicalized = "canonicalized"
+	NonIndexableRobotsBlocked = "robots_blocked"
+	NonIndexableCrawlFailed   = "crawl_failed"
+)
+
+// ExtCrawlRequest represents an external API request to crawl a site from a seed URL.
+// Options apply to every page; Options.URL and the include_* flags are ignored.
+type ExtCrawlRequest struct {
+	URL          string           `json:"url"`
+	MaxDepth     *int             `json:"max_depth,omitempty"` // Click depth from the seed, 0 = seed only
+	MaxPages     int              `json:"max_pages"`
+	Concurrency  int              `json:"concurrency"`
+	IgnoreRobots bool             `json:"ignore_robots"`
+	Options      ExtRenderRequest `json:"options"`
+}
+
+// ApplyDefaults sets default values for unset fields
+func (c *ExtCrawlRequest) ApplyDefaults() {
+	if c.MaxDepth == nil {
+		depth := DefaultCrawlMaxDepth
+		c.MaxDepth = &depth
+	}
+	if c.MaxPages == 0 {
+		c.MaxPages = DefaultCrawlMaxPages
+	}
+	if c.Concurrency == 0 {
+		c.Concurrency = DefaultCrawlConcurrency
+	}
+}
+
+// Validate checks crawl limits. The seed URL is validated like a render
+// request by the handler. Call ApplyDefaults first.
+func (c *ExtCrawlRequest) Validate() error {
+	if *c.MaxDepth < 0 || *c.MaxDepth > MaxCrawlDepth {
+		return fmt.Errorf("invalid max_depth: %d (must be 0-%d)", *c.MaxDepth, MaxCrawlDepth)
+	}
+	if c.MaxPages < 1 || c.MaxPages > MaxCrawlPages {
+		return fmt.Errorf("invalid max_pages: %d (must be 1-%d)", c.MaxPages, MaxCrawlPages)
+	}
+	if c.Concurrency < 1 || c.Concurrency > MaxCrawlConcurrency {
+		return fmt.Errorf("invalid concurrency: %d (must be 1-%d)", c.Concurrency, MaxCrawlConcurrency)
+	}
+	return nil
+}
+
+// CrawlPage is the summary of a single crawled page
+type CrawlPage struct {
+	URL                string       `json:"url"`
+	FinalURL           string       `json:"final_url,omitempty"`
+	Depth              int          `json:"depth"` // Click depth from the seed
+	Status             string       `json:"status"`
+	StatusCode         int          `json:"status_code,omitempty"`
+	Title              string       `json:"title"`
+	CanonicalURL       string       `json:"canonical_url"`
+	Indexable          bool         `json:"indexable"`
+	NonIndexableReason string       `json:"non_indexable_reason,omitempty"`
+	WordCount          int          `json:"word_count"`
+	InternalLinks      int          `json:"internal_links"` // Outgoing internal dofollow links
+	ExternalLinks      int          `json:"external_links"`
+	Inlinks            int          `json:"inlinks"` // Crawled pages linking to this page
+	Error              *RenderError `json:"error,omitempty"`
+}
+
+// CrawlLink is an edge of the internal link graph
+type CrawlLink struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// CrawlProgress counts crawl pages by status
+type CrawlProgress struct {
+	Discovered    int `json:"discovered"` // Unique internal URLs found, including the seed
+	Pending       int `json:"pending"`
+	Crawled       int `json:"crawled"`
+	Failed        int `json:"failed"`
+	RobotsBlocked int `json:"robots_blocked"`
+	Canceled      int `json:"canceled"`
+}
+
+// ExtCrawlJob is a snapshot of a crawl job
+type ExtCrawlJob struct {
+	ID         string        `json:"id"`
+	Status     string        `json:"status"` // JobStatus*
+	URL        string        `json:"url"`
+	MaxDepth   int           `json:"max_depth"`
+	MaxPages   int           `json:"max_pages"`
+	CreatedAt  time.Time     `json:"created_at"`
+	StartedAt  *time.Time    `json:"started_at,omitempty"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+	Progress   CrawlProgress `json:"progress"`
+	Pages      []CrawlPage   `json:"pages,omitempty"`
+	Links      []CrawlLink   `json:"links,omitempty"`
+}
+
+// ExtCrawlResponse represents the external API response for crawl requests
+type ExtCrawlResponse = ExtJobResponse[ExtCrawlJob]
@@ -0,0 +1,139 @@
+package types
+
+import "fmt"
+
+// Device profiles for RenderRequest.Device
+const (
+	DeviceIPhoneSE            = "iphone-se"
+	DeviceIPhone15            = "iphone-15"
+	DeviceIPhone15ProMax      = "iphone-15-pro-max"
+	DevicePixel8              = "pixel-8"
+	DeviceIPad                = "ipad"
+	DeviceIPadPro             = "ipad-pro"
+	DeviceDesktopHD           = "desktop-hd"
+	DeviceDesktop4K           = "desktop-4k"
+	DeviceGooglebotSmartphone = "googlebot-smartphone"
+)
+
+// Viewport limits
+const (
+	MinViewportSize       = 100
+	MaxViewportSize       = 8192
+	MinDeviceScaleFactor  = 0.5
+	MaxDeviceScaleFactor  = 4
+	defaultDesktopWidth   = 1920
+	defaultDesktopHeight  = 1080
+	defaultMobileWidth    = 375
+	defaultMobileHeight   = 812
+	iOSSafariUserAgent    = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1"
+	iPadSafariUserAgent   = "Mozilla/5.0 (iPad; CPU OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1"
+	pixel8ChromeUserAgent = "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36"
+)
+
+// ClientHints are the User-Agent Client Hints (Sec-CH-UA-*) and
+// navigator.userAgentData values of a Chromium-based device
+type ClientHints struct {
+	Platform        string
+	PlatformVersion string
+	Architecture    string
+	Model           string
+}
+
+// Device is the emulated screen and browser of a JS render
+type Device struct {
+	Name              string  `json:"name,omitempty"`
+	Width             int     `json:"width"`
+	Height            int     `json:"height"`
+	DeviceScaleFactor float64 `json:"device_scale_factor"`
+	Mobile            bool    `json:"mobile"`
+	Touch             bool    `json:"touch"`
+
+	UserAgent   string       `json:"-"`                  // Used when the request sets no user_agent
+	Platform    string       `json:"platform,omitempty"` // navigator.platform
+	ClientHints *ClientHints `json:"-"`                  // nil for browsers that do not send client hints
+}
+
+// DeviceProfiles match the Chrome DevTools device presets. Width and height
+// are in CSS pixels.
+var DeviceProfiles = map[string]Device{
+	DeviceIPhoneSE: {
+		Width: 375, Height: 667, DeviceScaleFactor: 2, Mobile: true, Touch: true,
+		UserAgent: iOSSafariUserAgent, Platform: "iPhone",
+	},
+	DeviceIPhone15: {
+		Width: 393, Height: 852, DeviceScaleFactor: 3, Mobile: true, Touch: true,
+		UserAgent: iOSSafariUserAgent, Platform: "iPhone",
+	},
+	DeviceIPhone15ProMax: {
+		Width: 430, Height: 932, DeviceScaleFactor: 3, Mobile: true, Touch: true,
+		UserAgent: iOSSafariUserAgent, Platform: "iPhone",
+	},
+	DevicePixel8: {
+		Width: 412, Height: 915, DeviceScaleFactor: 2.625, Mobile: true, Touch: true,
+		UserAgent: pixel8ChromeUserAgent, Platform: "Linux armv8l",
+		ClientHints: &ClientHints{Platform: "Android", PlatformVersion: "14.0.0", Model: "Pixel 8"},
+	},
+	DeviceIPad: {
+		Width: 820, Height: 1180, DeviceScaleFactor: 2, Mobile: true, Touch: true,
+		UserAgent: iPadSafariUserAgent, Platform: "iPad",
+	},
+	DeviceIPadPro: {
+		Width: 1024, Height: 1366, DeviceScaleFactor: 2, Mobile: true, Touch: true,
+		UserAgent: iPadSafariUserAgent, Platform: "iPad",
+	},
+	DeviceDesktopHD: {
+		Width: 1920, Height: 1080, DeviceScaleFactor: 1,
+		UserAgent: UserAgentPresets[UserAgentChrome], Platform: "Win32",
+		ClientHints: &ClientHints{Platform: "Windows", PlatformVersion: "10.0.0", Architecture: "x86"},
+	},
+	DeviceDesktop4K: {
+		Width: 3840, Height: 2160, DeviceScaleFactor: 1,
+		UserAgent: UserAgentPresets[UserAgentChrome], Platform: "Win32",
+		ClientHints: &ClientHints{Platform: "Windows", PlatformVersion: "10.0.0", Architecture: "x86"},
+	},
+	DeviceGooglebotSmartphone: {
+		Width: 412, Height: 732, DeviceScaleFactor: 2.625, Mobile: true, Touch: true,
+		UserAgent: UserAgentPresets[UserAgentGooglebotMobile], Platform: "Linux armv8l",
+		ClientHints: &ClientHints{Platform: "Android", PlatformVersion: "6.0.1", Model: "Nexus 5X"},
+	},
+}
+
+// ResolveDevice returns the device for a profile name with the viewport
+// overrides applied (0 = keep the profile value). Without a profile the base
+// is a 1920x1080 desktop, or a 375x812 phone when mobile is set.
+func ResolveDevice(name string, width, height int, scale float64, mobile bool) (*Device, error) {
+	var device Device
+	switch {
+	case name != "":
+		profile, ok := DeviceProfiles[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid device: %q", name)
+		}
+		device = profile
+		device.Name = name
+	case mobile:
+		device = Device{Width: defaultMobileWidth, Height: defaultMobileHeight, DeviceScaleFactor: 1, Mobile: true}
+	default:
+		device = Device{Width: defaultDesktopWidth, Height: defaultDesktopHeight, DeviceScaleFactor: 1}
+	}
+
+	if width != 0 {
+		if width < MinViewportSize || width > MaxViewportSize {
+			return nil, fmt.Errorf("invalid viewport_width: %d (must be %d-%d)", width, MinViewportSize, MaxViewportSize)
+		}
+		device.Width = width
+	}
+	if height != 0 {
+		if height < MinViewportSize || height > MaxViewportSize {
+			return nil, fmt.Errorf("invalid viewport_height: %d (must be %d-%d)", height, MinViewportSize, MaxViewportSize)
+		}
+		device.Height = height
+	}
+	if scale != 0 {
+		if scale < MinDeviceScaleFactor || scale > MaxDeviceScaleFactor {
+			return nil, fmt.Errorf("invalid device_scale_factor: %v (must be %v-%v)", scale, MinDeviceScaleFactor, MaxDeviceScaleFactor)
+		}
+		device.DeviceScaleFactor = scale
+	}
+	return &device, nil
+}
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveDevice(t *testing.T) {
+	tests := []struct {
+		name    string
+		device  string
+		width   int
+		height  int
+		scale   float64
+		mobile  bool
+		want    Device
+		wantErr string
+	}{
+		{name: "desktop default", want: Device{Width: 1920, Height: 1080, DeviceScaleFactor: 1}},
+		{name: "mobile default", mobile: true, want: Device{Width: 375, Height: 812, DeviceScaleFactor: 1, Mobile: true}},
+		{
+			name:   "profile",
+			device: DeviceIPhone15,
+			want:   Device{Name: DeviceIPhone15, Width: 393, Height: 852, DeviceScaleFactor: 3, Mobile: true, Touch: true},
+		},
+		{
+			name:   "profile ignores mobile fallback",
+			device: DeviceDesktopHD,
+			mobile: true,
+			want:   Device{Name: DeviceDesktopHD, Width: 1920, Height: 1080, DeviceScaleFactor: 1},
+		},
+		{
+			name:   "overrides",
+			device: DevicePixel8,
+			width:  360,
+			scale:  1,
+			want:   Device{Name: DevicePixel8, Width: 360, Height: 915, DeviceScaleFactor: 1, Mobile: true, Touch: true},
+		},
+		{name: "overrides without profile", width: 1280, height: 720, want: Device{Width: 1280, Height: 720, DeviceScaleFactor: 1}},
+		{name: "unknown profile", device: "nokia-3310", wantErr: "invalid device"},
+		{name: "width too small", width: MinViewportSize - 1, wantErr: "invalid viewport_width"},
+		{name: "height too large", height: MaxViewportSize + 1, wantErr: "invalid viewport_height"},
+		{name: "scale out of range", scale: 5, wantErr: "invalid device_scale_factor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveDevice(tt.device, tt.width, tt.height, tt.scale, tt.mobile)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveDevice() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveDevice() error = %v", err)
+			}
+			if got.Name != tt.want.Name || got.Width != tt.want.Width || got.Height != tt.want.Height ||
+				got.DeviceScaleFactor != tt.want.DeviceScaleFactor || got.Mobile != tt.want.Mobile || got.Touch != tt.want.Touch {
+				t.Errorf("ResolveDevice() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderRequest_DeviceUserAgent(t *testing.T) {
+	req := &RenderRequest{Device: DeviceGooglebotSmartphone}
+	req.ApplyDefaults()
+	if req.UserAgent != "" {
+		t.Errorf("ApplyDefaults() UserAgent = %q, want empty with a device profile", req.UserAgent)
+	}
+	if got := req.ResolvedUserAgent(); got != UserAgentPresets[UserAgentGooglebotMobile] {
+		t.Errorf("ResolvedUserAgent() = %q, want the profile user agent", got)
+	}
+	device, err := req.DeviceEmulation(false)
+	if err != nil {
+		t.Fatalf("DeviceEmulation() error = %v", err)
+	}
+	if device.Platform == "" || device.ClientHints == nil {
+		t.Errorf("DeviceEmulation() = %+v, want the profile platform hints", device)
+	}
+
+	// An explicit user agent replaces the profile browser and its hints
+	req.UserAgent = UserAgentFirefox
+	if got := req.ResolvedUserAgent(); got != UserAgentPresets[UserAgentFirefox] {
+		t.Errorf("ResolvedUserAgent() = %q, want the firefox preset", got)
+	}
+	device, _ = req.DeviceEmulation(false)
+	if device.Platform != "" || device.ClientHints != nil {
+		t.Errorf("DeviceEmulation() = %+v, want no platform hints", device)
+	}
+	if device.Width != 412 {
+		t.Errorf("Width = %d, want the profile viewport 412", device.Width)
+	}
+}
+
+func TestDeviceProfiles_Valid(t *testing.T) {
+	for name, d := range DeviceProfiles {
+		if d.UserAgent == "" || d.Platform == "" {
+			t.Errorf("%s: missing user agent or platform", name)
+		}
+		if d.Width < MinViewportSize || d.Width > MaxViewportSize || d.Height < MinViewportSize || d.Height > MaxViewportSize {
+			t.Errorf("%s: viewport %dx%d out of range", name, d.Width, d.Height)
+		}
+		if d.DeviceScaleFactor < MinDeviceScaleFactor || d.DeviceScaleFactor > MaxDeviceScaleFactor {
+			t.Errorf("%s: device scale factor %v out of range", name, d.DeviceScaleFactor)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialog actions for RenderRequest.DialogAction
+const (
+	DialogActionAccept  = "accept"  // OK: confirm returns true, prompt the prompt text, beforeunload leaves the page
+	DialogActionDismiss = "dismiss" // Cancel: confirm returns false, prompt null, beforeunload stays on the page
+)
+
+// Dialog limits
+const (
+	DefaultDialogAction     = DialogActionDismiss
+	MaxDialogs              = 50   // Dialogs recorded per render, later ones are still handled
+	MaxDialogMessageLength  = 1000 // Characters kept of a dialog message
+	MaxDialogPromptTextSize = 1000
+)
+
+// PromptPermissions are the permissions a page can prompt for. They are
+// denied unless listed in RenderRequest.Permissions.
+var PromptPermissions = []string{
+	"geolocation",
+	"notifications",
+	"camera",
+	"microphone",
+	"midi",
+	"clipboard-read",
+	"clipboard-write",
+	"persistent-storage",
+	"idle-detection",
+	"storage-access",
+	"window-management",
+	"local-fonts",
+}
+
+// Dialog is a JavaScript dialog the page opened during a JS render
+type Dialog struct {
+	Type    string  `json:"type"` // alert, confirm, prompt or beforeunload
+	Message string  `json:"message"`
+	URL     string  `json:"url"`    // Frame URL
+	Action  string  `json:"action"` // accept or dismiss
+	Time    float64 `json:"time"`   // Seconds since render start
+}
+
+// ValidateDialogOptions checks the dialog action and prompt text
+func ValidateDialogOptions(action, promptText string) error {
+	switch action {
+	case "", DialogActionAccept, DialogActionDismiss:
+	default:
+		return fmt.Errorf("invalid dialog_action: %q (use accept or dismiss)", action)
+	}
+	if len(promptText) > MaxDialogPromptTextSize {
+		return fmt.Errorf("dialog_prompt_text too long (max %d bytes)", MaxDialogPromptTextSize)
+	}
+	return nil
+}
+
+// ValidatePermissions checks a permission allowlist against PromptPermissions
+func ValidatePermissions(permissions []string) error {
+	for _, p := range permissions {
+		if !isPromptPermission(p) {
+			return fmt.Errorf("invalid permission: %q (use %s)", p, strings.Join(PromptPermissions, ", "))
+		}
+	}
+	return nil
+}
+
+func isPromptPermission(name string) bool {
+	for _, p := range PromptPermissions {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package types
+
+import "testing"
+
+func TestValidateDialogOptions(t *testing.T) {
+	tests := []struct {
+		action     string
+		promptText string
+		wantErr    bool
+	}{
+		{action: ""},
+		{action: DialogActionAccept, promptText: "yes"},
+		{action: DialogActionDismiss},
+		{action: "ignore", wantErr: true},
+		{action: DialogActionAccept, promptText: string(make([]byte, MaxDialogPromptTextSize+1)), wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := ValidateDialogOptions(tt.action, tt.promptText); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateDialogOptions(%q) error = %v, wantErr %v", tt.action, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidatePermissions(t *testing.T) {
+	if err := ValidatePermissions(nil); err != nil {
+		t.Errorf("ValidatePermissions(nil) error = %v", err)
+	}
+	if err := ValidatePermissions([]string{"geolocation", "notifications"}); err != nil {
+		t.Errorf("ValidatePermissions() error = %v", err)
+	}
+	if err := ValidatePermissions([]string{"camera", "telepathy"}); err == nil {
+		t.Error("ValidatePermissions() with an unknown permission = nil, want error")
+	}
+}
+
+func TestRenderRequest_ApplyDefaults_DialogAction(t *testing.T) {
+	req := &RenderRequest{}
+	req.ApplyDefaults()
+	if req.DialogAction != DialogActionDismiss {
+		t.Errorf("DialogAction = %q, want %q", req.DialogAction, DialogActionDismiss)
+	}
+}
@@ -0,0 +1,187 @@
+package types
+
+import "strings"
+
+// HAR response body limits
+const (
+	MaxHARBodyBytes      = 1 << 20  // Larger bodies are left out with a comment
+	MaxHARTotalBodyBytes = 20 << 20 // Bodies stop being captured past this total
+)
+
+// HARRedacted replaces the values of sensitive headers in a redacted HAR
+const HARRedacted = "[REDACTED]"
+
+// harSensitiveHeaders are credentials that must not be served from the
+// unauthenticated HAR download (lowercase)
+var harSensitiveHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"set-cookie":          true,
+}
+
+// HAROptions configures the HAR export of a render (JS mode only)
+type HAROptions struct {
+	IncludeBodies bool `json:"include_bodies"` // Add response bodies (text as-is, binary base64)
+}
+
+// HAR is an HTTP Archive 1.2 document
+// (http://www.softwareishard.com/blog/har-12-spec/)
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// Redacted returns a copy of the HAR with the values of credential headers
+// (Authorization, Proxy-Authorization, Cookie, Set-Cookie) and of the
+// request's custom headers, which may carry credentials of their own,
+// replaced by HARRedacted. The header entries are kept so viewers still show
+// they were sent.
+func (h *HAR) Redacted(customHeaders map[string]string) *HAR {
+	sensitive := make(map[string]bool, len(harSensitiveHeaders)+len(customHeaders))
+	for name := range harSensitiveHeaders {
+		sensitive[name] = true
+	}
+	for name := range customHeaders {
+		sensitive[strings.ToLower(name)] = true
+	}
+
+	redacted := *h
+	redacted.Log.Entries = make([]HAREntry, len(h.Log.Entries))
+	for i, entry := range h.Log.Entries {
+		entry.Request.Headers = redactHARHeaders(entry.Request.Headers, sensitive)
+		entry.Response.Headers = redactHARHeaders(entry.Response.Headers, sensitive)
+		redacted.Log.Entries[i] = entry
+	}
+	return &redacted
+}
+
+func redactHARHeaders(headers []HARHeader, sensitive map[string]bool) []HARHeader {
+	result := make([]HARHeader, len(headers))
+	for i, header := range headers {
+		if sensitive[strings.ToLower(header.Name)] {
+			header.Value = HARRedacted
+		}
+		result[i] = header
+	}
+	return result
+}
+
+// HARLog is the root object of a HAR document
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Pages   []HARPage  `json:"pages"`
+	Entries []HAREntry `json:"entries"`
+}
+
+// HARCreator names the application that created the HAR
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HARPage is a rendered page
+type HARPage struct {
+	StartedDateTime string         `json:"startedDateTime"`
+	ID              string         `json:"id"`
+	Title           string         `json:"title"`
+	PageTimings     HARPageTimings `json:"pageTimings"`
+}
+
+// HARPageTimings are milliseconds since page start, -1 if not reached
+type HARPageTimings struct {
+	OnContentLoad float64 `json:"onContentLoad"`
+	OnLoad        float64 `json:"onLoad"`
+}
+
+// HAREntry is a single request/response pair. Redirect hops are separate entries.
+type HAREntry struct {
+	PageRef         string      `json:"pageref"`
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"` // ms, sum of non-negative timings excluding ssl
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+	Comment         string      `json:"comment,omitempty"`
+
+	// Custom fields (underscore prefix per the spec)
+	ResourceType  string `json:"_resourceType,omitempty"`
+	BlockedReason string `json:"_blockedReason,omitempty"`
+	Error         string `json:"_error,omitempty"`
+	FromCache     bool   `json:"_fromCache,omitempty"`
+}
+
+// HARRequest is the request part of an entry
+type HARRequest struct {
+	Method      string          `json:"method"`
+	URL         string          `json:"url"`
+	HTTPVersion string          `json:"httpVersion"`
+	Cookies     []HARCookie     `json:"cookies"`
+	Headers     []HARHeader     `json:"headers"`
+	QueryString []HARQueryParam `json:"queryString"`
+	PostData    *HARPostData    `json:"postData,omitempty"`
+	HeadersSize int             `json:"headersSize"` // -1, not tracked
+	BodySize    int             `json:"bodySize"`
+}
+
+// HARResponse is the response part of an entry. Status is 0 for blocked
+// and failed requests.
+type HARResponse struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []HARCookie `json:"cookies"`
+	Headers     []HARHeader `json:"headers"`
+	Content     HARContent  `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"` // -1, not tracked
+	BodySize    int         `json:"bodySize"`    // Encoded (transfer) size, -1 if unknown
+}
+
+// HARHeader is a single header. Repeated headers are separate entries.
+type HARHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARCookie is a request or response cookie. Cookies are not broken out
+// of the Cookie and Set-Cookie headers, so these lists are always empty.
+type HARCookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARQueryParam is a single query string parameter
+type HARQueryParam struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARPostData is a request body
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// HARContent describes the response body
+type HARContent struct {
+	Size     int    `json:"size"` // Decoded size
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"` // "base64" for binary bodies
+	Comment  string `json:"comment,omitempty"`
+}
+
+// HARTimings are phase durations in milliseconds, -1 when the phase does
+// not apply (e.g. reused connection)
+type HARTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"` // Includes ssl
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
@@ -0,0 +1,40 @@
+package types
+
+import "testing"
+
+func TestHAR_Redacted(t *testing.T) {
+	har := &HAR{Log: HARLog{Entries: []HAREntry{{
+		Request: HARRequest{Headers: []HARHeader{
+			{Name: "authorization", Value: "Bearer secret"},
+			{Name: "Cookie", Value: "session=abc"},
+			{Name: "User-Agent", Value: "test"},
+			{Name: "x-staging-token", Value: "secret"},
+		}},
+		Response: HARResponse{Headers: []HARHeader{
+			{Name: "Set-Cookie", Value: "a=1"},
+			{Name: "Set-Cookie", Value: "b=2"},
+			{Name: "Content-Type", Value: "text/html"},
+		}},
+	}}}}
+
+	redacted := har.Redacted(map[string]string{"X-Staging-Token": "secret"})
+
+	entry := redacted.Log.Entries[0]
+	wantRequest := []string{HARRedacted, HARRedacted, "test", HARRedacted}
+	for i, want := range wantRequest {
+		if got := entry.Request.Headers[i].Value; got != want {
+			t.Errorf("Request.Headers[%d] = %q, want %q", i, got, want)
+		}
+	}
+	wantResponse := []string{HARRedacted, HARRedacted, "text/html"}
+	for i, want := range wantResponse {
+		if got := entry.Response.Headers[i].Value; got != want {
+			t.Errorf("Response.Headers[%d] = %q, want %q", i, got, want)
+		}
+	}
+
+	// The original HAR keeps its values for the authenticated response
+	if got := har.Log.Entries[0].Request.Headers[0].Value; got != "Bearer secret" {
+		t.Errorf("original Authorization = %q, want it unchanged", got)
+	}
+}
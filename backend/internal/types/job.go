@@ -0,0 +1,16 @@
+package types
+
+// Async API job statuses, shared by batch and crawl jobs
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusCanceled  = "canceled"
+)
+
+// ExtJobResponse represents the external API response for an async job
+type ExtJobResponse[T any] struct {
+	Success bool         `json:"success"`
+	Data    *T           `json:"data,omitempty"`
+	Error   *RenderError `json:"error,omitempty"`
+}
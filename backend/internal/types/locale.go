@@ -0,0 +1,82 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	_ "time/tzdata" // Validate IANA time zones on hosts without a zoneinfo database
+)
+
+// Geolocation limits
+const (
+	DefaultGeolocationAccuracy = 100 // meters
+	MaxGeolocationAccuracy     = 100000
+)
+
+// localePattern matches BCP 47 tags such as "en", "de-DE" or "zh-Hant-TW"
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8}){0,3}$`)
+
+// Geolocation is the position reported by navigator.geolocation (JS mode only)
+type Geolocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Accuracy  float64 `json:"accuracy"` // Meters, 0 = default
+}
+
+// ApplyDefaults sets default values for unset fields
+func (g *Geolocation) ApplyDefaults() {
+	if g.Accuracy == 0 {
+		g.Accuracy = DefaultGeolocationAccuracy
+	}
+}
+
+// Validate checks coordinates and accuracy. Call ApplyDefaults first.
+func (g *Geolocation) Validate() error {
+	if g.Latitude < -90 || g.Latitude > 90 {
+		return fmt.Errorf("invalid latitude: %v (must be -90 to 90)", g.Latitude)
+	}
+	if g.Longitude < -180 || g.Longitude > 180 {
+		return fmt.Errorf("invalid longitude: %v (must be -180 to 180)", g.Longitude)
+	}
+	if g.Accuracy <= 0 || g.Accuracy > MaxGeolocationAccuracy {
+		return fmt.Errorf("invalid accuracy: %v (must be 0-%d meters)", g.Accuracy, MaxGeolocationAccuracy)
+	}
+	return nil
+}
+
+// ValidateLocale checks a BCP 47 locale such as "de-DE". Empty is valid.
+func ValidateLocale(locale string) error {
+	if locale != "" && !localePattern.MatchString(locale) {
+		return fmt.Errorf("invalid locale: %q (use a BCP 47 tag such as de-DE)", locale)
+	}
+	return nil
+}
+
+// ValidateTimezone checks an IANA time zone such as "Europe/Berlin". Empty
+// is valid.
+func ValidateTimezone(timezone string) error {
+	if timezone == "" {
+		return nil
+	}
+	if timezone == "Local" {
+		return fmt.Errorf("invalid timezone: %q", timezone)
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("invalid timezone: %q (use an IANA name such as Europe/Berlin)", timezone)
+	}
+	return nil
+}
+
+// AcceptLanguage returns the Accept-Language header Chrome sends for a
+// locale: "de-DE" gives "de-DE,de;q=0.9". Empty for an empty locale.
+func AcceptLanguage(locale string) string {
+	if locale == "" {
+		return ""
+	}
+	language, _, found := strings.Cut(locale, "-")
+	if !found {
+		return locale
+	}
+	return locale + "," + language + ";q=0.9"
+}
@@ -0,0 +1,66 @@
+package types
+
+import "testing"
+
+func TestValidateLocale(t *testing.T) {
+	for _, locale := range []string{"", "en", "de-DE", "zh-Hant-TW", "es-419"} {
+		if err := ValidateLocale(locale); err != nil {
+			t.Errorf("ValidateLocale(%q) error = %v", locale, err)
+		}
+	}
+	for _, locale := range []string{"de_DE", "d", "english", "en-", "en-US;q=0.9"} {
+		if err := ValidateLocale(locale); err == nil {
+			t.Errorf("ValidateLocale(%q) = nil, want error", locale)
+		}
+	}
+}
+
+func TestValidateTimezone(t *testing.T) {
+	for _, tz := range []string{"", "UTC", "Europe/Berlin", "America/Argentina/Buenos_Aires"} {
+		if err := ValidateTimezone(tz); err != nil {
+			t.Errorf("ValidateTimezone(%q) error = %v", tz, err)
+		}
+	}
+	for _, tz := range []string{"Local", "Mars/Olympus", "../etc/passwd", "CEST+2"} {
+		if err := ValidateTimezone(tz); err == nil {
+			t.Errorf("ValidateTimezone(%q) = nil, want error", tz)
+		}
+	}
+}
+
+func TestGeolocation_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		g       Geolocation
+		wantErr bool
+	}{
+		{name: "valid", g: Geolocation{Latitude: 52.52, Longitude: 13.405}},
+		{name: "poles and date line", g: Geolocation{Latitude: -90, Longitude: 180}},
+		{name: "latitude out of range", g: Geolocation{Latitude: 90.1}, wantErr: true},
+		{name: "longitude out of range", g: Geolocation{Longitude: -181}, wantErr: true},
+		{name: "negative accuracy", g: Geolocation{Accuracy: -1}, wantErr: true},
+		{name: "accuracy too large", g: Geolocation{Accuracy: MaxGeolocationAccuracy + 1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.g.ApplyDefaults()
+			if err := tt.g.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAcceptLanguage(t *testing.T) {
+	tests := map[string]string{
+		"":           "",
+		"fr":         "fr",
+		"de-DE":      "de-DE,de;q=0.9",
+		"zh-Hant-TW": "zh-Hant-TW,zh;q=0.9",
+	}
+	for locale, want := range tests {
+		if got := AcceptLanguage(locale); got != want {
+			t.Errorf("AcceptLanguage(%q) = %q, want %q", locale, got, want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package types
+
+import "fmt"
+
+// PDF paper sizes in inches (width x height, portrait)
+var PDFPaperSizes = map[string][2]float64{
+	"letter":  {8.5, 11},
+	"legal":   {8.5, 14},
+	"tabloid": {11, 17},
+	"a3":      {11.69, 16.54},
+	"a4":      {8.27, 11.69},
+	"a5":      {5.83, 8.27},
+}
+
+// PDF defaults and limits
+const (
+	DefaultPDFPaper  = "a4"
+	DefaultPDFMargin = 0.4 // inches, Chrome's print default
+	MaxPDFMargin     = 2.0 // inches
+)
+
+// PDFOptions configures printing the rendered page to PDF (JS mode only)
+type PDFOptions struct {
+	Paper           string      `json:"paper"` // letter, legal, tabloid, a3, a4 or a5
+	Landscape       bool        `json:"landscape"`
+	PrintBackground bool        `json:"print_background"`
+	Margins         *PDFMargins `json:"margins"` // nil = 0.4in on every side
+}
+
+// PDFMargins are page margins in inches
+type PDFMargins struct {
+	Top    float64 `json:"top"`
+	Right  float64 `json:"right"`
+	Bottom float64 `json:"bottom"`
+	Left   float64 `json:"left"`
+}
+
+// ApplyDefaults sets default values for unset fields
+func (p *PDFOptions) ApplyDefaults() {
+	if p.Paper == "" {
+		p.Paper = DefaultPDFPaper
+	}
+	if p.Margins == nil {
+		p.Margins = &PDFMargins{Top: DefaultPDFMargin, Right: DefaultPDFMargin, Bottom: DefaultPDFMargin, Left: DefaultPDFMargin}
+	}
+}
+
+// Validate checks PDF options. Call ApplyDefaults first.
+func (p *PDFOptions) Validate() error {
+	if _, ok := PDFPaperSizes[p.Paper]; !ok {
+		return fmt.Errorf("invalid paper: %q (must be letter, legal, tabloid, a3, a4 or a5)", p.Paper)
+	}
+	if p.Margins != nil {
+		for _, m := range []float64{p.Margins.Top, p.Margins.Right, p.Margins.Bottom, p.Margins.Left} {
+			if m < 0 || m > MaxPDFMargin {
+				return fmt.Errorf("invalid margin: %g (must be 0-%g inches)", m, MaxPDFMargin)
+			}
+		}
+	}
+	return nil
+}
+
+// PaperSize returns the paper width and height in inches
+func (p *PDFOptions) PaperSize() (width, height float64) {
+	size := PDFPaperSizes[p.Paper]
+	return size[0], size[1]
+}
@@ -0,0 +1,55 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPDFOptions_ApplyDefaults(t *testing.T) {
+	opts := PDFOptions{}
+	opts.ApplyDefaults()
+
+	if opts.Paper != DefaultPDFPaper {
+		t.Errorf("Paper = %q, want %q", opts.Paper, DefaultPDFPaper)
+	}
+	if opts.Margins == nil || opts.Margins.Top != DefaultPDFMargin || opts.Margins.Left != DefaultPDFMargin {
+		t.Errorf("Margins = %+v, want %g on every side", opts.Margins, DefaultPDFMargin)
+	}
+
+	width, height := opts.PaperSize()
+	if width != 8.27 || height != 11.69 {
+		t.Errorf("PaperSize() = %g x %g, want a4", width, height)
+	}
+}
+
+func TestPDFOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    PDFOptions
+		wantErr string
+	}{
+		{name: "defaults", opts: PDFOptions{}},
+		{name: "letter landscape", opts: PDFOptions{Paper: "letter", Landscape: true, PrintBackground: true}},
+		{name: "zero margins", opts: PDFOptions{Margins: &PDFMargins{}}},
+		{name: "unknown paper", opts: PDFOptions{Paper: "b5"}, wantErr: "invalid paper"},
+		{name: "negative margin", opts: PDFOptions{Margins: &PDFMargins{Top: -1}}, wantErr: "invalid margin"},
+		{name: "margin too large", opts: PDFOptions{Margins: &PDFMargins{Left: MaxPDFMargin + 0.1}}, wantErr: "invalid margin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.ApplyDefaults()
+			err := opts.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package types
+
+import (
+	"net/url"
+	"strings"
+)
+
+// MaxRedirectHops is the maximum number of redirects followed in HTTP mode
+const MaxRedirectHops = 10
+
+// MaxRedirectRevisits is how often a followed chain may come back to the same
+// URL before it is stopped as a loop. One revisit allows the common
+// /a -> /b (sets a cookie) -> /a pattern.
+const MaxRedirectRevisits = 1
+
+// RedirectHop represents a single 3xx response in a redirect chain
+type RedirectHop struct {
+	URL            string  `json:"url"`
+	StatusCode     int     `json:"status_code"`
+	Location       string  `json:"location"` // Absolute redirect target
+	Time           float64 `json:"time"`     // seconds, from the hop's request to its 3xx response
+	ProtocolChange bool    `json:"protocol_change,omitempty"`
+	HostChange     bool    `json:"host_change,omitempty"`
+	Loop           bool    `json:"loop,omitempty"` // Location points back to a URL already in the chain
+}
+
+// RedirectFlags summarizes issues found in a redirect chain
+type RedirectFlags struct {
+	Loop           bool `json:"loop"`
+	MixedStatus    bool `json:"mixed_status"` // Both permanent (301/308) and temporary (302/303/307) hops
+	ProtocolChange bool `json:"protocol_change"`
+	HostChange     bool `json:"host_change"`
+}
+
+// AnnotateRedirectChain sets the per-hop flags on hops and returns the
+// chain-level summary. Returns nil for an empty chain.
+func AnnotateRedirectChain(hops []RedirectHop) *RedirectFlags {
+	if len(hops) == 0 {
+		return nil
+	}
+
+	flags := &RedirectFlags{}
+	hasPermanent, hasTemporary := false, false
+	visited := make(map[string]bool, len(hops))
+
+	for i := range hops {
+		hop := &hops[i]
+		visited[normalizeRedirectURL(hop.URL)] = true
+
+		from, errFrom := url.Parse(hop.URL)
+		to, errTo := url.Parse(hop.Location)
+		if errFrom == nil && errTo == nil {
+			hop.ProtocolChange = !strings.EqualFold(from.Scheme, to.Scheme)
+			hop.HostChange = !strings.EqualFold(from.Hostname(), to.Hostname())
+		}
+		hop.Loop = visited[normalizeRedirectURL(hop.Location)]
+
+		switch hop.StatusCode {
+		case 301, 308:
+			hasPermanent = true
+		case 302, 303, 307:
+			hasTemporary = true
+		}
+
+		flags.Loop = flags.Loop || hop.Loop
+		flags.ProtocolChange = flags.ProtocolChange || hop.ProtocolChange
+		flags.HostChange = flags.HostChange || hop.HostChange
+	}
+	flags.MixedStatus = hasPermanent && hasTemporary
+
+	return flags
+}
+
+// RedirectVisits counts the hops that already requested target
+func RedirectVisits(hops []RedirectHop, target string) int {
+	normalized := normalizeRedirectURL(target)
+	visits := 0
+	for _, hop := range hops {
+		if normalizeRedirectURL(hop.URL) == normalized {
+			visits++
+		}
+	}
+	return visits
+}
+
+// normalizeRedirectURL strips the fragment and lowercases scheme and host so
+// equivalent URLs compare equal
+func normalizeRedirectURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.Fragment = ""
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
@@ -0,0 +1,115 @@
+package types
+
+import "testing"
+
+func TestAnnotateRedirectChain(t *testing.T) {
+	tests := []struct {
+		name      string
+		hops      []RedirectHop
+		wantFlags *RedirectFlags
+		wantHops  []RedirectHop // only flag fields are compared
+	}{
+		{
+			name:      "empty chain",
+			hops:      nil,
+			wantFlags: nil,
+		},
+		{
+			name: "single same-host redirect",
+			hops: []RedirectHop{
+				{URL: "https://example.com/a", StatusCode: 301, Location: "https://example.com/b"},
+			},
+			wantFlags: &RedirectFlags{},
+			wantHops:  []RedirectHop{{}},
+		},
+		{
+			name: "http to https then www",
+			hops: []RedirectHop{
+				{URL: "http://example.com/", StatusCode: 301, Location: "https://example.com/"},
+				{URL: "https://example.com/", StatusCode: 301, Location: "https://www.example.com/"},
+			},
+			wantFlags: &RedirectFlags{ProtocolChange: true, HostChange: true},
+			wantHops: []RedirectHop{
+				{ProtocolChange: true},
+				{HostChange: true},
+			},
+		},
+		{
+			name: "mixed permanent and temporary",
+			hops: []RedirectHop{
+				{URL: "https://example.com/a", StatusCode: 301, Location: "https://example.com/b"},
+				{URL: "https://example.com/b", StatusCode: 302, Location: "https://example.com/c"},
+			},
+			wantFlags: &RedirectFlags{MixedStatus: true},
+			wantHops:  []RedirectHop{{}, {}},
+		},
+		{
+			name: "308 and 301 are not mixed",
+			hops: []RedirectHop{
+				{URL: "https://example.com/a", StatusCode: 308, Location: "https://example.com/b"},
+				{URL: "https://example.com/b", StatusCode: 301, Location: "https://example.com/c"},
+			},
+			wantFlags: &RedirectFlags{},
+			wantHops:  []RedirectHop{{}, {}},
+		},
+		{
+			name: "loop back to start",
+			hops: []RedirectHop{
+				{URL: "https://example.com/a", StatusCode: 302, Location: "https://example.com/b"},
+				{URL: "https://example.com/b", StatusCode: 302, Location: "https://EXAMPLE.com/a#top"},
+			},
+			wantFlags: &RedirectFlags{Loop: true},
+			wantHops:  []RedirectHop{{}, {Loop: true}},
+		},
+		{
+			name: "self redirect",
+			hops: []RedirectHop{
+				{URL: "https://example.com", StatusCode: 302, Location: "https://example.com/"},
+			},
+			wantFlags: &RedirectFlags{Loop: true},
+			wantHops:  []RedirectHop{{Loop: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AnnotateRedirectChain(tt.hops)
+			if (got == nil) != (tt.wantFlags == nil) {
+				t.Fatalf("AnnotateRedirectChain() = %v, want %v", got, tt.wantFlags)
+			}
+			if got != nil && *got != *tt.wantFlags {
+				t.Errorf("AnnotateRedirectChain() = %+v, want %+v", *got, *tt.wantFlags)
+			}
+			for i, want := range tt.wantHops {
+				hop := tt.hops[i]
+				if hop.ProtocolChange != want.ProtocolChange || hop.HostChange != want.HostChange || hop.Loop != want.Loop {
+					t.Errorf("hop %d flags = {protocol:%v host:%v loop:%v}, want {protocol:%v host:%v loop:%v}",
+						i, hop.ProtocolChange, hop.HostChange, hop.Loop,
+						want.ProtocolChange, want.HostChange, want.Loop)
+				}
+			}
+		})
+	}
+}
+
+func TestRedirectVisits(t *testing.T) {
+	hops := []RedirectHop{
+		{URL: "https://example.com/a", Location: "https://example.com/b"},
+		{URL: "https://example.com/b", Location: "https://EXAMPLE.com/a"},
+		{URL: "https://example.com/a#top", Location: "https://example.com/b"},
+	}
+
+	tests := []struct {
+		target string
+		want   int
+	}{
+		{target: "https://example.com/a", want: 2},
+		{target: "https://example.com/b", want: 1},
+		{target: "https://example.com/c", want: 0},
+	}
+	for _, tt := range tests {
+		if got := RedirectVisits(hops, tt.target); got != tt.want {
+			t.Errorf("RedirectVisits(%q) = %d, want %d", tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package types
+
+import "fmt"
+
+// Render modes for RenderRequest.RenderMode
+const (
+	RenderModeJS         = "js"          // Chrome with JavaScript
+	RenderModeHTTP       = "http"        // HTTP fetch parsed with goquery, no browser
+	RenderModeChromeNoJS = "chrome_nojs" // Chrome with script execution disabled
+)
+
+// ResolveRenderMode returns mode when set, else js or http from jsEnabled
+func ResolveRenderMode(mode string, jsEnabled bool) string {
+	switch {
+	case mode != "":
+		return mode
+	case jsEnabled:
+		return RenderModeJS
+	default:
+		return RenderModeHTTP
+	}
+}
+
+// ValidateRenderMode checks a render mode. Empty is valid.
+func ValidateRenderMode(mode string) error {
+	switch mode {
+	case "", RenderModeJS, RenderModeHTTP, RenderModeChromeNoJS:
+		return nil
+	}
+	return fmt.Errorf("invalid render_mode: %q (use js, http or chrome_nojs)", mode)
+}
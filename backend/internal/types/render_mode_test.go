@@ -0,0 +1,61 @@
+package types
+
+import "testing"
+
+func TestResolveRenderMode(t *testing.T) {
+	tests := []struct {
+		mode      string
+		jsEnabled bool
+		want      string
+	}{
+		{"", true, RenderModeJS},
+		{"", false, RenderModeHTTP},
+		{RenderModeChromeNoJS, false, RenderModeChromeNoJS},
+		{RenderModeChromeNoJS, true, RenderModeChromeNoJS},
+		{RenderModeHTTP, true, RenderModeHTTP},
+	}
+	for _, tt := range tests {
+		if got := ResolveRenderMode(tt.mode, tt.jsEnabled); got != tt.want {
+			t.Errorf("ResolveRenderMode(%q, %v) = %q, want %q", tt.mode, tt.jsEnabled, got, tt.want)
+		}
+	}
+}
+
+func TestValidateRenderMode(t *testing.T) {
+	for _, mode := range []string{"", RenderModeJS, RenderModeHTTP, RenderModeChromeNoJS} {
+		if err := ValidateRenderMode(mode); err != nil {
+			t.Errorf("ValidateRenderMode(%q) error = %v", mode, err)
+		}
+	}
+	for _, mode := range []string{"nojs", "chrome", "JS"} {
+		if err := ValidateRenderMode(mode); err == nil {
+			t.Errorf("ValidateRenderMode(%q) expected error", mode)
+		}
+	}
+}
+
+func TestExtCompareRequest_ToNonJSRenderRequest(t *testing.T) {
+	e := &ExtCompareRequest{
+		URL:               "https://example.com",
+		BlockAds:          true,
+		IncludeScreenshot: true,
+		Screenshot:        &ScreenshotOptions{Format: ScreenshotFormatJPEG},
+	}
+
+	req := e.ToNonJSRenderRequest()
+	if req.UsesChrome() || req.BlockAds || req.CaptureScreenshot {
+		t.Errorf("default non-JS side should be a plain HTTP fetch, got %+v", req)
+	}
+
+	e.NonJSRenderMode = RenderModeChromeNoJS
+	req = e.ToNonJSRenderRequest()
+	if req.Mode() != RenderModeChromeNoJS || !req.UsesChrome() {
+		t.Errorf("Mode() = %q, want chrome_nojs", req.Mode())
+	}
+	if !req.BlockAds || !req.CaptureScreenshot {
+		t.Error("chrome_nojs side should keep blocking and screenshot options")
+	}
+	if req.Screenshot == e.Screenshot {
+		t.Error("screenshot options should be copied")
+	}
+}
@@ -1,5 +1,14 @@
 package types
 
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // WaitEvent constants
 const (
 	WaitDOMContentLoaded  = "DOMContentLoaded"
@@ -8,6 +17,36 @@ const (
 	WaitNetworkAlmostIdle = "networkAlmostIdle"
 )
 
+// Condition wait events wait for page state instead of a lifecycle event:
+// "selector:<css>", "js:<expression>", "domStable" or "domStable:<ms>"
+const (
+	WaitSelectorPrefix = "selector:"
+	WaitJSPrefix       = "js:"
+	WaitDOMStable      = "domStable"
+)
+
+// Condition wait limits
+const (
+	MaxWaitConditionLength = 2000 // Max selector or expression length
+	DefaultDOMStableMs     = 500
+	MinDOMStableMs         = 50
+	MaxDOMStableMs         = 10000
+)
+
+// WaitCondition kinds
+const (
+	WaitKindSelector  = "selector"
+	WaitKindJS        = "js"
+	WaitKindDOMStable = "domStable"
+)
+
+// WaitCondition is a parsed condition wait event
+type WaitCondition struct {
+	Kind        string
+	Value       string        // CSS selector or JS expression
+	QuietPeriod time.Duration // Time without DOM mutations for domStable
+}
+
 // UserAgent preset constants
 const (
 	UserAgentChrome          = "chrome"
@@ -57,27 +96,81 @@ const (
 	MaxTimeout       = 60
 )
 
+// Custom header and cookie limits
+const (
+	MaxCustomHeaders = 50
+	MaxCustomCookies = 50
+)
+
+// forbiddenHeaders are headers that cannot be overridden by the caller
+// because they are controlled by the HTTP client or browser
+var forbiddenHeaders = map[string]bool{
+	"Host":              true,
+	"Content-Length":    true,
+	"Connection":        true,
+	"Transfer-Encoding": true,
+	"Upgrade":           true,
+	"Te":                true,
+	"Trailer":           true,
+	"Keep-Alive":        true,
+	"Proxy-Connection":  true,
+}
+
+// Cookie represents a cookie sent with the render or fetch request.
+// Domain defaults to the target URL host and Path defaults to "/".
+type Cookie struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Domain string `json:"domain,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
 // RenderRequest represents an API request to render a page
 type RenderRequest struct {
-	RequestID         string   `json:"request_id"`
-	URL               string   `json:"url"`
-	JSEnabled         bool     `json:"js_enabled"`
-	FollowRedirects   *bool    `json:"follow_redirects,omitempty"` // default true
-	UserAgent         string   `json:"user_agent,omitempty"`
-	Timeout           int      `json:"timeout,omitempty"`
-	WaitEvent         string   `json:"wait_event,omitempty"`
-	BlockAnalytics    bool     `json:"block_analytics,omitempty"`
-	BlockAds          bool     `json:"block_ads,omitempty"`
-	BlockSocial       bool     `json:"block_social,omitempty"`
-	BlockedTypes      []string `json:"blocked_types,omitempty"`
-	CaptureScreenshot bool     `json:"-"` // Internal only, not JSON-exposed
-	SessionToken      string   `json:"session_token,omitempty"`
+	RequestID         string               `json:"request_id"`
+	URL               string               `json:"url"`
+	JSEnabled         bool                 `json:"js_enabled"`
+	RenderMode        string               `json:"render_mode,omitempty"`      // js, http or chrome_nojs, overrides js_enabled
+	FollowRedirects   *bool                `json:"follow_redirects,omitempty"` // default true
+	UserAgent         string               `json:"user_agent,omitempty"`
+	Device            string               `json:"device,omitempty"`              // Device profile, JS mode viewport
+	ViewportWidth     int                  `json:"viewport_width,omitempty"`      // JS mode only, overrides the device
+	ViewportHeight    int                  `json:"viewport_height,omitempty"`     // JS mode only, overrides the device
+	DeviceScaleFactor float64              `json:"device_scale_factor,omitempty"` // JS mode only, overrides the device
+	Locale            string               `json:"locale,omitempty"`              // BCP 47, also sets Accept-Language
+	Timezone          string               `json:"timezone,omitempty"`            // IANA name, JS mode only
+	Geolocation       *Geolocation         `json:"geolocation,omitempty"`         // JS mode only
+	Timeout           int                  `json:"timeout,omitempty"`
+	WaitEvent         string               `json:"wait_event,omitempty"`
+	BlockAnalytics    bool                 `json:"block_analytics,omitempty"`
+	BlockAds          bool                 `json:"block_ads,omitempty"`
+	BlockSocial       bool                 `json:"block_social,omitempty"`
+	BlockedTypes      []string             `json:"blocked_types,omitempty"`
+	Headers           map[string]string    `json:"headers,omitempty"`
+	Cookies           []Cookie             `json:"cookies,omitempty"`
+	Actions           []Action             `json:"actions,omitempty"`                 // JS mode only
+	ScrollToBottom    *ScrollOptions       `json:"scroll_to_bottom,omitempty"`        // JS mode only
+	Screenshot        *ScreenshotOptions   `json:"screenshot,omitempty"`              // nil = viewport PNG
+	PDF               *PDFOptions          `json:"pdf,omitempty"`                     // JS mode only, nil = no PDF
+	HAR               *HAROptions          `json:"har,omitempty"`                     // JS mode only, nil = no HAR
+	Snapshots         *SnapshotOptions     `json:"snapshots,omitempty"`               // JS mode only, nil = no DOM snapshots
+	ResponseBodies    *ResponseBodyOptions `json:"capture_response_bodies,omitempty"` // JS mode only, nil = no bodies
+	DialogAction      string               `json:"dialog_action,omitempty"`           // JS mode only, accept or dismiss
+	DialogPromptText  string               `json:"dialog_prompt_text,omitempty"`      // Answer to prompt() when accepting
+	Permissions       []string             `json:"permissions,omitempty"`             // JS mode only, granted, others denied
+	WebVitals         bool                 `json:"web_vitals,omitempty"`              // JS mode only
+	NetworkProfile    string               `json:"network_profile,omitempty"`         // JS mode only
+	NetworkConditions *NetworkConditions   `json:"network_conditions,omitempty"`      // Used with network_profile "custom"
+	CPUThrottlingRate float64              `json:"cpu_throttling_rate,omitempty"`     // JS mode only, 1-20
+	CaptureScreenshot bool                 `json:"-"`                                 // Internal only, not JSON-exposed
+	SessionToken      string               `json:"session_token,omitempty"`
 }
 
 // ExtRenderRequest represents an external API request with content inclusion options
 type ExtRenderRequest struct {
 	URL             string   `json:"url"`
 	JSEnabled       bool     `json:"js_enabled"`
+	RenderMode      string   `json:"render_mode"` // js, http or chrome_nojs, overrides js_enabled
 	FollowRedirects *bool    `json:"follow_redirects,omitempty"`
 	UserAgent       string   `json:"user_agent"`
 	Timeout         int      `json:"timeout"`
@@ -87,6 +180,33 @@ type ExtRenderRequest struct {
 	BlockSocial     bool     `json:"block_social"`
 	BlockedTypes    []string `json:"blocked_types"`
 
+	Headers map[string]string `json:"headers"`
+	Cookies []Cookie          `json:"cookies"`
+	Actions []Action          `json:"actions"` // JS mode only
+
+	ScrollToBottom *ScrollOptions `json:"scroll_to_bottom"` // JS mode only, nil = no scrolling
+
+	// Device emulation (the profile user agent also applies in HTTP mode)
+	Device            string  `json:"device"`
+	ViewportWidth     int     `json:"viewport_width"`
+	ViewportHeight    int     `json:"viewport_height"`
+	DeviceScaleFactor float64 `json:"device_scale_factor"`
+
+	// JavaScript dialogs and permission prompts (JS mode only)
+	DialogAction     string   `json:"dialog_action"`
+	DialogPromptText string   `json:"dialog_prompt_text"`
+	Permissions      []string `json:"permissions"`
+
+	// Locale emulation (locale also sets Accept-Language in HTTP mode)
+	Locale      string       `json:"locale"`
+	Timezone    string       `json:"timezone"`    // JS mode only
+	Geolocation *Geolocation `json:"geolocation"` // JS mode only
+
+	// Network and CPU throttling (JS mode only)
+	NetworkProfile    string             `json:"network_profile"`
+	NetworkConditions *NetworkConditions `json:"network_conditions"` // Used with network_profile "custom"
+	CPUThrottlingRate float64            `json:"cpu_throttling_rate"`
+
 	IncludeHTML           bool `json:"include_html"`
 	IncludeText           bool `json:"include_text"`
 	IncludeMarkdown       bool `json:"include_markdown"`
@@ -96,9 +216,86 @@ type ExtRenderRequest struct {
 	IncludeStructuredData bool `json:"include_structured_data"`
 	IncludeScreenshot     bool `json:"include_screenshot"`
 
+	Screenshot *ScreenshotOptions `json:"screenshot"` // Used with include_screenshot, nil = viewport PNG
+
+	IncludePDF bool        `json:"include_pdf"` // JS mode only
+	PDF        *PDFOptions `json:"pdf"`         // Used with include_pdf, nil = defaults
+
+	IncludeHAR bool        `json:"include_har"` // JS mode only
+	HAR        *HAROptions `json:"har"`         // Used with include_har, nil = defaults
+
+	IncludeWebVitals bool `json:"include_web_vitals"` // JS mode only
+
+	IncludeSnapshots bool             `json:"include_snapshots"` // JS mode only
+	Snapshots        *SnapshotOptions `json:"snapshots"`         // Used with include_snapshots, nil = defaults
+
+	// XHR and fetch response bodies, attached to network entries (JS mode only)
+	ResponseBodies *ResponseBodyOptions `json:"capture_response_bodies"`
+
+	// Diagnostics (JS mode only)
+	IncludeNetwork    bool     `json:"include_network"`
+	NetworkTypes      []string `json:"network_types"`       // Resource types to keep, empty = all
+	NetworkFailedOnly bool     `json:"network_failed_only"` // Keep failed, blocked and 4xx/5xx requests only
+	IncludeConsole    bool     `json:"include_console"`
+	ConsoleMinLevel   string   `json:"console_min_level"` // debug, info, warning, error
+	IncludeJSErrors   bool     `json:"include_js_errors"`
+	IncludeLifecycle  bool     `json:"include_lifecycle"`
+
 	MaxContentLength int `json:"max_content_length"`
 }
 
+// Network resource types accepted by ExtRenderRequest.NetworkTypes
+var validNetworkTypes = map[string]bool{
+	"document": true, "stylesheet": true, "image": true, "media": true, "font": true,
+	"script": true, "texttrack": true, "xhr": true, "fetch": true, "prefetch": true,
+	"eventsource": true, "websocket": true, "manifest": true, "signedexchange": true,
+	"ping": true, "cspviolationreport": true, "preflight": true, "other": true,
+}
+
+// Console level ranks used by ExtRenderRequest.ConsoleMinLevel
+var consoleLevelRanks = map[string]int{
+	"debug":   0,
+	"info":    1,
+	"log":     1,
+	"warning": 2,
+	"warn":    2,
+	"error":   3,
+}
+
+// ConsoleLevelRank returns the severity rank of a console message level.
+// Levels other than debug, warning and error (log, info, table, trace...)
+// rank as info.
+func ConsoleLevelRank(level string) int {
+	if rank, ok := consoleLevelRanks[strings.ToLower(level)]; ok {
+		return rank
+	}
+	if strings.EqualFold(level, "assert") {
+		return consoleLevelRanks["error"]
+	}
+	return consoleLevelRanks["info"]
+}
+
+// ValidateDiagnostics checks the network and console filter options
+func (e *ExtRenderRequest) ValidateDiagnostics() error {
+	for _, t := range e.NetworkTypes {
+		if !validNetworkTypes[strings.ToLower(t)] {
+			return fmt.Errorf("invalid network type: %q", t)
+		}
+	}
+	if e.ConsoleMinLevel != "" {
+		if _, ok := consoleLevelRanks[strings.ToLower(e.ConsoleMinLevel)]; !ok {
+			return fmt.Errorf("invalid console level: %q (use debug, info, warning or error)", e.ConsoleMinLevel)
+		}
+	}
+	return nil
+}
+
+// UsesChrome reports whether the request renders in Chrome (js or
+// chrome_nojs mode)
+func (e *ExtRenderRequest) UsesChrome() bool {
+	return ResolveRenderMode(e.RenderMode, e.JSEnabled) != RenderModeHTTP
+}
+
 // ToRenderRequest converts an ExtRenderRequest to a RenderRequest
 func (e *ExtRenderRequest) ToRenderRequest() *RenderRequest {
 	followRedirects := true
@@ -108,16 +305,71 @@ func (e *ExtRenderRequest) ToRenderRequest() *RenderRequest {
 	req := &RenderRequest{
 		URL:               e.URL,
 		JSEnabled:         e.JSEnabled,
+		RenderMode:        e.RenderMode,
 		FollowRedirects:   &followRedirects,
 		UserAgent:         e.UserAgent,
+		Device:            e.Device,
+		ViewportWidth:     e.ViewportWidth,
+		ViewportHeight:    e.ViewportHeight,
+		DeviceScaleFactor: e.DeviceScaleFactor,
+		Locale:            e.Locale,
+		DialogAction:      e.DialogAction,
+		DialogPromptText:  e.DialogPromptText,
+		Permissions:       e.Permissions,
+		Timezone:          e.Timezone,
 		Timeout:           e.Timeout,
 		WaitEvent:         e.WaitEvent,
 		BlockAnalytics:    e.BlockAnalytics,
 		BlockAds:          e.BlockAds,
 		BlockSocial:       e.BlockSocial,
 		BlockedTypes:      e.BlockedTypes,
+		Headers:           e.Headers,
+		Cookies:           e.Cookies,
+		Actions:           e.Actions,
+		WebVitals:         e.IncludeWebVitals,
+		NetworkProfile:    e.NetworkProfile,
+		CPUThrottlingRate: e.CPUThrottlingRate,
 		CaptureScreenshot: e.IncludeScreenshot,
 	}
+	// Copied so ApplyDefaults does not modify options shared by batch items
+	if e.ScrollToBottom != nil {
+		scroll := *e.ScrollToBottom
+		req.ScrollToBottom = &scroll
+	}
+	if e.Geolocation != nil {
+		geolocation := *e.Geolocation
+		req.Geolocation = &geolocation
+	}
+	if e.NetworkConditions != nil {
+		conditions := *e.NetworkConditions
+		req.NetworkConditions = &conditions
+	}
+	if e.ResponseBodies != nil {
+		bodies := *e.ResponseBodies
+		req.ResponseBodies = &bodies
+	}
+	if e.Screenshot != nil {
+		screenshot := *e.Screenshot
+		req.Screenshot = &screenshot
+	}
+	if e.IncludePDF {
+		req.PDF = &PDFOptions{}
+		if e.PDF != nil {
+			*req.PDF = *e.PDF
+		}
+	}
+	if e.IncludeHAR {
+		req.HAR = &HAROptions{}
+		if e.HAR != nil {
+			*req.HAR = *e.HAR
+		}
+	}
+	if e.IncludeSnapshots {
+		req.Snapshots = &SnapshotOptions{}
+		if e.Snapshots != nil {
+			*req.Snapshots = *e.Snapshots
+		}
+	}
 	return req
 }
 
@@ -129,6 +381,51 @@ func (r *RenderRequest) ShouldFollowRedirects() bool {
 	return *r.FollowRedirects
 }
 
+// Mode returns the render mode: render_mode when set, else js or http from
+// js_enabled
+func (r *RenderRequest) Mode() string {
+	return ResolveRenderMode(r.RenderMode, r.JSEnabled)
+}
+
+// UsesChrome reports whether the request renders in Chrome (js or
+// chrome_nojs mode)
+func (r *RenderRequest) UsesChrome() bool {
+	return r.Mode() != RenderModeHTTP
+}
+
+// Throttling returns the resolved network and CPU throttling, nil when none
+// was requested
+func (r *RenderRequest) Throttling() (*Throttling, error) {
+	return ResolveThrottling(r.NetworkProfile, r.NetworkConditions, r.CPUThrottlingRate)
+}
+
+// ResolvedUserAgent returns the full user agent string: the user_agent preset
+// or custom value, else the device profile's browser, else the default
+func (r *RenderRequest) ResolvedUserAgent() string {
+	if r.UserAgent == "" {
+		if profile, ok := DeviceProfiles[r.Device]; ok {
+			return profile.UserAgent
+		}
+	}
+	return ResolveUserAgent(r.UserAgent)
+}
+
+// DeviceEmulation returns the emulated device for a JS render. Without a
+// device profile, mobile selects the phone viewport. Platform hints are
+// dropped when user_agent replaces the profile's browser.
+func (r *RenderRequest) DeviceEmulation(mobile bool) (*Device, error) {
+	device, err := ResolveDevice(r.Device, r.ViewportWidth, r.ViewportHeight, r.DeviceScaleFactor, mobile)
+	if err != nil {
+		return nil, err
+	}
+	if device.UserAgent != r.ResolvedUserAgent() {
+		device.UserAgent = ""
+		device.Platform = ""
+		device.ClientHints = nil
+	}
+	return device, nil
+}
+
 // ResolveUserAgent returns the full user agent string for a preset or the custom value
 func ResolveUserAgent(preset string) string {
 	if preset == "" {
@@ -145,12 +442,45 @@ func IsValidWaitEvent(event string) bool {
 	if event == "" {
 		return true
 	}
-	return ValidWaitEvents[event]
+	if ValidWaitEvents[event] {
+		return true
+	}
+	_, ok := ParseWaitCondition(event)
+	return ok
+}
+
+// ParseWaitCondition parses a condition wait event. Returns false for
+// lifecycle events and malformed conditions.
+func ParseWaitCondition(event string) (WaitCondition, bool) {
+	switch {
+	case strings.HasPrefix(event, WaitSelectorPrefix):
+		return parseWaitValue(WaitKindSelector, strings.TrimPrefix(event, WaitSelectorPrefix))
+	case strings.HasPrefix(event, WaitJSPrefix):
+		return parseWaitValue(WaitKindJS, strings.TrimPrefix(event, WaitJSPrefix))
+	case event == WaitDOMStable:
+		return WaitCondition{Kind: WaitKindDOMStable, QuietPeriod: DefaultDOMStableMs * time.Millisecond}, true
+	case strings.HasPrefix(event, WaitDOMStable+":"):
+		ms, err := strconv.Atoi(strings.TrimPrefix(event, WaitDOMStable+":"))
+		if err != nil || ms < MinDOMStableMs || ms > MaxDOMStableMs {
+			return WaitCondition{}, false
+		}
+		return WaitCondition{Kind: WaitKindDOMStable, QuietPeriod: time.Duration(ms) * time.Millisecond}, true
+	}
+	return WaitCondition{}, false
+}
+
+// parseWaitValue validates the selector or expression of a condition wait event
+func parseWaitValue(kind, value string) (WaitCondition, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || len(value) > MaxWaitConditionLength {
+		return WaitCondition{}, false
+	}
+	return WaitCondition{Kind: kind, Value: value}, true
 }
 
 // ApplyDefaults applies default values to a RenderRequest
 func (r *RenderRequest) ApplyDefaults() {
-	if r.UserAgent == "" {
+	if r.UserAgent == "" && r.Device == "" {
 		r.UserAgent = DefaultUserAgent
 	}
 	if r.Timeout == 0 {
@@ -159,9 +489,120 @@ func (r *RenderRequest) ApplyDefaults() {
 	if r.WaitEvent == "" {
 		r.WaitEvent = DefaultWaitEvent
 	}
+	if r.DialogAction == "" {
+		r.DialogAction = DefaultDialogAction
+	}
+	if r.ScrollToBottom != nil {
+		r.ScrollToBottom.ApplyDefaults()
+	}
+	if r.Screenshot != nil {
+		r.Screenshot.ApplyDefaults()
+	}
+	if r.PDF != nil {
+		r.PDF.ApplyDefaults()
+	}
+	if r.Geolocation != nil {
+		r.Geolocation.ApplyDefaults()
+	}
+	if r.ResponseBodies != nil {
+		r.ResponseBodies.ApplyDefaults()
+	}
+	if r.Snapshots != nil {
+		r.Snapshots.ApplyDefaults()
+	}
 }
 
 // ValidateTimeout checks if the timeout is within valid range
 func (r *RenderRequest) ValidateTimeout() bool {
 	return r.Timeout >= MinTimeout && r.Timeout <= MaxTimeout
 }
+
+// ValidateHeaders checks custom headers for valid names and values, rejects
+// headers controlled by the client (Host, Content-Length, hop-by-hop headers)
+// and enforces MaxCustomHeaders
+func (r *RenderRequest) ValidateHeaders() error {
+	if len(r.Headers) > MaxCustomHeaders {
+		return fmt.Errorf("too many headers (max %d)", MaxCustomHeaders)
+	}
+	for name, value := range r.Headers {
+		if !isValidHeaderName(name) {
+			return fmt.Errorf("invalid header name: %q", name)
+		}
+		if forbiddenHeaders[http.CanonicalHeaderKey(name)] {
+			return fmt.Errorf("header not allowed: %s", http.CanonicalHeaderKey(name))
+		}
+		if strings.ContainsAny(value, "\r\n\x00") {
+			return fmt.Errorf("invalid value for header %s", http.CanonicalHeaderKey(name))
+		}
+	}
+	return nil
+}
+
+// ValidateCookies checks custom cookies for valid names and values and
+// enforces MaxCustomCookies
+func (r *RenderRequest) ValidateCookies() error {
+	if len(r.Cookies) > MaxCustomCookies {
+		return fmt.Errorf("too many cookies (max %d)", MaxCustomCookies)
+	}
+	for _, c := range r.Cookies {
+		if !isValidHeaderName(c.Name) {
+			return fmt.Errorf("invalid cookie name: %q", c.Name)
+		}
+		if strings.ContainsAny(c.Value, ";\r\n\x00") {
+			return fmt.Errorf("invalid value for cookie %s", c.Name)
+		}
+		if c.Path != "" && !strings.HasPrefix(c.Path, "/") {
+			return fmt.Errorf("cookie %s path must start with /", c.Name)
+		}
+	}
+	return nil
+}
+
+// isValidHeaderName reports whether name is a non-empty RFC 7230 token
+func isValidHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if c >= 0x7f || c <= ' ' || strings.ContainsRune("()<>@,;:\\\"/[]?={}", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// CustomHeadersAllowed reports whether the custom headers of a request for
+// target may be sent to requestURL: same scheme, host and port, or the same
+// host upgraded from http to https on the default ports
+func CustomHeadersAllowed(target, requestURL string) bool {
+	t, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return false
+	}
+	if !strings.EqualFold(t.Hostname(), u.Hostname()) {
+		return false
+	}
+	ts, us := strings.ToLower(t.Scheme), strings.ToLower(u.Scheme)
+	if ts == us {
+		return originPort(t) == originPort(u)
+	}
+	return ts == "http" && us == "https" && originPort(t) == "80" && originPort(u) == "443"
+}
+
+// originPort returns the URL port, or the scheme's default port
+func originPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "ws":
+		return "80"
+	case "https", "wss":
+		return "443"
+	}
+	return ""
+}
@@ -1,7 +1,10 @@
 package types
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestResolveUserAgent(t *testing.T) {
@@ -103,6 +106,46 @@ func TestIsValidWaitEvent(t *testing.T) {
 			event:    "domcontentloaded",
 			expected: false,
 		},
+		{
+			name:     "selector condition is valid",
+			event:    "selector:#app .product",
+			expected: true,
+		},
+		{
+			name:     "js condition is valid",
+			event:    "js:window.__APP_READY__ === true",
+			expected: true,
+		},
+		{
+			name:     "domStable is valid",
+			event:    WaitDOMStable,
+			expected: true,
+		},
+		{
+			name:     "domStable with quiet period is valid",
+			event:    "domStable:1000",
+			expected: true,
+		},
+		{
+			name:     "empty selector is invalid",
+			event:    "selector:  ",
+			expected: false,
+		},
+		{
+			name:     "empty js expression is invalid",
+			event:    "js:",
+			expected: false,
+		},
+		{
+			name:     "domStable quiet period out of range",
+			event:    "domStable:60000",
+			expected: false,
+		},
+		{
+			name:     "domStable quiet period not a number",
+			event:    "domStable:soon",
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -115,6 +158,28 @@ func TestIsValidWaitEvent(t *testing.T) {
 	}
 }
 
+func TestParseWaitCondition(t *testing.T) {
+	tests := []struct {
+		event string
+		want  WaitCondition
+		ok    bool
+	}{
+		{"selector: main h1 ", WaitCondition{Kind: WaitKindSelector, Value: "main h1"}, true},
+		{"js:document.title !== ''", WaitCondition{Kind: WaitKindJS, Value: "document.title !== ''"}, true},
+		{"domStable", WaitCondition{Kind: WaitKindDOMStable, QuietPeriod: DefaultDOMStableMs * time.Millisecond}, true},
+		{"domStable:250", WaitCondition{Kind: WaitKindDOMStable, QuietPeriod: 250 * time.Millisecond}, true},
+		{"selector:" + strings.Repeat("a", MaxWaitConditionLength+1), WaitCondition{}, false},
+		{WaitNetworkIdle, WaitCondition{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseWaitCondition(tt.event)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseWaitCondition(%q) = %+v, %v, want %+v, %v", tt.event, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
 func TestRenderRequest_ApplyDefaults(t *testing.T) {
 	t.Run("applies defaults to empty request", func(t *testing.T) {
 		req := &RenderRequest{}
@@ -199,3 +264,129 @@ func TestRenderRequest_ValidateTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderRequest_ValidateHeaders(t *testing.T) {
+	tooMany := make(map[string]string)
+	for i := 0; i <= MaxCustomHeaders; i++ {
+		tooMany[fmt.Sprintf("X-Header-%d", i)] = "v"
+	}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		wantErr bool
+	}{
+		{name: "nil headers", headers: nil, wantErr: false},
+		{name: "valid headers", headers: map[string]string{"Authorization": "Bearer x", "X-AB-Test": "b"}, wantErr: false},
+		{name: "empty name", headers: map[string]string{"": "v"}, wantErr: true},
+		{name: "name with space", headers: map[string]string{"X Bad": "v"}, wantErr: true},
+		{name: "host not allowed", headers: map[string]string{"host": "evil.com"}, wantErr: true},
+		{name: "content-length not allowed", headers: map[string]string{"Content-Length": "1"}, wantErr: true},
+		{name: "CRLF in value", headers: map[string]string{"X-Test": "a\r\nX-Injected: 1"}, wantErr: true},
+		{name: "too many headers", headers: tooMany, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &RenderRequest{Headers: tt.headers}
+			err := req.ValidateHeaders()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateHeaders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderRequest_ValidateCookies(t *testing.T) {
+	tests := []struct {
+		name    string
+		cookies []Cookie
+		wantErr bool
+	}{
+		{name: "nil cookies", cookies: nil, wantErr: false},
+		{name: "valid cookie", cookies: []Cookie{{Name: "session", Value: "abc", Domain: "example.com", Path: "/"}}, wantErr: false},
+		{name: "empty name", cookies: []Cookie{{Name: "", Value: "abc"}}, wantErr: true},
+		{name: "semicolon in value", cookies: []Cookie{{Name: "a", Value: "b; c=d"}}, wantErr: true},
+		{name: "relative path", cookies: []Cookie{{Name: "a", Value: "b", Path: "admin"}}, wantErr: true},
+		{name: "too many cookies", cookies: make([]Cookie, MaxCustomCookies+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &RenderRequest{Cookies: tt.cookies}
+			err := req.ValidateCookies()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCookies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExtRenderRequest_ValidateDiagnostics(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     ExtRenderRequest
+		wantErr bool
+	}{
+		{name: "no filters", req: ExtRenderRequest{}, wantErr: false},
+		{name: "valid network types", req: ExtRenderRequest{NetworkTypes: []string{"script", "XHR", "Fetch"}}, wantErr: false},
+		{name: "unknown network type", req: ExtRenderRequest{NetworkTypes: []string{"script", "video"}}, wantErr: true},
+		{name: "valid console level", req: ExtRenderRequest{ConsoleMinLevel: "warning"}, wantErr: false},
+		{name: "console level alias", req: ExtRenderRequest{ConsoleMinLevel: "WARN"}, wantErr: false},
+		{name: "unknown console level", req: ExtRenderRequest{ConsoleMinLevel: "fatal"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.ValidateDiagnostics()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDiagnostics() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConsoleLevelRank(t *testing.T) {
+	tests := []struct {
+		level string
+		want  int
+	}{
+		{"debug", 0},
+		{"log", 1},
+		{"info", 1},
+		{"table", 1},
+		{"warning", 2},
+		{"error", 3},
+		{"assert", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			if got := ConsoleLevelRank(tt.level); got != tt.want {
+				t.Errorf("ConsoleLevelRank(%q) = %d, want %d", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCustomHeadersAllowed(t *testing.T) {
+	tests := []struct {
+		target, requestURL string
+		want               bool
+	}{
+		{"https://example.com/page", "https://example.com/app.js", true},
+		{"https://Example.com/", "https://example.com:443/", true},
+		{"http://example.com/", "http://example.com:80/x", true},
+		{"http://example.com/", "https://example.com/", true}, // Upgrade to https
+		{"http://example.com:8080/", "https://example.com/", false},
+		{"https://example.com/", "http://example.com/", false}, // Downgrade
+		{"https://example.com/", "https://cdn.example.com/", false},
+		{"http://127.0.0.1:8080/", "http://127.0.0.1:9090/", false},
+		{"https://example.com/", "data:text/plain,hi", false},
+	}
+	for _, tt := range tests {
+		if got := CustomHeadersAllowed(tt.target, tt.requestURL); got != tt.want {
+			t.Errorf("CustomHeadersAllowed(%q, %q) = %v, want %v", tt.target, tt.requestURL, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package types
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 )
 
 // Error codes
@@ -25,13 +26,42 @@ const (
 	ErrMethodNotAllowed     = "METHOD_NOT_ALLOWED"
 	ErrInvalidRequestBody   = "INVALID_REQUEST_BODY"
 	ErrSSRFBlocked          = "SSRF_BLOCKED"
+	ErrInvalidHeaders       = "INVALID_HEADERS"
+	ErrInvalidCookies       = "INVALID_COOKIES"
+	ErrInvalidFilter        = "INVALID_FILTER"
+	ErrInvalidBatch         = "INVALID_BATCH"
+	ErrBatchNotFound        = "BATCH_NOT_FOUND"
+	ErrBatchQueueFull       = "BATCH_QUEUE_FULL"
+	ErrBatchResultTooLarge  = "BATCH_RESULT_TOO_LARGE"
+	ErrInvalidActions       = "INVALID_ACTIONS"
+	ErrInvalidScroll        = "INVALID_SCROLL"
+	ErrInvalidScreenshot    = "INVALID_SCREENSHOT"
+	ErrInvalidPDF           = "INVALID_PDF"
+	ErrInvalidThrottling    = "INVALID_THROTTLING"
+	ErrInvalidDevice        = "INVALID_DEVICE"
+	ErrInvalidLocale        = "INVALID_LOCALE"
+	ErrInvalidGeolocation   = "INVALID_GEOLOCATION"
+	ErrInvalidBodyCapture   = "INVALID_BODY_CAPTURE"
+	ErrInvalidDialogs       = "INVALID_DIALOGS"
+	ErrInvalidPermissions   = "INVALID_PERMISSIONS"
+	ErrInvalidRenderMode    = "INVALID_RENDER_MODE"
+	ErrInvalidSnapshots     = "INVALID_SNAPSHOTS"
+	ErrInvalidCrawl         = "INVALID_CRAWL"
+	ErrCrawlNotFound        = "CRAWL_NOT_FOUND"
+	ErrCrawlQueueFull       = "CRAWL_QUEUE_FULL"
 )
 
 // ErrorCodeToHTTPStatus maps an error code to the appropriate HTTP status code.
 func ErrorCodeToHTTPStatus(code string) int {
 	switch code {
-	case ErrInvalidURL, ErrInvalidTimeout, ErrInvalidWaitEvent, ErrDomainNotFound, ErrInvalidRequestBody:
+	case ErrInvalidURL, ErrInvalidTimeout, ErrInvalidWaitEvent, ErrDomainNotFound, ErrInvalidRequestBody,
+		ErrInvalidHeaders, ErrInvalidCookies, ErrInvalidFilter, ErrInvalidActions, ErrInvalidScroll, ErrInvalidScreenshot,
+		ErrInvalidPDF, ErrInvalidThrottling, ErrInvalidDevice, ErrInvalidLocale, ErrInvalidGeolocation, ErrInvalidBodyCapture,
+		ErrInvalidDialogs, ErrInvalidPermissions, ErrInvalidRenderMode, ErrInvalidSnapshots,
+		ErrInvalidBatch, ErrInvalidCrawl:
 		return http.StatusBadRequest
+	case ErrBatchNotFound, ErrCrawlNotFound:
+		return http.StatusNotFound
 	case ErrAPIKeyRequired:
 		return http.StatusUnauthorized
 	case ErrAPIKeyInvalid, ErrSessionTokenRequired, ErrSessionTokenInvalid, ErrSessionTokenExpired, ErrSSRFBlocked:
@@ -40,7 +70,7 @@ func ErrorCodeToHTTPStatus(code string) int {
 		return http.StatusMethodNotAllowed
 	case ErrRenderTimeout:
 		return http.StatusRequestTimeout
-	case ErrChromeUnavailable, ErrPoolExhausted, ErrPoolShuttingDown:
+	case ErrChromeUnavailable, ErrPoolExhausted, ErrPoolShuttingDown, ErrBatchQueueFull, ErrCrawlQueueFull:
 		return http.StatusServiceUnavailable
 	default:
 		return http.StatusInternalServerError
@@ -71,17 +101,19 @@ type Link struct {
 	IsSocial    bool   `json:"is_social"`
 	IsUgc       bool   `json:"is_ugc"`
 	IsSponsored bool   `json:"is_sponsored"`
+	AfterScroll bool   `json:"after_scroll,omitempty"` // Only present after scroll_to_bottom
 }
 
 // Image represents an image extracted from the page
 type Image struct {
-	Src        string `json:"src"` // Always resolved to absolute URL
-	Alt        string `json:"alt"`
-	IsExternal bool   `json:"is_external"`
-	IsAbsolute bool   `json:"is_absolute"` // Was original src absolute?
-	IsInLink   bool   `json:"is_in_link"`
-	LinkHref   string `json:"link_href,omitempty"`
-	Size       int    `json:"size"` // bytes from network request, 0 if not found
+	Src         string `json:"src"` // Always resolved to absolute URL
+	Alt         string `json:"alt"`
+	IsExternal  bool   `json:"is_external"`
+	IsAbsolute  bool   `json:"is_absolute"` // Was original src absolute?
+	IsInLink    bool   `json:"is_in_link"`
+	LinkHref    string `json:"link_href,omitempty"`
+	Size        int    `json:"size"`                   // bytes from network request, 0 if not found
+	AfterScroll bool   `json:"after_scroll,omitempty"` // Only present or loaded after scroll_to_bottom
 }
 
 // RenderResponse represents the API response
@@ -101,9 +133,18 @@ type RenderData struct {
 	PageSizeBytes int     `json:"page_size_bytes"`
 	RenderTime    float64 `json:"render_time"`
 	ScreenshotID  string  `json:"screenshot_id,omitempty"`
+	PDFID         string  `json:"pdf_id,omitempty"` // Served by GET /api/pdf/{id}
+	HARID         string  `json:"har_id,omitempty"` // Served by GET /api/har/{id}
 	MetaRobots    string  `json:"meta_robots,omitempty"`
 	XRobotsTag    string  `json:"x_robots_tag,omitempty"`
 
+	// Main document response headers (canonical keys, repeated headers joined by JoinHeaderValues)
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+
+	// Redirect chain (one hop per 3xx response of the main document)
+	RedirectChain []RedirectHop  `json:"redirect_chain,omitempty"`
+	RedirectFlags *RedirectFlags `json:"redirect_flags,omitempty"`
+
 	// Robots directives (parsed)
 	MetaIndexable bool `json:"meta_indexable"`
 	MetaFollow    bool `json:"meta_follow"`
@@ -136,9 +177,33 @@ type RenderData struct {
 	// Network information
 	Requests []NetworkRequest `json:"requests,omitempty"`
 
+	// Main document request (final redirect hop) with its timing breakdown
+	Document *NetworkRequest `json:"document,omitempty"`
+
 	// Lifecycle timing
 	Lifecycle []LifecycleEvent `json:"lifecycle,omitempty"`
 
+	// Scripted page action results (JS mode only)
+	Actions []ActionResult `json:"actions,omitempty"`
+
+	// Scroll-to-bottom summary (JS mode only)
+	Scroll *ScrollResult `json:"scroll,omitempty"`
+
+	// Core Web Vitals and paint metrics (JS mode only, when requested)
+	WebVitals *WebVitals `json:"web_vitals,omitempty"`
+
+	// Network and CPU throttling applied to the render (JS mode only)
+	Throttling *Throttling `json:"throttling,omitempty"`
+
+	// Emulated viewport and device (JS mode only)
+	Device *Device `json:"device,omitempty"`
+
+	// JavaScript dialogs the page opened (JS mode only)
+	Dialogs []Dialog `json:"dialogs,omitempty"`
+
+	// DOM snapshots at lifecycle milestones (JS mode only, when requested)
+	Snapshots *Snapshots `json:"snapshots,omitempty"`
+
 	// Console and errors
 	Console  []ConsoleMessage `json:"console,omitempty"`
 	JSErrors []JSError        `json:"js_errors,omitempty"`
@@ -147,22 +212,40 @@ type RenderData struct {
 	HTML string `json:"html,omitempty"`
 
 	ScreenshotData []byte `json:"-"` // Raw screenshot bytes, not serialized to JSON
+	PDFData        []byte `json:"-"` // Raw PDF bytes, not serialized to JSON
+	HARData        *HAR   `json:"-"` // HAR document, not serialized to JSON
 }
 
 // NetworkRequest represents a single network request
 type NetworkRequest struct {
-	ID         string  `json:"id"`
-	URL        string  `json:"url"`
-	Method     string  `json:"method"`
-	Status     int     `json:"status"`
-	Type       string  `json:"type"`
-	Size       int     `json:"size"`
-	Time       float64 `json:"time"` // seconds
-	IsInternal bool    `json:"is_internal"`
-	Blocked    bool    `json:"blocked,omitempty"`
-	Failed     bool    `json:"failed,omitempty"`
+	ID            string  `json:"id"`
+	URL           string  `json:"url"`
+	Method        string  `json:"method"`
+	Status        int     `json:"status"`
+	Type          string  `json:"type"`
+	Size          int     `json:"size"`
+	Time          float64 `json:"time"` // seconds
+	IsInternal    bool    `json:"is_internal"`
+	Blocked       bool    `json:"blocked,omitempty"`
+	BlockedReason string  `json:"blocked_reason,omitempty"` // "blocklist" or "ssrf"
+	Failed        bool    `json:"failed,omitempty"`
+
+	// Connection details, omitted when the request never reached the network
+	Timing    *RequestTiming `json:"timing,omitempty"`
+	Protocol  string         `json:"protocol,omitempty"` // h1, h2 or h3
+	RemoteIP  string         `json:"remote_ip,omitempty"`
+	FromCache bool           `json:"from_cache,omitempty"`
+
+	// XHR and fetch body, with capture_response_bodies
+	ResponseBody *ResponseBody `json:"response_body,omitempty"`
 }
 
+// Blocked reason values for NetworkRequest.BlockedReason
+const (
+	BlockedReasonBlocklist = "blocklist"
+	BlockedReasonSSRF      = "ssrf"
+)
+
 // LifecycleEvent represents a single lifecycle timing event
 type LifecycleEvent struct {
 	Event string  `json:"event"`
@@ -210,6 +293,8 @@ type ExtRenderData struct {
 	StatusCode      int               `json:"status_code"`
 	FinalURL        string            `json:"final_url"`
 	RedirectURL     string            `json:"redirect_url"`
+	RedirectChain   []RedirectHop     `json:"redirect_chain"`
+	RedirectFlags   *RedirectFlags    `json:"redirect_flags"`
 	CanonicalURL    string            `json:"canonical_url"`
 	PageSizeBytes   int               `json:"page_size_bytes"`
 	RenderTime      float64           `json:"render_time"`
@@ -226,6 +311,10 @@ type ExtRenderData struct {
 	TextHtmlRatio   float64           `json:"text_html_ratio"`
 	OpenGraph       map[string]string `json:"open_graph"`
 	HrefLangs       []HrefLang        `json:"hreflang"`
+	ResponseHeaders map[string]string `json:"response_headers"`
+
+	// Main document request (final redirect hop) with its timing breakdown
+	Document *NetworkRequest `json:"document,omitempty"`
 
 	// Opt-in content fields (pointer types: nil = omitted, non-nil = present)
 	HTML                *string           `json:"html,omitempty"`
@@ -237,6 +326,39 @@ type ExtRenderData struct {
 	Images              []Image           `json:"images,omitempty"`
 	StructuredData      []json.RawMessage `json:"structured_data,omitempty"`
 	Screenshot          *string           `json:"screenshot,omitempty"`
+	ScreenshotFormat    string            `json:"screenshot_format,omitempty"` // png, jpeg or webp
+	PDF                 *string           `json:"pdf,omitempty"`               // base64-encoded PDF
+	PDFID               string            `json:"pdf_id,omitempty"`            // Served by GET /api/pdf/{id}
+	HAR                 *HAR              `json:"har,omitempty"`
+	HARID               string            `json:"har_id,omitempty"` // Served by GET /api/har/{id}
+
+	// Opt-in diagnostics (JS mode only). Nil when not requested; a requested
+	// list is always present, as [] when empty.
+	Network   *[]NetworkRequest `json:"network,omitempty"`
+	Console   *[]ConsoleMessage `json:"console,omitempty"`
+	JSErrors  *[]JSError        `json:"js_errors,omitempty"`
+	Lifecycle *[]LifecycleEvent `json:"lifecycle,omitempty"`
+
+	// Scripted page action results, present when actions were requested
+	Actions []ActionResult `json:"actions,omitempty"`
+
+	// Scroll-to-bottom summary, present when scroll_to_bottom was requested
+	Scroll *ScrollResult `json:"scroll,omitempty"`
+
+	// Core Web Vitals and paint metrics, present when include_web_vitals was requested
+	WebVitals *WebVitals `json:"web_vitals,omitempty"`
+
+	// Network and CPU throttling applied to the render, present when requested
+	Throttling *Throttling `json:"throttling,omitempty"`
+
+	// Emulated viewport and device (JS mode only)
+	Device *Device `json:"device,omitempty"`
+
+	// JavaScript dialogs the page opened, present when there were any
+	Dialogs []Dialog `json:"dialogs,omitempty"`
+
+	// DOM snapshots at lifecycle milestones, present when include_snapshots was requested
+	Snapshots *Snapshots `json:"snapshots,omitempty"`
 }
 
 // ExtRenderResponse represents the external API response
@@ -245,3 +367,13 @@ type ExtRenderResponse struct {
 	Data    *ExtRenderData `json:"data,omitempty"`
 	Error   *RenderError   `json:"error,omitempty"`
 }
+
+// JoinHeaderValues joins the values of a repeated header for a flat header
+// map. Set-Cookie values contain commas (Expires), so they are joined with
+// "\n" as Chrome reports them; other headers are joined with ", ".
+func JoinHeaderValues(name string, values []string) string {
+	if strings.EqualFold(name, "Set-Cookie") {
+		return strings.Join(values, "\n")
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,139 @@
+package types
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// Response body capture defaults and limits
+const (
+	DefaultBodyMaxBytes      = 64 << 10 // Per body, larger bodies are truncated
+	MaxBodyMaxBytes          = 1 << 20
+	DefaultBodyMaxTotalBytes = 2 << 20 // Bodies stop being captured past this total
+	MaxBodyMaxTotalBytes     = 10 << 20
+	MaxBodyFilters           = 20 // Max url_patterns and mime_types entries each
+)
+
+// Reasons a response body was not captured
+const (
+	BodySkippedTotalBudget = "total_budget" // The total byte budget was used up
+	BodySkippedUnavailable = "unavailable"  // Chrome no longer had the body, e.g. the page navigated away
+)
+
+// ResponseBodyOptions configures capturing XHR and fetch response bodies
+// (JS mode only). Zero values use defaults.
+type ResponseBodyOptions struct {
+	URLPatterns   []string `json:"url_patterns"`    // Substrings, or globs with * matched against the full URL. Empty = all.
+	MimeTypes     []string `json:"mime_types"`      // e.g. application/json or text/*. Empty = all.
+	MaxBodyBytes  int      `json:"max_body_bytes"`  // Per body, larger bodies are truncated
+	MaxTotalBytes int      `json:"max_total_bytes"` // Total for all bodies of the render
+}
+
+// ResponseBody is a captured response body. Binary bodies are base64 encoded.
+type ResponseBody struct {
+	Body      string `json:"body,omitempty"`
+	Base64    bool   `json:"base64,omitempty"`
+	Size      int    `json:"size"`                // Decoded size before truncation
+	Truncated bool   `json:"truncated,omitempty"` // Cut to max_body_bytes or the remaining total budget
+	Skipped   string `json:"skipped,omitempty"`   // total_budget or unavailable
+}
+
+// ApplyDefaults sets default values for unset fields
+func (o *ResponseBodyOptions) ApplyDefaults() {
+	if o.MaxBodyBytes == 0 {
+		o.MaxBodyBytes = DefaultBodyMaxBytes
+	}
+	if o.MaxTotalBytes == 0 {
+		o.MaxTotalBytes = DefaultBodyMaxTotalBytes
+	}
+}
+
+// Validate checks filters and budgets. Call ApplyDefaults first.
+func (o *ResponseBodyOptions) Validate() error {
+	if len(o.URLPatterns) > MaxBodyFilters {
+		return fmt.Errorf("too many url_patterns (max %d)", MaxBodyFilters)
+	}
+	for i, p := range o.URLPatterns {
+		if strings.TrimSpace(p) == "" {
+			return fmt.Errorf("url_patterns[%d] is empty", i)
+		}
+	}
+	if len(o.MimeTypes) > MaxBodyFilters {
+		return fmt.Errorf("too many mime_types (max %d)", MaxBodyFilters)
+	}
+	for i, m := range o.MimeTypes {
+		major, minor, ok := strings.Cut(m, "/")
+		if !ok || major == "" || minor == "" || major == "*" {
+			return fmt.Errorf("invalid mime_types[%d]: %q (use type/subtype or type/*)", i, m)
+		}
+	}
+	if o.MaxBodyBytes < 1 || o.MaxBodyBytes > MaxBodyMaxBytes {
+		return fmt.Errorf("invalid max_body_bytes: %d (must be 1-%d)", o.MaxBodyBytes, MaxBodyMaxBytes)
+	}
+	if o.MaxTotalBytes < 1 || o.MaxTotalBytes > MaxBodyMaxTotalBytes {
+		return fmt.Errorf("invalid max_total_bytes: %d (must be 1-%d)", o.MaxTotalBytes, MaxBodyMaxTotalBytes)
+	}
+	return nil
+}
+
+// Matches reports whether a response passes the URL and MIME type filters
+func (o *ResponseBodyOptions) Matches(url, mimeType string) bool {
+	return o.matchesURL(url) && o.matchesMimeType(mimeType)
+}
+
+func (o *ResponseBodyOptions) matchesURL(url string) bool {
+	if len(o.URLPatterns) == 0 {
+		return true
+	}
+	for _, p := range o.URLPatterns {
+		if strings.Contains(p, "*") {
+			if matchWildcard(p, url) {
+				return true
+			}
+		} else if strings.Contains(url, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *ResponseBodyOptions) matchesMimeType(mimeType string) bool {
+	if len(o.MimeTypes) == 0 {
+		return true
+	}
+	if parsed, _, err := mime.ParseMediaType(mimeType); err == nil {
+		mimeType = parsed
+	}
+	mimeType = strings.ToLower(mimeType)
+	for _, m := range o.MimeTypes {
+		m = strings.ToLower(m)
+		if prefix, ok := strings.CutSuffix(m, "/*"); ok {
+			if strings.HasPrefix(mimeType, prefix+"/") {
+				return true
+			}
+		} else if mimeType == m {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWildcard matches s against a pattern where * stands for any run of
+// characters, including none
+func matchWildcard(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return len(s) >= len(last) && strings.HasSuffix(s, last)
+}
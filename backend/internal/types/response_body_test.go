@@ -0,0 +1,88 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResponseBodyOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ResponseBodyOptions
+		wantErr string
+	}{
+		{name: "defaults", opts: ResponseBodyOptions{}},
+		{name: "filters", opts: ResponseBodyOptions{URLPatterns: []string{"/api/"}, MimeTypes: []string{"application/json", "text/*"}}},
+		{name: "empty pattern", opts: ResponseBodyOptions{URLPatterns: []string{" "}}, wantErr: "url_patterns[0] is empty"},
+		{name: "bad mime type", opts: ResponseBodyOptions{MimeTypes: []string{"json"}}, wantErr: "invalid mime_types[0]"},
+		{name: "wildcard mime type", opts: ResponseBodyOptions{MimeTypes: []string{"*/*"}}, wantErr: "invalid mime_types[0]"},
+		{name: "body too large", opts: ResponseBodyOptions{MaxBodyBytes: MaxBodyMaxBytes + 1}, wantErr: "invalid max_body_bytes"},
+		{name: "negative total", opts: ResponseBodyOptions{MaxTotalBytes: -1}, wantErr: "invalid max_total_bytes"},
+		{name: "too many patterns", opts: ResponseBodyOptions{URLPatterns: make([]string, MaxBodyFilters+1)}, wantErr: "too many url_patterns"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.ApplyDefaults()
+			err := tt.opts.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResponseBodyOptions_Matches(t *testing.T) {
+	opts := &ResponseBodyOptions{
+		URLPatterns: []string{"/api/products", "https://*.example.com/v2/*.json"},
+		MimeTypes:   []string{"application/json", "text/*"},
+	}
+	tests := []struct {
+		url      string
+		mimeType string
+		want     bool
+	}{
+		{"https://shop.test/api/products?page=2", "application/json", true},
+		{"https://shop.test/api/products", "application/json; charset=utf-8", true},
+		{"https://shop.test/api/products", "TEXT/PLAIN", true},
+		{"https://shop.test/api/products", "image/png", false},
+		{"https://shop.test/api/users", "application/json", false},
+		{"https://cdn.example.com/v2/menu.json", "application/json", true},
+		{"https://cdn.example.com/v1/menu.json", "application/json", false},
+		{"https://cdn.example.com/v2/menu.json?x=1", "application/json", false},
+	}
+	for _, tt := range tests {
+		if got := opts.Matches(tt.url, tt.mimeType); got != tt.want {
+			t.Errorf("Matches(%q, %q) = %v, want %v", tt.url, tt.mimeType, got, tt.want)
+		}
+	}
+
+	all := &ResponseBodyOptions{}
+	if !all.Matches("https://any.test/x", "") {
+		t.Error("Matches() without filters = false, want true")
+	}
+}
+
+func TestMatchWildcard(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"*", "anything", true},
+		{"https://*", "https://a.test/", true},
+		{"*.json", "https://a.test/data.json", true},
+		{"*.json", "https://a.test/data.jsonp", false},
+		{"*/api/*/items", "https://a.test/api/v1/items", true},
+		{"a*a", "a", false},
+	}
+	for _, tt := range tests {
+		if got := matchWildcard(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("matchWildcard(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package types
+
+import "testing"
+
+func TestJoinHeaderValues(t *testing.T) {
+	cookies := []string{"a=1; Expires=Wed, 21 Oct 2026 07:28:00 GMT", "b=2"}
+	if got := JoinHeaderValues("Set-Cookie", cookies); got != cookies[0]+"\n"+cookies[1] {
+		t.Errorf("JoinHeaderValues(Set-Cookie) = %q, want values joined with newlines", got)
+	}
+	if got := JoinHeaderValues("set-cookie", cookies[1:]); got != "b=2" {
+		t.Errorf("JoinHeaderValues(set-cookie) = %q, want %q", got, "b=2")
+	}
+	if got := JoinHeaderValues("Vary", []string{"Accept", "Cookie"}); got != "Accept, Cookie" {
+		t.Errorf("JoinHeaderValues(Vary) = %q, want %q", got, "Accept, Cookie")
+	}
+}
@@ -0,0 +1,66 @@
+package types
+
+import "fmt"
+
+// Screenshot formats
+const (
+	ScreenshotFormatPNG  = "png"
+	ScreenshotFormatJPEG = "jpeg"
+	ScreenshotFormatWebP = "webp"
+)
+
+// Screenshot limits
+const (
+	DefaultScreenshotQuality   = 80
+	DefaultScreenshotMaxHeight = 10000 // px
+	MaxScreenshotHeight        = 16384 // Chrome's maximum texture size
+)
+
+// ScreenshotOptions configures the page screenshot (JS mode only). Zero
+// values capture the viewport as PNG.
+type ScreenshotOptions struct {
+	FullPage  bool   `json:"full_page"`
+	Selector  string `json:"selector"`   // Capture only the first element matching this CSS selector
+	Format    string `json:"format"`     // png, jpeg or webp
+	Quality   int    `json:"quality"`    // 1-100, jpeg and webp only
+	MaxHeight int    `json:"max_height"` // Crop full-page and element captures to this height, in CSS pixels
+}
+
+// ApplyDefaults sets default values for unset fields
+func (s *ScreenshotOptions) ApplyDefaults() {
+	if s.Format == "" {
+		s.Format = ScreenshotFormatPNG
+	}
+	if s.Quality == 0 && s.Format != ScreenshotFormatPNG {
+		s.Quality = DefaultScreenshotQuality
+	}
+	if s.MaxHeight == 0 {
+		s.MaxHeight = DefaultScreenshotMaxHeight
+	}
+}
+
+// Validate checks screenshot options. Call ApplyDefaults first.
+func (s *ScreenshotOptions) Validate() error {
+	switch s.Format {
+	case ScreenshotFormatPNG:
+		if s.Quality != 0 {
+			return fmt.Errorf("quality applies to jpeg and webp only")
+		}
+	case ScreenshotFormatJPEG, ScreenshotFormatWebP:
+		if s.Quality < 1 || s.Quality > 100 {
+			return fmt.Errorf("invalid quality: %d (must be 1-100)", s.Quality)
+		}
+	default:
+		return fmt.Errorf("invalid format: %q (must be png, jpeg or webp)", s.Format)
+	}
+	if s.FullPage && s.Selector != "" {
+		return fmt.Errorf("full_page and selector cannot be combined")
+	}
+	if len(s.Selector) > MaxWaitConditionLength {
+		return fmt.Errorf("selector exceeds %d characters", MaxWaitConditionLength)
+	}
+	if s.MaxHeight < 1 || s.MaxHeight > MaxScreenshotHeight {
+		return fmt.Errorf("invalid max_height: %d (must be 1-%d)", s.MaxHeight, MaxScreenshotHeight)
+	}
+	return nil
+}
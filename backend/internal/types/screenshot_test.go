@@ -0,0 +1,55 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScreenshotOptions_ApplyDefaults(t *testing.T) {
+	png := ScreenshotOptions{}
+	png.ApplyDefaults()
+	if png.Format != ScreenshotFormatPNG || png.Quality != 0 || png.MaxHeight != DefaultScreenshotMaxHeight {
+		t.Errorf("png defaults = %+v", png)
+	}
+
+	jpeg := ScreenshotOptions{Format: ScreenshotFormatJPEG}
+	jpeg.ApplyDefaults()
+	if jpeg.Quality != DefaultScreenshotQuality {
+		t.Errorf("jpeg Quality = %d, want %d", jpeg.Quality, DefaultScreenshotQuality)
+	}
+}
+
+func TestScreenshotOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ScreenshotOptions
+		wantErr string
+	}{
+		{name: "defaults", opts: ScreenshotOptions{}},
+		{name: "full page webp", opts: ScreenshotOptions{FullPage: true, Format: ScreenshotFormatWebP, Quality: 60, MaxHeight: MaxScreenshotHeight}},
+		{name: "element jpeg", opts: ScreenshotOptions{Selector: "#hero", Format: ScreenshotFormatJPEG}},
+		{name: "unknown format", opts: ScreenshotOptions{Format: "gif"}, wantErr: "invalid format"},
+		{name: "quality with png", opts: ScreenshotOptions{Quality: 50}, wantErr: "jpeg and webp only"},
+		{name: "quality out of range", opts: ScreenshotOptions{Format: ScreenshotFormatJPEG, Quality: 101}, wantErr: "invalid quality"},
+		{name: "full page and selector", opts: ScreenshotOptions{FullPage: true, Selector: "main"}, wantErr: "cannot be combined"},
+		{name: "selector too long", opts: ScreenshotOptions{Selector: strings.Repeat("a", MaxWaitConditionLength+1)}, wantErr: "selector exceeds"},
+		{name: "max_height too large", opts: ScreenshotOptions{FullPage: true, MaxHeight: MaxScreenshotHeight + 1}, wantErr: "invalid max_height"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.ApplyDefaults()
+			err := opts.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Scroll-to-bottom limits
+const (
+	DefaultScrollMaxSteps    = 10
+	MaxScrollSteps           = 50
+	DefaultScrollStepDelayMs = 500
+	MinScrollStepDelayMs     = 100
+	MaxScrollStepDelayMs     = 5000
+	DefaultScrollMaxHeight   = 20000 // px
+	MaxScrollHeight          = 100000
+)
+
+// Reasons scrolling stopped
+const (
+	ScrollStopBottom    = "bottom"     // Page end reached and no more content loaded
+	ScrollStopMaxSteps  = "max_steps"  // Step limit reached
+	ScrollStopMaxHeight = "max_height" // Page grew beyond max_height
+	ScrollStopTimeout   = "timeout"    // Render timeout used up
+	ScrollStopError     = "error"      // Script evaluation failed, e.g. the page navigated away
+)
+
+// ScrollOptions configures scrolling to the bottom of the page to trigger
+// lazy-loaded content (JS mode only). Zero values use defaults.
+type ScrollOptions struct {
+	MaxSteps    int `json:"max_steps"`
+	StepDelayMs int `json:"step_delay_ms"`
+	MaxHeight   int `json:"max_height"` // Stop once the document is this tall, in CSS pixels
+}
+
+// ApplyDefaults sets default values for unset fields
+func (s *ScrollOptions) ApplyDefaults() {
+	if s.MaxSteps == 0 {
+		s.MaxSteps = DefaultScrollMaxSteps
+	}
+	if s.StepDelayMs == 0 {
+		s.StepDelayMs = DefaultScrollStepDelayMs
+	}
+	if s.MaxHeight == 0 {
+		s.MaxHeight = DefaultScrollMaxHeight
+	}
+}
+
+// Validate checks scroll limits. Call ApplyDefaults first.
+func (s *ScrollOptions) Validate() error {
+	if s.MaxSteps < 1 || s.MaxSteps > MaxScrollSteps {
+		return fmt.Errorf("invalid max_steps: %d (must be 1-%d)", s.MaxSteps, MaxScrollSteps)
+	}
+	if s.StepDelayMs < MinScrollStepDelayMs || s.StepDelayMs > MaxScrollStepDelayMs {
+		return fmt.Errorf("invalid step_delay_ms: %d (must be %d-%d)", s.StepDelayMs, MinScrollStepDelayMs, MaxScrollStepDelayMs)
+	}
+	if s.MaxHeight < 1 || s.MaxHeight > MaxScrollHeight {
+		return fmt.Errorf("invalid max_height: %d (must be 1-%d)", s.MaxHeight, MaxScrollHeight)
+	}
+	return nil
+}
+
+// ScrollResult reports what scrolling to the bottom did
+type ScrollResult struct {
+	Steps         int     `json:"steps"`
+	InitialHeight int     `json:"initial_height"` // Document height before scrolling, CSS pixels
+	FinalHeight   int     `json:"final_height"`
+	StopReason    string  `json:"stop_reason"`
+	Duration      float64 `json:"duration"`   // seconds
+	NewImages     int     `json:"new_images"` // Images marked after_scroll
+	NewLinks      int     `json:"new_links"`  // Links marked after_scroll
+
+	// src and href attributes of loaded images and links present before
+	// scrolling, as written in the HTML
+	InitialImages []string `json:"-"`
+	InitialLinks  []string `json:"-"`
+}
+
+// MarkAfterScroll flags images and links that were not present (or, for
+// images, not loaded) before scrolling and counts them in the result. Both
+// sides are resolved against pageURL, the URL the parser resolved images and
+// links against, and normalized before comparing.
+func (s *ScrollResult) MarkAfterScroll(pageURL string, images []Image, links []Link) {
+	base, _ := url.Parse(pageURL)
+
+	initialImages := make(map[string]bool, len(s.InitialImages))
+	for _, src := range s.InitialImages {
+		initialImages[normalizeScrollURL(base, src)] = true
+	}
+	initialLinks := make(map[string]bool, len(s.InitialLinks))
+	for _, href := range s.InitialLinks {
+		initialLinks[normalizeScrollURL(base, href)] = true
+	}
+
+	s.NewImages, s.NewLinks = 0, 0
+	for i := range images {
+		if !initialImages[normalizeScrollURL(base, images[i].Src)] {
+			images[i].AfterScroll = true
+			s.NewImages++
+		}
+	}
+	for i := range links {
+		if !initialLinks[normalizeScrollURL(base, links[i].Href)] {
+			links[i].AfterScroll = true
+			s.NewLinks++
+		}
+	}
+}
+
+// normalizeScrollURL resolves rawURL against base, lowercases the host and
+// gives an empty path "/", so https://Example.com and https://example.com/
+// compare equal. Unparsable URLs are returned trimmed.
+func normalizeScrollURL(base *url.URL, rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	u.Host = strings.ToLower(u.Host)
+	if u.Host != "" && u.Path == "" && u.Opaque == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
@@ -0,0 +1,113 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScrollOptions_ApplyDefaults(t *testing.T) {
+	opts := ScrollOptions{MaxSteps: 3}
+	opts.ApplyDefaults()
+
+	if opts.MaxSteps != 3 {
+		t.Errorf("MaxSteps = %d, want 3", opts.MaxSteps)
+	}
+	if opts.StepDelayMs != DefaultScrollStepDelayMs {
+		t.Errorf("StepDelayMs = %d, want %d", opts.StepDelayMs, DefaultScrollStepDelayMs)
+	}
+	if opts.MaxHeight != DefaultScrollMaxHeight {
+		t.Errorf("MaxHeight = %d, want %d", opts.MaxHeight, DefaultScrollMaxHeight)
+	}
+}
+
+func TestScrollOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ScrollOptions
+		wantErr string
+	}{
+		{name: "defaults", opts: ScrollOptions{}},
+		{name: "max values", opts: ScrollOptions{MaxSteps: MaxScrollSteps, StepDelayMs: MaxScrollStepDelayMs, MaxHeight: MaxScrollHeight}},
+		{name: "too many steps", opts: ScrollOptions{MaxSteps: MaxScrollSteps + 1}, wantErr: "invalid max_steps"},
+		{name: "negative steps", opts: ScrollOptions{MaxSteps: -1}, wantErr: "invalid max_steps"},
+		{name: "delay too short", opts: ScrollOptions{StepDelayMs: MinScrollStepDelayMs - 1}, wantErr: "invalid step_delay_ms"},
+		{name: "delay too long", opts: ScrollOptions{StepDelayMs: MaxScrollStepDelayMs + 1}, wantErr: "invalid step_delay_ms"},
+		{name: "height too large", opts: ScrollOptions{MaxHeight: MaxScrollHeight + 1}, wantErr: "invalid max_height"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.ApplyDefaults()
+			err := opts.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScrollResult_MarkAfterScroll(t *testing.T) {
+	result := &ScrollResult{
+		InitialImages: []string{"https://example.com/hero.jpg"},
+		InitialLinks:  []string{"https://example.com/a", "https://example.com/b"},
+	}
+	images := []Image{
+		{Src: "https://example.com/hero.jpg"},
+		{Src: "https://example.com/item-1.jpg"},
+		{Src: "https://example.com/item-2.jpg"},
+	}
+	links := []Link{
+		{Href: "https://example.com/a"},
+		{Href: "https://example.com/b"},
+		{Href: "https://example.com/page/2"},
+	}
+
+	result.MarkAfterScroll("https://example.com/", images, links)
+
+	if result.NewImages != 2 {
+		t.Errorf("NewImages = %d, want 2", result.NewImages)
+	}
+	if result.NewLinks != 1 {
+		t.Errorf("NewLinks = %d, want 1", result.NewLinks)
+	}
+	if images[0].AfterScroll || !images[1].AfterScroll || !images[2].AfterScroll {
+		t.Errorf("image after_scroll flags = %v, %v, %v, want false, true, true",
+			images[0].AfterScroll, images[1].AfterScroll, images[2].AfterScroll)
+	}
+	if links[0].AfterScroll || links[1].AfterScroll || !links[2].AfterScroll {
+		t.Errorf("link after_scroll flags = %v, %v, %v, want false, false, true",
+			links[0].AfterScroll, links[1].AfterScroll, links[2].AfterScroll)
+	}
+}
+
+func TestScrollResult_MarkAfterScroll_Normalized(t *testing.T) {
+	// The baseline holds the src and href attributes as written in the HTML
+	result := &ScrollResult{
+		InitialImages: []string{"img/hero.jpg"},
+		InitialLinks:  []string{"https://Example.com", "/about"},
+	}
+	// The parser keeps absolute URLs raw and resolves relative ones
+	images := []Image{{Src: "https://example.com/blog/img/hero.jpg"}}
+	links := []Link{
+		{Href: "https://Example.com"},
+		{Href: "https://example.com/about"},
+		{Href: "https://example.com/"},
+	}
+
+	result.MarkAfterScroll("https://example.com/blog/post", images, links)
+
+	if result.NewImages != 0 || images[0].AfterScroll {
+		t.Errorf("NewImages = %d, want the relative baseline image matched", result.NewImages)
+	}
+	// https://Example.com, https://example.com/ and the resolved /about were all there
+	if result.NewLinks != 0 {
+		t.Errorf("NewLinks = %d, want 0 (links %+v)", result.NewLinks, links)
+	}
+}
@@ -0,0 +1,102 @@
+package types
+
+import "fmt"
+
+// SnapshotFinal is the milestone of the final HTML, taken after the wait
+// event, actions and scrolling
+const SnapshotFinal = "final"
+
+// Snapshot timeline elements
+const (
+	SnapshotElementTitle     = "title"
+	SnapshotElementH1        = "h1"
+	SnapshotElementCanonical = "canonical_url"
+	SnapshotElementWordCount = "word_count"
+	SnapshotElementLinkCount = "link_count"
+)
+
+// Snapshot timeline changes
+const (
+	SnapshotChangeAppeared = "appeared"
+	SnapshotChangeChanged  = "changed"
+	SnapshotChangeRemoved  = "removed"
+)
+
+// DefaultSnapshotEvents are the lifecycle milestones snapshotted when
+// SnapshotOptions.Events is empty
+var DefaultSnapshotEvents = []string{
+	"DOMContentLoaded",
+	"firstContentfulPaint",
+	"load",
+	"networkAlmostIdle",
+	"networkIdle",
+}
+
+// snapshotEvents are the Chrome lifecycle events a snapshot can be taken at
+var snapshotEvents = map[string]bool{
+	"DOMContentLoaded":     true,
+	"load":                 true,
+	"firstPaint":           true,
+	"firstContentfulPaint": true,
+	"firstImagePaint":      true,
+	"firstMeaningfulPaint": true,
+	"networkAlmostIdle":    true,
+	"networkIdle":          true,
+	"InteractiveTime":      true,
+}
+
+// SnapshotOptions configures DOM snapshots at lifecycle milestones (JS mode
+// only). Zero values use defaults.
+type SnapshotOptions struct {
+	Events []string `json:"events"` // Lifecycle events to snapshot, empty = DefaultSnapshotEvents
+}
+
+// ApplyDefaults sets default values for unset fields
+func (o *SnapshotOptions) ApplyDefaults() {
+	if len(o.Events) == 0 {
+		o.Events = append([]string(nil), DefaultSnapshotEvents...)
+	}
+}
+
+// Validate checks the snapshot events. Call ApplyDefaults first.
+func (o *SnapshotOptions) Validate() error {
+	seen := make(map[string]bool, len(o.Events))
+	for _, event := range o.Events {
+		if !snapshotEvents[event] {
+			return fmt.Errorf("invalid snapshot event: %q", event)
+		}
+		if seen[event] {
+			return fmt.Errorf("duplicate snapshot event: %q", event)
+		}
+		seen[event] = true
+	}
+	return nil
+}
+
+// DOMSnapshot summarizes the SEO elements of the DOM at a lifecycle milestone
+type DOMSnapshot struct {
+	Event        string   `json:"event"` // Lifecycle event, or "final"
+	Time         float64  `json:"time"`  // Seconds since render start
+	Title        string   `json:"title"`
+	H1           []string `json:"h1"`
+	WordCount    int      `json:"word_count"`
+	LinkCount    int      `json:"link_count"`
+	CanonicalURL string   `json:"canonical_url"`
+}
+
+// SnapshotChange is a key SEO element first seen, changed or gone at a
+// milestone
+type SnapshotChange struct {
+	Event   string  `json:"event"`
+	Time    float64 `json:"time"`
+	Element string  `json:"element"` // title, h1, canonical_url, word_count or link_count
+	Change  string  `json:"change"`  // appeared, changed or removed
+	From    string  `json:"from,omitempty"`
+	To      string  `json:"to,omitempty"`
+}
+
+// Snapshots is the DOM snapshot timeline of a JS render
+type Snapshots struct {
+	Milestones []DOMSnapshot    `json:"milestones"` // In time order, ending with "final"
+	Timeline   []SnapshotChange `json:"timeline"`
+}
@@ -0,0 +1,45 @@
+package types
+
+import "testing"
+
+func TestSnapshotOptions_ApplyDefaults(t *testing.T) {
+	o := &SnapshotOptions{}
+	o.ApplyDefaults()
+	if len(o.Events) != len(DefaultSnapshotEvents) {
+		t.Fatalf("Events = %v, want %v", o.Events, DefaultSnapshotEvents)
+	}
+
+	// The defaults are copied, not shared
+	o.Events[0] = "load"
+	if DefaultSnapshotEvents[0] != "DOMContentLoaded" {
+		t.Error("ApplyDefaults shares DefaultSnapshotEvents")
+	}
+
+	o = &SnapshotOptions{Events: []string{"networkIdle"}}
+	o.ApplyDefaults()
+	if len(o.Events) != 1 {
+		t.Errorf("Events = %v, want [networkIdle]", o.Events)
+	}
+}
+
+func TestSnapshotOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		events  []string
+		wantErr bool
+	}{
+		{"defaults", DefaultSnapshotEvents, false},
+		{"paint events", []string{"firstPaint", "firstMeaningfulPaint"}, false},
+		{"unknown event", []string{"domReady"}, true},
+		{"final is implicit", []string{SnapshotFinal}, true},
+		{"duplicate", []string{"load", "load"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &SnapshotOptions{Events: tt.events}
+			if err := o.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package types
+
+import "fmt"
+
+// Network profiles for RenderRequest.NetworkProfile
+const (
+	NetworkProfileOffline = "offline"
+	NetworkProfileSlow3G  = "slow-3g"
+	NetworkProfileFast3G  = "fast-3g"
+	NetworkProfile4G      = "4g"
+	NetworkProfileCustom  = "custom" // Uses NetworkConditions
+)
+
+// Throttling limits
+const (
+	MaxNetworkLatencyMs    = 10000
+	MaxNetworkThroughput   = 1000000 // kbps
+	MaxCPUThrottlingRate   = 20
+	minCPUThrottlingRate   = 1
+	kbpsToBytesPerSecond   = 1000.0 / 8
+	unlimitedCDPThroughput = -1
+)
+
+// NetworkConditions describes an emulated network. Throughput is in kilobits
+// per second, 0 = unlimited.
+type NetworkConditions struct {
+	Offline      bool    `json:"offline,omitempty"`
+	LatencyMs    float64 `json:"latency_ms"`
+	DownloadKbps float64 `json:"download_kbps"`
+	UploadKbps   float64 `json:"upload_kbps"`
+}
+
+// networkProfiles match the Chrome DevTools throttling presets
+var networkProfiles = map[string]NetworkConditions{
+	NetworkProfileOffline: {Offline: true},
+	NetworkProfileSlow3G:  {LatencyMs: 2000, DownloadKbps: 400, UploadKbps: 400},
+	NetworkProfileFast3G:  {LatencyMs: 562.5, DownloadKbps: 1440, UploadKbps: 675},
+	NetworkProfile4G:      {LatencyMs: 165, DownloadKbps: 8100, UploadKbps: 1350},
+}
+
+// DownloadBytesPerSecond returns the download throughput for CDP, -1 = unlimited
+func (n *NetworkConditions) DownloadBytesPerSecond() float64 {
+	return cdpThroughput(n.DownloadKbps)
+}
+
+// UploadBytesPerSecond returns the upload throughput for CDP, -1 = unlimited
+func (n *NetworkConditions) UploadBytesPerSecond() float64 {
+	return cdpThroughput(n.UploadKbps)
+}
+
+func cdpThroughput(kbps float64) float64 {
+	if kbps <= 0 {
+		return unlimitedCDPThroughput
+	}
+	return kbps * kbpsToBytesPerSecond
+}
+
+// Validate checks custom network conditions
+func (n *NetworkConditions) Validate() error {
+	if n.LatencyMs < 0 || n.LatencyMs > MaxNetworkLatencyMs {
+		return fmt.Errorf("invalid latency_ms: %v (must be 0-%d)", n.LatencyMs, MaxNetworkLatencyMs)
+	}
+	if n.DownloadKbps < 0 || n.DownloadKbps > MaxNetworkThroughput {
+		return fmt.Errorf("invalid download_kbps: %v (must be 0-%d)", n.DownloadKbps, MaxNetworkThroughput)
+	}
+	if n.UploadKbps < 0 || n.UploadKbps > MaxNetworkThroughput {
+		return fmt.Errorf("invalid upload_kbps: %v (must be 0-%d)", n.UploadKbps, MaxNetworkThroughput)
+	}
+	return nil
+}
+
+// Throttling is the network and CPU emulation applied to a JS render
+type Throttling struct {
+	NetworkProfile    string             `json:"network_profile,omitempty"`
+	Network           *NetworkConditions `json:"network,omitempty"`
+	CPUThrottlingRate float64            `json:"cpu_throttling_rate,omitempty"` // Slowdown factor, 1 = none
+}
+
+// ResolveThrottling validates the throttling options and resolves the network
+// profile to its conditions. Returns nil when nothing is throttled.
+func ResolveThrottling(profile string, custom *NetworkConditions, cpuRate float64) (*Throttling, error) {
+	if cpuRate != 0 && (cpuRate < minCPUThrottlingRate || cpuRate > MaxCPUThrottlingRate) {
+		return nil, fmt.Errorf("invalid cpu_throttling_rate: %v (must be %d-%d)", cpuRate, minCPUThrottlingRate, MaxCPUThrottlingRate)
+	}
+
+	t := &Throttling{NetworkProfile: profile}
+	if cpuRate > minCPUThrottlingRate {
+		t.CPUThrottlingRate = cpuRate
+	}
+
+	switch profile {
+	case "":
+		if custom != nil {
+			return nil, fmt.Errorf("network_conditions requires network_profile %q", NetworkProfileCustom)
+		}
+	case NetworkProfileCustom:
+		if custom == nil {
+			return nil, fmt.Errorf("network_profile %q requires network_conditions", NetworkProfileCustom)
+		}
+		if err := custom.Validate(); err != nil {
+			return nil, err
+		}
+		conditions := *custom
+		t.Network = &conditions
+	default:
+		conditions, ok := networkProfiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("invalid network_profile: %q (use offline, slow-3g, fast-3g, 4g or custom)", profile)
+		}
+		if custom != nil {
+			return nil, fmt.Errorf("network_conditions requires network_profile %q", NetworkProfileCustom)
+		}
+		t.Network = &conditions
+	}
+
+	if t.Network == nil && t.CPUThrottlingRate == 0 {
+		return nil, nil
+	}
+	return t, nil
+}
@@ -0,0 +1,93 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveThrottling(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		custom  *NetworkConditions
+		cpuRate float64
+		want    *Throttling
+		wantErr string
+	}{
+		{name: "nothing requested", want: nil},
+		{name: "cpu rate of 1 is no throttling", cpuRate: 1, want: nil},
+		{
+			name:    "preset",
+			profile: NetworkProfileSlow3G,
+			want: &Throttling{
+				NetworkProfile: NetworkProfileSlow3G,
+				Network:        &NetworkConditions{LatencyMs: 2000, DownloadKbps: 400, UploadKbps: 400},
+			},
+		},
+		{
+			name:    "offline with cpu",
+			profile: NetworkProfileOffline,
+			cpuRate: 4,
+			want: &Throttling{
+				NetworkProfile:    NetworkProfileOffline,
+				Network:           &NetworkConditions{Offline: true},
+				CPUThrottlingRate: 4,
+			},
+		},
+		{name: "cpu only", cpuRate: 6, want: &Throttling{CPUThrottlingRate: 6}},
+		{
+			name:    "custom",
+			profile: NetworkProfileCustom,
+			custom:  &NetworkConditions{LatencyMs: 300, DownloadKbps: 2000},
+			want: &Throttling{
+				NetworkProfile: NetworkProfileCustom,
+				Network:        &NetworkConditions{LatencyMs: 300, DownloadKbps: 2000},
+			},
+		},
+		{name: "unknown profile", profile: "5g", wantErr: "invalid network_profile"},
+		{name: "custom without conditions", profile: NetworkProfileCustom, wantErr: "requires network_conditions"},
+		{name: "conditions without custom", profile: NetworkProfile4G, custom: &NetworkConditions{}, wantErr: "requires network_profile"},
+		{name: "conditions without profile", custom: &NetworkConditions{}, wantErr: "requires network_profile"},
+		{name: "latency too high", profile: NetworkProfileCustom, custom: &NetworkConditions{LatencyMs: MaxNetworkLatencyMs + 1}, wantErr: "invalid latency_ms"},
+		{name: "negative throughput", profile: NetworkProfileCustom, custom: &NetworkConditions{UploadKbps: -1}, wantErr: "invalid upload_kbps"},
+		{name: "cpu rate below 1", cpuRate: 0.5, wantErr: "invalid cpu_throttling_rate"},
+		{name: "cpu rate too high", cpuRate: MaxCPUThrottlingRate + 1, wantErr: "invalid cpu_throttling_rate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveThrottling(tt.profile, tt.custom, tt.cpuRate)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveThrottling() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveThrottling() error = %v", err)
+			}
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("ResolveThrottling() = %+v, want %+v", got, tt.want)
+			}
+			if got == nil {
+				return
+			}
+			if got.NetworkProfile != tt.want.NetworkProfile || got.CPUThrottlingRate != tt.want.CPUThrottlingRate {
+				t.Errorf("ResolveThrottling() = %+v, want %+v", got, tt.want)
+			}
+			if (got.Network == nil) != (tt.want.Network == nil) || (got.Network != nil && *got.Network != *tt.want.Network) {
+				t.Errorf("Network = %+v, want %+v", got.Network, tt.want.Network)
+			}
+		})
+	}
+}
+
+func TestNetworkConditions_CDPThroughput(t *testing.T) {
+	n := &NetworkConditions{DownloadKbps: 1600}
+	if got := n.DownloadBytesPerSecond(); got != 200000 {
+		t.Errorf("DownloadBytesPerSecond() = %v, want 200000", got)
+	}
+	if got := n.UploadBytesPerSecond(); got != -1 {
+		t.Errorf("UploadBytesPerSecond() = %v, want -1 (unlimited)", got)
+	}
+}
@@ -0,0 +1,39 @@
+package types
+
+import "strings"
+
+// Negotiated protocol values for NetworkRequest.Protocol
+const (
+	ProtocolHTTP1 = "h1"
+	ProtocolHTTP2 = "h2"
+	ProtocolHTTP3 = "h3"
+)
+
+// RequestTiming breaks a request down into its phases, in seconds.
+// Phases that did not happen (reused connection, plain HTTP, cache hit) are 0.
+type RequestTiming struct {
+	DNS      float64 `json:"dns"`
+	Connect  float64 `json:"connect"` // TCP/QUIC connection setup, excluding TLS
+	TLS      float64 `json:"tls"`
+	TTFB     float64 `json:"ttfb"`     // From the request being sent to the first response byte
+	Download float64 `json:"download"` // From the first response byte to the end of the body
+}
+
+// NormalizeProtocol maps protocol names reported by Chrome or net/http
+// ("http/1.1", "HTTP/2.0", "h3-29", "quic") to h1, h2 or h3. Other values
+// (data, blob) are returned lowercased.
+func NormalizeProtocol(protocol string) string {
+	p := strings.ToLower(protocol)
+	switch {
+	case p == "":
+		return ""
+	case strings.HasPrefix(p, "http/1"):
+		return ProtocolHTTP1
+	case p == "h2" || strings.HasPrefix(p, "http/2"):
+		return ProtocolHTTP2
+	case strings.HasPrefix(p, "h3") || p == "quic" || strings.HasPrefix(p, "http/3"):
+		return ProtocolHTTP3
+	default:
+		return p
+	}
+}
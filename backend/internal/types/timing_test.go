@@ -0,0 +1,27 @@
+package types
+
+import "testing"
+
+func TestNormalizeProtocol(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"http/1.0", ProtocolHTTP1},
+		{"http/1.1", ProtocolHTTP1},
+		{"HTTP/1.1", ProtocolHTTP1},
+		{"h2", ProtocolHTTP2},
+		{"HTTP/2.0", ProtocolHTTP2},
+		{"h3", ProtocolHTTP3},
+		{"h3-29", ProtocolHTTP3},
+		{"quic", ProtocolHTTP3},
+		{"data", "data"},
+		{"Blob", "blob"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeProtocol(tt.in); got != tt.want {
+			t.Errorf("NormalizeProtocol(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package types
+
+// Web vitals collection limits
+const (
+	MaxLayoutShifts         = 50     // Layout shift entries reported per render (CLS counts all)
+	MaxLongTasks            = 50     // Long task entries reported per render (TBT counts all)
+	MaxShiftElements        = 5      // Shifted elements kept per layout shift
+	LongTaskBlockingMs      = 50.0   // Portion of a long task that does not count as blocking
+	CLSSessionGapMs         = 1000.0 // A CLS session window ends after this gap without shifts
+	CLSSessionMaxDurationMs = 5000.0 // or once it spans this long
+)
+
+// WebVitals holds Core Web Vitals and paint metrics measured during a JS
+// render. Times are milliseconds since navigation start; metrics the page
+// never produced (no contentful paint, no long task) are 0.
+type WebVitals struct {
+	LCPMs      float64 `json:"lcp_ms"`
+	LCPElement string  `json:"lcp_element,omitempty"` // CSS selector of the LCP element
+	LCPURL     string  `json:"lcp_url,omitempty"`     // Image URL when the LCP element is an image
+	LCPSize    int     `json:"lcp_size,omitempty"`    // Rendered area in CSS pixels
+
+	FPMs  float64 `json:"fp_ms"`
+	FCPMs float64 `json:"fcp_ms"`
+
+	CLS          float64       `json:"cls"`
+	LayoutShifts []LayoutShift `json:"layout_shifts,omitempty"`
+
+	TBTMs     float64    `json:"tbt_ms"` // Blocking time of long tasks after FCP
+	LongTasks []LongTask `json:"long_tasks,omitempty"`
+
+	// Chrome Performance domain metrics (TaskDuration, ScriptDuration,
+	// LayoutCount, JSHeapUsedSize...), as reported by Performance.getMetrics
+	PerformanceMetrics map[string]float64 `json:"performance_metrics,omitempty"`
+}
+
+// LayoutShift is a single layout-shift entry
+type LayoutShift struct {
+	TimeMs         float64  `json:"time_ms"`
+	Value          float64  `json:"value"`
+	HadRecentInput bool     `json:"had_recent_input,omitempty"` // Excluded from CLS
+	Elements       []string `json:"elements,omitempty"`         // CSS selectors of the shifted elements
+}
+
+// LongTask is a main thread task that took longer than 50ms
+type LongTask struct {
+	StartMs    float64 `json:"start_ms"`
+	DurationMs float64 `json:"duration_ms"`
+}